@@ -0,0 +1,78 @@
+// Command adminkey mints admin API keys directly against the database.
+// It is the bootstrap path for the first key, since the HTTP endpoints require an existing admin key.
+//
+// Usage:
+//
+//	go run ./cmd/adminkey -name "ops bootstrap" -role admin
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"go-backend/internal/apps/admin/models"
+	"go-backend/internal/apps/admin/repository"
+	"go-backend/internal/apps/admin/service"
+	"go-backend/internal/common/database"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	name := flag.String("name", "", "human-readable name for the key (required)")
+	role := flag.String("role", "admin", "role for the key: admin, support or readonly")
+	ttl := flag.Duration("ttl", 0, "optional key lifetime, e.g. 720h (default: no expiry)")
+	flag.Parse()
+
+	if *name == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// Load environment variables from appropriate file
+	env := getEnv("GO_ENV", "local")
+	envFile := ".env." + env
+	if err := godotenv.Load(envFile); err != nil {
+		if err := godotenv.Load(); err != nil {
+			log.Printf("No %s or .env file found, using environment variables", envFile)
+		}
+	}
+
+	db, err := database.NewConnection(database.Config{
+		Host:     getEnv("DB_HOST", "localhost"),
+		Port:     getEnv("DB_PORT", "5432"),
+		User:     getEnv("DB_USER", "postgres"),
+		Password: getEnv("DB_PASSWORD", "postgres"),
+		DBName:   getEnv("DB_NAME", "go_backend"),
+		SSLMode:  getEnv("DB_SSL_MODE", "disable"),
+	})
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	req := models.CreateAPIKeyRequest{Name: *name, Role: *role}
+	if *ttl > 0 {
+		expiresAt := time.Now().Add(*ttl)
+		req.ExpiresAt = &expiresAt
+	}
+
+	keySvc := service.NewAPIKeyService(repository.NewAPIKeyRepository(db))
+	created, err := keySvc.CreateAPIKey(req)
+	if err != nil {
+		log.Fatalf("Failed to create api key: %v", err)
+	}
+
+	fmt.Printf("Created %s key %q (id %s)\n", created.Role, created.Name, created.ID)
+	fmt.Printf("API key (shown only once): %s\n", created.Key)
+}
+
+// getEnv retrieves environment variable or returns default value
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
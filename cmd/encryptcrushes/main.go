@@ -0,0 +1,75 @@
+// Command encryptcrushes encrypts crush target identifiers that are still stored in clear.
+// The server's encrypt_legacy_crushes job does the same in the background; run this to finish the
+// backfill right away or when the scheduler is disabled. It is safe to re-run.
+//
+// Usage:
+//
+//	go run ./cmd/encryptcrushes -batch 500
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"go-backend/internal/apps/crush/repository"
+	"go-backend/internal/common/database"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	batch := flag.Int("batch", 500, "number of crushes encrypted per transaction")
+	flag.Parse()
+
+	if *batch <= 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// Load environment variables from appropriate file
+	env := getEnv("GO_ENV", "local")
+	envFile := ".env." + env
+	if err := godotenv.Load(envFile); err != nil {
+		if err := godotenv.Load(); err != nil {
+			log.Printf("No %s or .env file found, using environment variables", envFile)
+		}
+	}
+
+	db, err := database.NewConnection(database.Config{
+		Host:     getEnv("DB_HOST", "localhost"),
+		Port:     getEnv("DB_PORT", "5432"),
+		User:     getEnv("DB_USER", "postgres"),
+		Password: getEnv("DB_PASSWORD", "postgres"),
+		DBName:   getEnv("DB_NAME", "go_backend"),
+		SSLMode:  getEnv("DB_SSL_MODE", "disable"),
+	})
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	repo := repository.NewCrushRepository(db)
+	total := 0
+	for {
+		n, err := repo.EncryptLegacyBatch(*batch)
+		if err != nil {
+			log.Fatalf("Failed to encrypt crushes after %d: %v", total, err)
+		}
+		if n == 0 {
+			break
+		}
+		total += n
+		fmt.Printf("Encrypted %d crushes\n", total)
+	}
+
+	fmt.Printf("Done: %d crushes encrypted\n", total)
+}
+
+// getEnv retrieves environment variable or returns default value
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,80 @@
+// Command indexidentifiers fills the blind indexes of users' phones and social handles.
+// The server's index_identifiers job does the same in the background; run this to finish the
+// backfill right away or when the scheduler is disabled. It is safe to re-run.
+//
+// Usage:
+//
+//	go run ./cmd/indexidentifiers -batch 500
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"go-backend/internal/apps/user/repository"
+	"go-backend/internal/common/database"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	batch := flag.Int("batch", 500, "number of rows indexed per transaction")
+	flag.Parse()
+
+	if *batch <= 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// Load environment variables from appropriate file
+	env := getEnv("GO_ENV", "local")
+	envFile := ".env." + env
+	if err := godotenv.Load(envFile); err != nil {
+		if err := godotenv.Load(); err != nil {
+			log.Printf("No %s or .env file found, using environment variables", envFile)
+		}
+	}
+
+	db, err := database.NewConnection(database.Config{
+		Host:     getEnv("DB_HOST", "localhost"),
+		Port:     getEnv("DB_PORT", "5432"),
+		User:     getEnv("DB_USER", "postgres"),
+		Password: getEnv("DB_PASSWORD", "postgres"),
+		DBName:   getEnv("DB_NAME", "go_backend"),
+		SSLMode:  getEnv("DB_SSL_MODE", "disable"),
+	})
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	users := run("users", *batch, repository.NewUserRepository(db).IndexPhoneBatch)
+	identities := run("identities", *batch, repository.NewIdentityRepository(db).IndexValueBatch)
+
+	fmt.Printf("Done: %d users and %d identities indexed\n", users, identities)
+}
+
+// run calls indexBatch until nothing is left to index and returns the number of rows indexed
+func run(name string, batch int, indexBatch func(limit int) (int, error)) int {
+	total := 0
+	for {
+		n, err := indexBatch(batch)
+		if err != nil {
+			log.Fatalf("Failed to index %s after %d: %v", name, total, err)
+		}
+		if n == 0 {
+			return total
+		}
+		total += n
+		fmt.Printf("Indexed %d %s\n", total, name)
+	}
+}
+
+// getEnv retrieves environment variable or returns default value
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -1,12 +1,26 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
+	"strconv"
 
+	adminHandler "go-backend/internal/apps/admin/handler"
+	adminRepository "go-backend/internal/apps/admin/repository"
+	adminService "go-backend/internal/apps/admin/service"
+	authHandler "go-backend/internal/apps/auth/handler"
+	authRepository "go-backend/internal/apps/auth/repository"
+	authService "go-backend/internal/apps/auth/service"
 	crushHandler "go-backend/internal/apps/crush/handler"
 	crushRepository "go-backend/internal/apps/crush/repository"
 	crushService "go-backend/internal/apps/crush/service"
+	moderationHandler "go-backend/internal/apps/moderation/handler"
+	moderationRepository "go-backend/internal/apps/moderation/repository"
+	moderationService "go-backend/internal/apps/moderation/service"
+	notificationHandler "go-backend/internal/apps/notification/handler"
+	notificationRepository "go-backend/internal/apps/notification/repository"
+	notificationService "go-backend/internal/apps/notification/service"
 	otpHandler "go-backend/internal/apps/otp/handler"
 	otpRepository "go-backend/internal/apps/otp/repository"
 	otpService "go-backend/internal/apps/otp/service"
@@ -21,6 +35,7 @@ import (
 	userService "go-backend/internal/apps/user/service"
 	"go-backend/internal/common/database"
 	"go-backend/internal/common/middleware"
+	"go-backend/internal/common/scheduler"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -53,6 +68,13 @@ func main() {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
 
+	// Initialize admin API key dependencies
+	// The first key is minted with `make admin-key` (cmd/adminkey)
+	apiKeyRepo := adminRepository.NewAPIKeyRepository(db)
+	apiKeySvc := adminService.NewAPIKeyService(apiKeyRepo)
+	apiKeyH := adminHandler.NewAPIKeyHandler(apiKeySvc)
+	adminGuard := middleware.NewAdminGuard(apiKeySvc)
+
 	// Initialize Razorpay dependencies
 	// Note: With multi-client support, Razorpay credentials are now stored per config in the database
 	// The old environment variables are no longer used for subscription operations
@@ -61,54 +83,235 @@ func main() {
 	configH := configHandler.NewRazorpayConfigHandler(configSvc)
 
 	subscriptionRepo := razorpayRepository.NewSubscriptionRepository(db)
+	webhookEventRepo := razorpayRepository.NewWebhookEventRepository(db)
 	subscriptionService := razorpayService.NewSubscriptionService(
 		subscriptionRepo,
 		configRepo,
+		webhookEventRepo,
 	)
 	subscriptionHandler := razorpayHandler.NewSubscriptionHandler(subscriptionService)
 
 	// Initialize repositories
 	userRepo := userRepository.NewUserRepository(db)
 	crushRepo := crushRepository.NewCrushRepository(db)
+	analyticsRepo := crushRepository.NewAnalyticsRepository(db)
+	totpRepo := userRepository.NewTOTPRepository(db)
+	identityRepo := userRepository.NewIdentityRepository(db)
+	notificationRepo := notificationRepository.NewNotificationRepository(db)
+	blockRepo := moderationRepository.NewBlockRepository(db)
+	reportRepo := moderationRepository.NewReportRepository(db)
 
 	// Initialize services
-	crushSvc := crushService.NewCrushService(crushRepo, userRepo)
-	userSvc := userService.NewUserService(userRepo, crushRepo)
+	notificationSvc := notificationService.NewNotificationService(notificationRepo, userRepo)
+	totpSvc := userService.NewTOTPService(totpRepo, userRepo)
+	identitySvc := userService.NewIdentityService(identityRepo, userRepo)
+	blockSvc := moderationService.NewBlockService(blockRepo, userRepo, crushRepo)
+	moderationSvc := moderationService.NewModerationService(reportRepo, userRepo, crushRepo, notificationSvc)
+	analyticsSvc := crushService.NewAnalyticsService(analyticsRepo)
 
 	// Initialize handlers
-	crushH := crushHandler.NewCrushHandler(crushSvc)
-	userH := userHandler.NewUserHandler(userSvc)
+	totpH := userHandler.NewTOTPHandler(totpSvc)
+	identityH := userHandler.NewIdentityHandler(identitySvc)
+	notificationH := notificationHandler.NewNotificationHandler(notificationSvc)
+	blockH := moderationHandler.NewBlockHandler(blockSvc)
+	moderationH := moderationHandler.NewModerationHandler(moderationSvc)
+	analyticsH := crushHandler.NewAnalyticsHandler(analyticsSvc)
+
+	// Initialize auth (session) dependencies
+	refreshTokenRepo := authRepository.NewRefreshTokenRepository(db)
+	authSvc := authService.NewAuthService(refreshTokenRepo, userRepo, totpSvc)
+	authH := authHandler.NewAuthHandler(authSvc)
+	requireAuth := middleware.RequireAuth(authSvc)
 
 	// Initialize OTP dependencies
-	// Use AuthKey provider for production, no-op for local/dev
-	var otpProvider otpService.OTPProvider
+	// Phone OTP providers are registered when configured; SMS_ROUTING picks and orders them per app and country
+	// and sets the channel fallback order (e.g. whatsapp -> sms)
+	smsProviders := map[string]otpService.OTPProvider{}
+	var defaultSMSRoute otpService.SMSRoute
+	if authKey, authKeyTemplateID := getEnv("AUTHKEY_API_KEY", ""), getEnv("AUTHKEY_TEMPLATE_ID", ""); authKey != "" && authKeyTemplateID != "" {
+		smsProviders[otpService.ProviderAuthKey] = otpService.NewAuthKeyProvider(authKey, authKeyTemplateID)
+		defaultSMSRoute = append(defaultSMSRoute, otpService.ProviderAuthKey)
+	}
+	if twilioSID := getEnv("TWILIO_ACCOUNT_SID", ""); twilioSID != "" {
+		smsProviders[otpService.ProviderTwilio] = otpService.NewTwilioProvider(otpService.TwilioConfig{
+			AccountSID: twilioSID,
+			AuthToken:  getEnv("TWILIO_AUTH_TOKEN", ""),
+			From:       getEnv("TWILIO_FROM", ""),
+			BaseURL:    getEnv("TWILIO_BASE_URL", ""),
+		})
+		defaultSMSRoute = append(defaultSMSRoute, otpService.ProviderTwilio)
+	}
+	if whatsAppToken := getEnv("WHATSAPP_ACCESS_TOKEN", ""); whatsAppToken != "" {
+		smsProviders[otpService.ProviderWhatsApp] = otpService.NewWhatsAppProvider(otpService.WhatsAppConfig{
+			AccessToken:   whatsAppToken,
+			PhoneNumberID: getEnv("WHATSAPP_PHONE_NUMBER_ID", ""),
+			TemplateName:  getEnv("WHATSAPP_TEMPLATE_NAME", ""),
+			LanguageCode:  getEnv("WHATSAPP_LANGUAGE_CODE", ""),
+			BaseURL:       getEnv("WHATSAPP_BASE_URL", ""),
+		})
+		defaultSMSRoute = append(defaultSMSRoute, otpService.ProviderWhatsApp)
+	}
 	if env == "prod" {
-		authKey := getEnv("AUTHKEY_API_KEY", "")
-		authKeyTemplateID := getEnv("AUTHKEY_TEMPLATE_ID", "")
-
-		if authKey == "" || authKeyTemplateID == "" {
-			log.Fatal("AUTHKEY_API_KEY and AUTHKEY_TEMPLATE_ID are required in production")
+		if len(smsProviders) == 0 {
+			log.Fatal("At least one SMS provider (AUTHKEY_*, TWILIO_* or WHATSAPP_*) is required in production")
 		}
+	} else {
+		// Local/dev sends nothing unless SMS_ROUTING opts in to a real provider
+		// Plaintext OTPs are only written to logs in local mode
+		smsProviders[otpService.ProviderNoOp] = otpService.NewNoOpProvider(env == "local")
+		defaultSMSRoute = otpService.SMSRoute{otpService.ProviderNoOp}
+	}
 
-		otpProvider = otpService.NewAuthKeyProvider(authKey, authKeyTemplateID)
-		log.Println("Using AuthKey SMS provider (production mode)")
+	smsRouting, err := otpService.ParseSMSRouting(getEnv("SMS_ROUTING", ""), defaultSMSRoute)
+	if err != nil {
+		log.Fatalf("Invalid SMS_ROUTING: %v", err)
+	}
+	otpProvider, err := otpService.NewRoutingProvider(smsProviders, smsRouting)
+	if err != nil {
+		log.Fatalf("Failed to configure SMS routing: %v", err)
+	}
+	log.Printf("Using SMS providers %v (default route)", smsRouting.Default)
+
+	// Crush invites reuse the SMS routing; CRUSH_INVITES enables them per app, e.g. {"apps":{"app":{...}}}
+	inviteConfigs, err := crushService.ParseInviteConfigs(getEnv("CRUSH_INVITES", ""))
+	if err != nil {
+		log.Fatalf("Invalid CRUSH_INVITES: %v", err)
+	}
+	inviteRepo := crushRepository.NewInviteRepository(db)
+	inviteSvc := crushService.NewInviteService(inviteRepo, userRepo, otpProvider, inviteConfigs)
+	// CRUSH_POLICIES sets per-app crush rules such as auto-expiry and subscription tiers,
+	// e.g. {"apps":{"app":{"expire_after_days":90,"free_crush_limit":3,"subscriber_crush_limit":10}}}
+	crushPolicies, err := crushService.ParseCrushPolicies(getEnv("CRUSH_POLICIES", ""))
+	if err != nil {
+		log.Fatalf("Invalid CRUSH_POLICIES: %v", err)
+	}
+	crushSvc := crushService.NewCrushService(crushRepo, userRepo, identityRepo, blockRepo, notificationSvc, inviteSvc, crushPolicies, subscriptionRepo)
+	crushH := crushHandler.NewCrushHandler(crushSvc)
+	inviteH := crushHandler.NewInviteHandler(inviteSvc, getEnv("TWILIO_AUTH_TOKEN", ""), getEnv("TWILIO_INBOUND_URL", ""))
+
+	// Use SMTP for email OTPs when configured (required in production), no-op otherwise
+	var emailProvider otpService.EmailProvider
+	if smtpHost := getEnv("SMTP_HOST", ""); smtpHost != "" {
+		smtpPort, err := strconv.Atoi(getEnv("SMTP_PORT", "587"))
+		if err != nil {
+			log.Fatalf("Invalid SMTP_PORT: %v", err)
+		}
+		emailProvider, err = otpService.NewSMTPEmailProvider(otpService.SMTPConfig{
+			Host:     smtpHost,
+			Port:     smtpPort,
+			Username: getEnv("SMTP_USERNAME", ""),
+			Password: getEnv("SMTP_PASSWORD", ""),
+			From:     getEnv("SMTP_FROM", ""),
+		})
+		if err != nil {
+			log.Fatalf("Failed to configure SMTP email provider: %v", err)
+		}
+		log.Printf("Using SMTP email provider (%s:%d)", smtpHost, smtpPort)
+	} else if env == "prod" {
+		log.Fatal("SMTP_HOST and SMTP_FROM are required in production")
 	} else {
-		otpProvider = otpService.NewNoOpProvider()
-		log.Println("Using No-Op provider - OTP will be logged only (local/dev mode)")
+		emailProvider = otpService.NewNoOpEmailProvider(env == "local")
+		log.Println("Using No-Op email provider - emails will not be sent (local/dev mode)")
+	}
+
+	emailTemplates, err := otpService.NewEmailTemplates(getEnv("EMAIL_TEMPLATE_DIR", ""))
+	if err != nil {
+		log.Fatalf("Failed to load email templates: %v", err)
+	}
+
+	// Per-app attempt limits and send throttles, e.g. {"default":{...},"apps":{"app":{...}}}
+	// Policies stored via /otp/policies take precedence over OTP_POLICIES
+	envOTPPolicies, err := otpService.ParseOTPPolicies(getEnv("OTP_POLICIES", ""))
+	if err != nil {
+		log.Fatalf("Invalid OTP_POLICIES: %v", err)
 	}
+	otpPolicyRepo := otpRepository.NewOTPPolicyRepository(db)
+	otpPolicies := otpService.NewDBPolicyResolver(otpPolicyRepo, envOTPPolicies)
+	otpPolicySvc := otpService.NewOTPPolicyService(otpPolicyRepo, envOTPPolicies)
 
 	phoneOTPRepo := otpRepository.NewPhoneOTPRepository(db)
 	emailOTPRepo := otpRepository.NewEmailOTPRepository(db)
-	phoneOTPSvc := otpService.NewPhoneOTPService(phoneOTPRepo, otpProvider)
-	emailOTPSvc := otpService.NewEmailOTPService(emailOTPRepo)
+	otpSendLogRepo := otpRepository.NewOTPSendLogRepository(db)
+	phoneOTPSvc := otpService.NewPhoneOTPService(phoneOTPRepo, otpSendLogRepo, otpPolicies, otpProvider, authSvc)
+	emailOTPSvc := otpService.NewEmailOTPService(emailOTPRepo, otpSendLogRepo, otpPolicies, emailProvider, emailTemplates, authSvc)
 	phoneOTPH := otpHandler.NewPhoneOTPHandler(phoneOTPSvc)
 	emailOTPH := otpHandler.NewEmailOTPHandler(emailOTPSvc)
+	otpPolicyH := otpHandler.NewOTPPolicyHandler(otpPolicySvc)
+
+	magicLinkRepo := otpRepository.NewMagicLinkRepository(db)
+	magicLinkSvc := otpService.NewMagicLinkService(magicLinkRepo, otpSendLogRepo, otpPolicies, emailProvider, emailTemplates, authSvc)
+	magicLinkH := otpHandler.NewMagicLinkHandler(magicLinkSvc)
+
+	// Users confirm a new phone or email with an OTP before switching to it
+	userSvc := userService.NewUserService(userRepo, crushRepo, otpService.NewContactVerifier(phoneOTPSvc, emailOTPSvc))
+	userH := userHandler.NewUserHandler(userSvc)
+
+	// Background jobs run on every replica; an advisory lock per job ensures only one runs each slot
+	// Set JOBS_ENABLED=false to run a replica without the scheduler
+	otpCleanupSvc := otpService.NewOTPCleanupService(phoneOTPRepo, emailOTPRepo, otpSendLogRepo, magicLinkRepo)
+	identifierIndexSvc := userService.NewIdentifierIndexService(userRepo, identityRepo)
+	jobScheduler := scheduler.New(db)
+	jobs := []scheduler.Job{
+		{
+			Name:     "purge_expired_otps",
+			Schedule: getEnv("JOB_PURGE_OTPS_SCHEDULE", "*/15 * * * *"),
+			Run: func(ctx context.Context) (int64, error) {
+				return otpCleanupSvc.PurgeExpired()
+			},
+		},
+		{
+			Name:     "expire_abandoned_checkouts",
+			Schedule: getEnv("JOB_EXPIRE_CHECKOUTS_SCHEDULE", "0 * * * *"),
+			Run: func(ctx context.Context) (int64, error) {
+				return subscriptionService.ExpireAbandonedCheckouts()
+			},
+		},
+		{
+			// Crushes created before target encryption only match once encrypted; a no-op once none are left
+			Name:     "encrypt_legacy_crushes",
+			Schedule: getEnv("JOB_ENCRYPT_CRUSHES_SCHEDULE", "*/5 * * * *"),
+			Run: func(ctx context.Context) (int64, error) {
+				return crushSvc.EncryptLegacyCrushes(ctx)
+			},
+		},
+		{
+			// Users and identities created before blind indexing are left out of crush analytics until indexed
+			Name:     "index_identifiers",
+			Schedule: getEnv("JOB_INDEX_IDENTIFIERS_SCHEDULE", "*/5 * * * *"),
+			Run: func(ctx context.Context) (int64, error) {
+				return identifierIndexSvc.IndexIdentifiers(ctx)
+			},
+		},
+		{
+			Name:     "expire_crushes",
+			Schedule: getEnv("JOB_EXPIRE_CRUSHES_SCHEDULE", "0 * * * *"),
+			Run: func(ctx context.Context) (int64, error) {
+				return crushSvc.ExpireCrushes()
+			},
+		},
+	}
+	for _, job := range jobs {
+		if err := jobScheduler.Register(job); err != nil {
+			log.Fatalf("Failed to register job: %v", err)
+		}
+	}
+	if getEnv("JOBS_ENABLED", "true") == "true" {
+		jobScheduler.Start(context.Background())
+	}
+	jobH := adminHandler.NewJobHandler(jobScheduler)
 
 	// Setup Gin router
 	ginMode := getEnv("GIN_MODE", "release")
 	gin.SetMode(ginMode)
 
-	router := gin.Default()
+	// Request logs redact tokens passed in query strings (the notification stream's access token, magic link tokens)
+	router := gin.New()
+	router.Use(middleware.Logger(), gin.Recovery())
+
+	// Client IPs (used by the per-IP OTP send caps) only come from forwarding headers of configured proxies
+	if err := middleware.SetupTrustedProxies(router); err != nil {
+		log.Fatalf("Failed to configure trusted proxies: %v", err)
+	}
 
 	// Health check endpoint (before CORS middleware to allow access from any client)
 	router.GET("/health", func(c *gin.Context) {
@@ -119,7 +322,7 @@ func main() {
 	})
 
 	// Razorpay config creation endpoint (before CORS middleware for admin access)
-	router.POST("/api/v1/razorpay-configs", configH.CreateRazorpayConfig)
+	router.POST("/api/v1/razorpay-configs", adminGuard(middleware.RoleAdmin), configH.CreateRazorpayConfig)
 
 	// Setup CORS middleware
 	router.Use(middleware.SetupCORS(env))
@@ -128,19 +331,34 @@ func main() {
 	v1 := router.Group("/api/v1")
 	{
 		// Register Razorpay Config management routes
-		configHandler.RegisterRazorpayConfigRoutes(v1, configH)
+		configHandler.RegisterRazorpayConfigRoutes(v1, configH, adminGuard)
 
 		// Register Razorpay subscription routes
-		razorpayHandler.RegisterSubscriptionRoutes(v1, subscriptionHandler)
+		razorpayHandler.RegisterSubscriptionRoutes(v1, subscriptionHandler, requireAuth, adminGuard)
 
 		// Register User management routes
-		userHandler.RegisterUserRoutes(v1, userH)
+		userHandler.RegisterUserRoutes(v1, userH, totpH, identityH, requireAuth, adminGuard)
 
 		// Register OTP routes
-		otpHandler.RegisterOTPRoutes(v1, phoneOTPH, emailOTPH)
+		otpHandler.RegisterOTPRoutes(v1, phoneOTPH, emailOTPH, magicLinkH, otpPolicyH, adminGuard)
+
+		// Register session (refresh/logout) routes
+		authHandler.RegisterAuthRoutes(v1, authH)
+
+		// Register admin API key management routes
+		adminHandler.RegisterAPIKeyRoutes(v1, apiKeyH, adminGuard)
+
+		// Register background job status routes
+		adminHandler.RegisterJobRoutes(v1, jobH, adminGuard)
 
 		// Register Crush Connect routes
-		crushHandler.RegisterCrushRoutes(v1, crushH)
+		crushHandler.RegisterCrushRoutes(v1, crushH, inviteH, analyticsH, requireAuth, adminGuard)
+
+		// Register notification inbox and stream routes
+		notificationHandler.RegisterNotificationRoutes(v1, notificationH, requireAuth)
+
+		// Register block, abuse report and moderation queue routes
+		moderationHandler.RegisterModerationRoutes(v1, blockH, moderationH, requireAuth, adminGuard)
 
 		// Future apps can register their routes here
 		// Example: handler.RegisterUserRoutes(v1, userHandler)
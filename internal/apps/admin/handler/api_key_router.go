@@ -0,0 +1,17 @@
+package handler
+
+import (
+	"go-backend/internal/common/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterAPIKeyRoutes registers admin API key management routes (admin role only)
+func RegisterAPIKeyRoutes(router *gin.RouterGroup, handler *APIKeyHandler, adminGuard middleware.AdminGuard) {
+	keys := router.Group("/admin/api-keys", adminGuard(middleware.RoleAdmin))
+	{
+		keys.POST("", handler.CreateAPIKey)
+		keys.GET("", handler.ListAPIKeys)
+		keys.DELETE("/:id", handler.RevokeAPIKey)
+	}
+}
@@ -0,0 +1,16 @@
+package handler
+
+import (
+	"go-backend/internal/common/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterJobRoutes registers background job status routes (any admin role)
+func RegisterJobRoutes(router *gin.RouterGroup, handler *JobHandler, adminGuard middleware.AdminGuard) {
+	jobs := router.Group("/admin/jobs", adminGuard())
+	{
+		jobs.GET("", handler.ListJobs)
+		jobs.GET("/runs", handler.ListJobRuns)
+	}
+}
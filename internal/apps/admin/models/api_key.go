@@ -0,0 +1,74 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// APIKey represents an admin credential used to access management endpoints
+// Only the SHA-256 hash of the key is persisted; the prefix helps identify keys in listings
+type APIKey struct {
+	ID         uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Name       string         `gorm:"not null;size:100" json:"name"`
+	Role       string         `gorm:"not null;size:20" json:"role"`
+	KeyPrefix  string         `gorm:"not null;size:16" json:"key_prefix"`
+	KeyHash    string         `gorm:"not null;size:64;uniqueIndex" json:"-"`
+	LastUsedAt *time.Time     `json:"last_used_at,omitempty"`
+	ExpiresAt  *time.Time     `json:"expires_at,omitempty"`
+	RevokedAt  *time.Time     `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+}
+
+// TableName sets the table name to 'admin_api_keys'
+func (APIKey) TableName() string { return "admin_api_keys" }
+
+// BeforeCreate hook to generate UUID before creating record
+func (k *APIKey) BeforeCreate(tx *gorm.DB) error {
+	if k.ID == uuid.Nil {
+		k.ID = uuid.New()
+	}
+	return nil
+}
+
+// CreateAPIKeyRequest represents the request body for minting an admin API key
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" binding:"required,min=1,max=100"`
+	Role      string     `json:"role" binding:"required,oneof=admin support readonly"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// APIKeyResponse represents the response payload for admin API key operations
+type APIKeyResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	Role       string     `json:"role"`
+	KeyPrefix  string     `json:"key_prefix"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// ToResponse converts APIKey model to APIKeyResponse
+func (k *APIKey) ToResponse() APIKeyResponse {
+	return APIKeyResponse{
+		ID:         k.ID,
+		Name:       k.Name,
+		Role:       k.Role,
+		KeyPrefix:  k.KeyPrefix,
+		LastUsedAt: k.LastUsedAt,
+		ExpiresAt:  k.ExpiresAt,
+		RevokedAt:  k.RevokedAt,
+		CreatedAt:  k.CreatedAt,
+	}
+}
+
+// CreatedAPIKeyResponse is returned once when a key is minted and includes the plaintext key
+type CreatedAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
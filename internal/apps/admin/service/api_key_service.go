@@ -0,0 +1,131 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go-backend/internal/apps/admin/models"
+	"go-backend/internal/apps/admin/repository"
+	"go-backend/internal/common/middleware"
+	"go-backend/pkg/secure"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// apiKeyPrefix marks admin API keys so they are recognisable in logs and secret scanners
+const apiKeyPrefix = "adm_"
+
+// lastUsedResolution limits how often last_used_at is written for a busy key
+const lastUsedResolution = time.Minute
+
+// APIKeyService defines business logic for admin API keys
+type APIKeyService interface {
+	CreateAPIKey(req models.CreateAPIKeyRequest) (*models.CreatedAPIKeyResponse, error)
+	ListAPIKeys() ([]models.APIKeyResponse, error)
+	RevokeAPIKey(id uuid.UUID) error
+	ResolveAdminKey(key string) (*middleware.AdminPrincipal, error)
+}
+
+// apiKeyService implements APIKeyService
+type apiKeyService struct {
+	repo repository.APIKeyRepository
+}
+
+// NewAPIKeyService creates a new instance of APIKeyService
+func NewAPIKeyService(repo repository.APIKeyRepository) APIKeyService {
+	return &apiKeyService{repo: repo}
+}
+
+// isValidRole checks whether role is a known admin role
+func isValidRole(role string) bool {
+	switch role {
+	case middleware.RoleAdmin, middleware.RoleSupport, middleware.RoleReadonly:
+		return true
+	}
+	return false
+}
+
+// CreateAPIKey mints a new admin API key; the plaintext key is only returned here
+func (s *apiKeyService) CreateAPIKey(req models.CreateAPIKeyRequest) (*models.CreatedAPIKeyResponse, error) {
+	if !isValidRole(req.Role) {
+		return nil, errors.New("role must be one of admin, support, readonly")
+	}
+	if req.ExpiresAt != nil && req.ExpiresAt.Before(time.Now()) {
+		return nil, errors.New("expires_at must be in the future")
+	}
+
+	secret, err := secure.GenerateRandomToken(32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate api key: %w", err)
+	}
+	plaintext := apiKeyPrefix + secret
+
+	key := &models.APIKey{
+		Name:      req.Name,
+		Role:      req.Role,
+		KeyPrefix: plaintext[:len(apiKeyPrefix)+8],
+		KeyHash:   secure.HashToken(plaintext),
+		ExpiresAt: req.ExpiresAt,
+	}
+
+	if err := s.repo.Create(key); err != nil {
+		return nil, err
+	}
+
+	return &models.CreatedAPIKeyResponse{
+		APIKeyResponse: key.ToResponse(),
+		Key:            plaintext,
+	}, nil
+}
+
+// ListAPIKeys lists all admin API keys without their secrets
+func (s *apiKeyService) ListAPIKeys() ([]models.APIKeyResponse, error) {
+	keys, err := s.repo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.APIKeyResponse, len(keys))
+	for i, key := range keys {
+		responses[i] = key.ToResponse()
+	}
+	return responses, nil
+}
+
+// RevokeAPIKey revokes an admin API key
+func (s *apiKeyService) RevokeAPIKey(id uuid.UUID) error {
+	return s.repo.Revoke(id)
+}
+
+// ResolveAdminKey validates a raw admin API key and returns the principal it belongs to
+func (s *apiKeyService) ResolveAdminKey(key string) (*middleware.AdminPrincipal, error) {
+	stored, err := s.repo.FindByHash(secure.HashToken(key))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("invalid api key")
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if stored.RevokedAt != nil {
+		return nil, errors.New("api key revoked")
+	}
+	if stored.ExpiresAt != nil && now.After(*stored.ExpiresAt) {
+		return nil, errors.New("api key expired")
+	}
+
+	if stored.LastUsedAt == nil || now.Sub(*stored.LastUsedAt) > lastUsedResolution {
+		if err := s.repo.TouchLastUsed(stored.ID, now); err != nil {
+			fmt.Printf("[APIKeyService] Failed to record last use of key %s: %v\n", stored.KeyPrefix, err)
+		}
+	}
+
+	return &middleware.AdminPrincipal{
+		KeyID: stored.ID,
+		Name:  stored.Name,
+		Role:  stored.Role,
+	}, nil
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"net/http"
+
+	"go-backend/internal/apps/auth/models"
+	"go-backend/internal/apps/auth/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuthHandler handles HTTP endpoints for session management
+type AuthHandler struct {
+	service service.AuthService
+}
+
+// NewAuthHandler creates a new instance of AuthHandler
+func NewAuthHandler(service service.AuthService) *AuthHandler {
+	return &AuthHandler{service: service}
+}
+
+// RefreshSession handles POST /api/v1/auth/refresh
+func (h *AuthHandler) RefreshSession(c *gin.Context) {
+	var req models.RefreshSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.service.RefreshSession(req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch err.Error() {
+		case "invalid refresh token", "refresh token expired":
+			status = http.StatusUnauthorized
+		case "user is suspended":
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
+
+// VerifyMFA handles POST /api/v1/auth/mfa/verify
+func (h *AuthHandler) VerifyMFA(c *gin.Context) {
+	var req models.MFAVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.service.VerifyMFA(req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch err.Error() {
+		case "invalid mfa token", "mfa token expired", "invalid totp code", "totp not enabled":
+			status = http.StatusUnauthorized
+		case "too many failed attempts, try again later":
+			status = http.StatusTooManyRequests
+		case "user is suspended":
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
+
+// Logout handles POST /api/v1/auth/logout
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var req models.LogoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.Logout(req); err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "invalid refresh token" {
+			status = http.StatusUnauthorized
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "logged out successfully"})
+}
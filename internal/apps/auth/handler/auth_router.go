@@ -0,0 +1,13 @@
+package handler
+
+import "github.com/gin-gonic/gin"
+
+// RegisterAuthRoutes registers all session-related routes
+func RegisterAuthRoutes(router *gin.RouterGroup, handler *AuthHandler) {
+	auth := router.Group("/auth")
+	{
+		auth.POST("/refresh", handler.RefreshSession)
+		auth.POST("/logout", handler.Logout)
+		auth.POST("/mfa/verify", handler.VerifyMFA)
+	}
+}
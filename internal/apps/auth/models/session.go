@@ -0,0 +1,92 @@
+package models
+
+import (
+	"time"
+
+	userModels "go-backend/internal/apps/user/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Token types carried in the "typ" claim
+const (
+	TokenTypeAccess = "access"
+	TokenTypeMFA    = "mfa"
+)
+
+// RefreshToken represents a long-lived refresh token issued for a user session
+// Only the SHA-256 hash of the token is persisted
+type RefreshToken struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID     uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	AppName    string     `gorm:"not null;size:100" json:"app_name"`
+	TokenHash  string     `gorm:"not null;size:64;uniqueIndex" json:"-"`
+	ExpiresAt  time.Time  `gorm:"not null" json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	ReplacedBy *uuid.UUID `gorm:"type:uuid" json:"replaced_by,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// TableName sets the table name to 'refresh_tokens'
+func (RefreshToken) TableName() string { return "refresh_tokens" }
+
+// BeforeCreate hook to generate UUID before creating record
+func (t *RefreshToken) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return nil
+}
+
+// AccessTokenClaims represents the claims embedded in a signed access token
+type AccessTokenClaims struct {
+	Subject   string `json:"sub"`
+	AppName   string `json:"app"`
+	Type      string `json:"typ"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// RefreshSessionRequest payload to exchange a refresh token for a new token pair
+type RefreshSessionRequest struct {
+	AppName      string `json:"app_name" binding:"required"`
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// LogoutRequest payload to revoke a refresh token
+type LogoutRequest struct {
+	AppName      string `json:"app_name" binding:"required"`
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// MFAVerifyRequest payload to complete a login that requires a second factor
+type MFAVerifyRequest struct {
+	AppName  string `json:"app_name" binding:"required"`
+	MFAToken string `json:"mfa_token" binding:"required"`
+	Code     string `json:"code" binding:"required"` // Authenticator or recovery code
+}
+
+// MFAChallengeResponse is returned instead of a session when the user must present a second factor
+type MFAChallengeResponse struct {
+	MFAToken  string   `json:"mfa_token"`
+	Methods   []string `json:"methods"`
+	ExpiresIn int64    `json:"expires_in"` // MFA token lifetime in seconds
+}
+
+// LoginResult is the outcome of a first-factor login: either a session or an MFA challenge
+type LoginResult struct {
+	Session *SessionResponse
+	MFA     *MFAChallengeResponse
+}
+
+// SessionResponse represents an authenticated session returned to the client
+type SessionResponse struct {
+	User                  userModels.UserResponse `json:"user"`
+	AccessToken           string                  `json:"access_token"`
+	RefreshToken          string                  `json:"refresh_token"`
+	TokenType             string                  `json:"token_type"`
+	ExpiresIn             int64                   `json:"expires_in"` // Access token lifetime in seconds
+	RefreshTokenExpiresAt time.Time               `json:"refresh_token_expires_at"`
+}
@@ -0,0 +1,360 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go-backend/internal/apps/auth/models"
+	"go-backend/internal/apps/auth/repository"
+	userModels "go-backend/internal/apps/user/models"
+	userRepository "go-backend/internal/apps/user/repository"
+	"go-backend/internal/common/middleware"
+	"go-backend/pkg/secure"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	accessTokenTTL  = 15 * time.Minute
+	refreshTokenTTL = 30 * 24 * time.Hour
+	mfaTokenTTL     = 5 * time.Minute
+)
+
+// ErrUserSuspended is returned when a suspended user tries to sign in or refresh a session
+var ErrUserSuspended = errors.New("user is suspended")
+
+// SecondFactor checks whether a user must present a second factor and verifies it
+type SecondFactor interface {
+	IsEnrolled(userID uuid.UUID) (bool, error)
+	VerifySecondFactor(userID uuid.UUID, code string) error
+}
+
+// AuthService defines business logic for user sessions
+type AuthService interface {
+	LoginWithPhone(appName, countryCode, phone string) (*models.LoginResult, error)
+	LoginWithEmail(appName, email string) (*models.LoginResult, error)
+	VerifyMFA(req models.MFAVerifyRequest) (*models.SessionResponse, error)
+	RefreshSession(req models.RefreshSessionRequest) (*models.SessionResponse, error)
+	Logout(req models.LogoutRequest) error
+	VerifyAccessToken(token string) (*models.AccessTokenClaims, error)
+	ResolvePrincipal(token string) (*middleware.Principal, error)
+}
+
+// authService implements AuthService
+type authService struct {
+	repo         repository.RefreshTokenRepository
+	userRepo     userRepository.UserRepository
+	secondFactor SecondFactor
+}
+
+// NewAuthService creates a new instance of AuthService
+func NewAuthService(repo repository.RefreshTokenRepository, userRepo userRepository.UserRepository, secondFactor SecondFactor) AuthService {
+	return &authService{
+		repo:         repo,
+		userRepo:     userRepo,
+		secondFactor: secondFactor,
+	}
+}
+
+// LoginWithPhone links a verified phone number to its user (creating one if needed) and issues a session,
+// or an MFA challenge if the user has enrolled a second factor
+func (s *authService) LoginWithPhone(appName, countryCode, phone string) (*models.LoginResult, error) {
+	user, err := s.userRepo.FindByAppAndContact(appName, countryCode, phone)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+
+		user = &userModels.User{
+			CountryCode: &countryCode,
+			Phone:       &phone,
+			AppName:     appName,
+		}
+		if err := s.userRepo.Create(user); err != nil {
+			// A concurrent login may have created the user first
+			existing, findErr := s.userRepo.FindByAppAndContact(appName, countryCode, phone)
+			if findErr != nil {
+				return nil, fmt.Errorf("failed to create user: %w", err)
+			}
+			user = existing
+		}
+	}
+
+	return s.completeLogin(user)
+}
+
+// LoginWithEmail links a verified email address to its user (creating one if needed) and issues a session,
+// or an MFA challenge if the user has enrolled a second factor
+func (s *authService) LoginWithEmail(appName, email string) (*models.LoginResult, error) {
+	user, err := s.userRepo.FindByAppAndEmail(appName, email)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+
+		user = &userModels.User{
+			Email:   &email,
+			AppName: appName,
+		}
+		if err := s.userRepo.Create(user); err != nil {
+			// A concurrent login may have created the user first
+			existing, findErr := s.userRepo.FindByAppAndEmail(appName, email)
+			if findErr != nil {
+				return nil, fmt.Errorf("failed to create user: %w", err)
+			}
+			user = existing
+		}
+	}
+
+	return s.completeLogin(user)
+}
+
+// VerifyMFA exchanges an MFA token and a valid second-factor code for a session
+func (s *authService) VerifyMFA(req models.MFAVerifyRequest) (*models.SessionResponse, error) {
+	var claims models.AccessTokenClaims
+	if err := secure.VerifyToken(req.MFAToken, &claims); err != nil {
+		return nil, errors.New("invalid mfa token")
+	}
+	if claims.Type != models.TokenTypeMFA || claims.AppName != req.AppName {
+		return nil, errors.New("invalid mfa token")
+	}
+	if time.Now().Unix() > claims.ExpiresAt {
+		return nil, errors.New("mfa token expired")
+	}
+
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return nil, errors.New("invalid mfa token")
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("invalid mfa token")
+		}
+		return nil, err
+	}
+
+	if err := s.secondFactor.VerifySecondFactor(user.ID, req.Code); err != nil {
+		return nil, err
+	}
+
+	return s.issueSession(user)
+}
+
+// RefreshSession exchanges a valid refresh token for a new token pair (rotating the refresh token)
+func (s *authService) RefreshSession(req models.RefreshSessionRequest) (*models.SessionResponse, error) {
+	stored, err := s.repo.FindByHash(secure.HashToken(req.RefreshToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("invalid refresh token")
+		}
+		return nil, err
+	}
+
+	if stored.AppName != req.AppName {
+		return nil, errors.New("invalid refresh token")
+	}
+
+	// A revoked token being presented again indicates it was leaked; end every session of the user
+	if stored.RevokedAt != nil {
+		if err := s.repo.RevokeAllForUser(stored.UserID); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("invalid refresh token")
+	}
+
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, errors.New("refresh token expired")
+	}
+
+	user, err := s.userRepo.FindByID(stored.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("invalid refresh token")
+		}
+		return nil, err
+	}
+	if user.IsSuspended() {
+		return nil, ErrUserSuspended
+	}
+
+	refreshToken, replacement, err := newRefreshToken(user)
+	if err != nil {
+		return nil, err
+	}
+
+	rotated, err := s.repo.Rotate(stored.ID, replacement)
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		return nil, errors.New("invalid refresh token")
+	}
+
+	return buildSession(user, refreshToken, replacement.ExpiresAt)
+}
+
+// Logout revokes the given refresh token
+func (s *authService) Logout(req models.LogoutRequest) error {
+	stored, err := s.repo.FindByHash(secure.HashToken(req.RefreshToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("invalid refresh token")
+		}
+		return err
+	}
+
+	if stored.AppName != req.AppName {
+		return errors.New("invalid refresh token")
+	}
+
+	return s.repo.Revoke(stored.ID)
+}
+
+// VerifyAccessToken validates an access token's signature, type and expiry and returns its claims
+func (s *authService) VerifyAccessToken(token string) (*models.AccessTokenClaims, error) {
+	var claims models.AccessTokenClaims
+	if err := secure.VerifyToken(token, &claims); err != nil {
+		return nil, errors.New("invalid access token")
+	}
+
+	if claims.Type != models.TokenTypeAccess {
+		return nil, errors.New("invalid access token")
+	}
+
+	if time.Now().Unix() > claims.ExpiresAt {
+		return nil, errors.New("access token expired")
+	}
+
+	return &claims, nil
+}
+
+// ResolvePrincipal verifies an access token and returns the principal it was issued to
+func (s *authService) ResolvePrincipal(token string) (*middleware.Principal, error) {
+	claims, err := s.VerifyAccessToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return nil, errors.New("invalid access token")
+	}
+
+	return &middleware.Principal{
+		UserID:    userID,
+		AppName:   claims.AppName,
+		ExpiresAt: time.Unix(claims.ExpiresAt, 0),
+	}, nil
+}
+
+// completeLogin issues a session, or an MFA challenge if the user has enrolled a second factor
+func (s *authService) completeLogin(user *userModels.User) (*models.LoginResult, error) {
+	if user.IsSuspended() {
+		return nil, ErrUserSuspended
+	}
+
+	enrolled, err := s.secondFactor.IsEnrolled(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if enrolled {
+		challenge, err := buildMFAChallenge(user)
+		if err != nil {
+			return nil, err
+		}
+		return &models.LoginResult{MFA: challenge}, nil
+	}
+
+	session, err := s.issueSession(user)
+	if err != nil {
+		return nil, err
+	}
+	return &models.LoginResult{Session: session}, nil
+}
+
+// issueSession creates and persists a new refresh token and signs an access token for the user
+func (s *authService) issueSession(user *userModels.User) (*models.SessionResponse, error) {
+	if user.IsSuspended() {
+		return nil, ErrUserSuspended
+	}
+
+	refreshToken, stored, err := newRefreshToken(user)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(stored); err != nil {
+		return nil, err
+	}
+
+	return buildSession(user, refreshToken, stored.ExpiresAt)
+}
+
+// newRefreshToken generates a refresh token value and its persisted (hashed) representation
+func newRefreshToken(user *userModels.User) (string, *models.RefreshToken, error) {
+	value, err := secure.GenerateRandomToken(32)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return value, &models.RefreshToken{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		AppName:   user.AppName,
+		TokenHash: secure.HashToken(value),
+		ExpiresAt: time.Now().Add(refreshTokenTTL),
+	}, nil
+}
+
+// buildSession signs an access token scoped to the user's app and assembles the session response
+func buildSession(user *userModels.User, refreshToken string, refreshExpiresAt time.Time) (*models.SessionResponse, error) {
+	now := time.Now()
+	claims := models.AccessTokenClaims{
+		Subject:   user.ID.String(),
+		AppName:   user.AppName,
+		Type:      models.TokenTypeAccess,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(accessTokenTTL).Unix(),
+	}
+
+	accessToken, err := secure.SignToken(claims)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign access token: %w", err)
+	}
+
+	return &models.SessionResponse{
+		User:                  user.ToResponse(),
+		AccessToken:           accessToken,
+		RefreshToken:          refreshToken,
+		TokenType:             "Bearer",
+		ExpiresIn:             int64(accessTokenTTL.Seconds()),
+		RefreshTokenExpiresAt: refreshExpiresAt,
+	}, nil
+}
+
+// buildMFAChallenge signs a short-lived token that lets the user complete login with a second factor
+func buildMFAChallenge(user *userModels.User) (*models.MFAChallengeResponse, error) {
+	now := time.Now()
+	claims := models.AccessTokenClaims{
+		Subject:   user.ID.String(),
+		AppName:   user.AppName,
+		Type:      models.TokenTypeMFA,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(mfaTokenTTL).Unix(),
+	}
+
+	mfaToken, err := secure.SignToken(claims)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign mfa token: %w", err)
+	}
+
+	return &models.MFAChallengeResponse{
+		MFAToken:  mfaToken,
+		Methods:   []string{"totp", "recovery_code"},
+		ExpiresIn: int64(mfaTokenTTL.Seconds()),
+	}, nil
+}
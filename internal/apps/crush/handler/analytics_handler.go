@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"go-backend/internal/apps/crush/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AnalyticsHandler handles HTTP requests for Crush Connect analytics
+// Every endpoint takes app_name, from and to (YYYY-MM-DD, inclusive) filters
+// and returns JSON, or CSV with format=csv
+type AnalyticsHandler struct {
+	service service.AnalyticsService
+}
+
+// NewAnalyticsHandler creates a new instance of AnalyticsHandler
+func NewAnalyticsHandler(service service.AnalyticsService) *AnalyticsHandler {
+	return &AnalyticsHandler{service: service}
+}
+
+// csvExporter is an analytics response that can be written as CSV
+type csvExporter interface {
+	CSVRows() [][]string
+}
+
+// analyticsErrorStatus maps analytics errors to HTTP status codes
+func analyticsErrorStatus(err error) int {
+	switch err.Error() {
+	case "invalid from date", "invalid to date", "from must not be after to", "date range cannot exceed 366 days":
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// analyticsFormat reads the format parameter, defaulting to json
+func analyticsFormat(c *gin.Context) (string, bool) {
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be json or csv"})
+		return "", false
+	}
+	return format, true
+}
+
+// respondAnalytics writes an analytics response in the requested format
+// CSV downloads are named after the metric and the requested dates
+func respondAnalytics(c *gin.Context, format, metric string, resp csvExporter, err error) {
+	if err != nil {
+		c.JSON(analyticsErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	if format != "csv" {
+		c.JSON(http.StatusOK, gin.H{"data": resp})
+		return
+	}
+
+	filename := metric
+	if from := c.Query("from"); from != "" {
+		filename += "_" + from
+	}
+	if to := c.Query("to"); to != "" {
+		filename += "_" + to
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+".csv"))
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	if err := w.WriteAll(resp.CSVRows()); err != nil {
+		fmt.Printf("[CrushAnalytics] Failed to write %s CSV: %v\n", metric, err)
+	}
+}
+
+// CrushesPerDay handles GET /api/v1/crushes/analytics/crushes-per-day
+func (h *AnalyticsHandler) CrushesPerDay(c *gin.Context) {
+	format, ok := analyticsFormat(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.service.CrushesPerDay(c.Query("app_name"), c.Query("from"), c.Query("to"))
+	respondAnalytics(c, format, "crushes-per-day", resp, err)
+}
+
+// MatchesPerDay handles GET /api/v1/crushes/analytics/matches-per-day
+func (h *AnalyticsHandler) MatchesPerDay(c *gin.Context) {
+	format, ok := analyticsFormat(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.service.MatchesPerDay(c.Query("app_name"), c.Query("from"), c.Query("to"))
+	respondAnalytics(c, format, "matches-per-day", resp, err)
+}
+
+// ContactMethods handles GET /api/v1/crushes/analytics/contact-methods
+func (h *AnalyticsHandler) ContactMethods(c *gin.Context) {
+	format, ok := analyticsFormat(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.service.ContactMethods(c.Query("app_name"), c.Query("from"), c.Query("to"))
+	respondAnalytics(c, format, "contact-methods", resp, err)
+}
+
+// Conversion handles GET /api/v1/crushes/analytics/conversion
+func (h *AnalyticsHandler) Conversion(c *gin.Context) {
+	format, ok := analyticsFormat(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.service.Conversion(c.Query("app_name"), c.Query("from"), c.Query("to"))
+	respondAnalytics(c, format, "conversion", resp, err)
+}
+
+// CountryCodes handles GET /api/v1/crushes/analytics/country-codes
+// Supports a limit parameter (default 10, max 100)
+func (h *AnalyticsHandler) CountryCodes(c *gin.Context) {
+	format, ok := analyticsFormat(c)
+	if !ok {
+		return
+	}
+
+	limit := 0
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
+			limit = l
+		}
+	}
+
+	resp, err := h.service.TopCountryCodes(c.Query("app_name"), c.Query("from"), c.Query("to"), limit)
+	respondAnalytics(c, format, "country-codes", resp, err)
+}
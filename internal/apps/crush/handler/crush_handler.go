@@ -1,11 +1,13 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"go-backend/internal/apps/crush/models"
 	"go-backend/internal/apps/crush/service"
+	"go-backend/internal/common/middleware"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -21,17 +23,89 @@ func NewCrushHandler(service service.CrushService) *CrushHandler {
 	return &CrushHandler{service: service}
 }
 
+// resolveUserID returns the authenticated user's ID, rejecting requests whose
+// user_id query parameter refers to someone else
+func resolveUserID(c *gin.Context) (uuid.UUID, bool) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+		return uuid.Nil, false
+	}
+
+	if userIDStr := c.Query("user_id"); userIDStr != "" {
+		userID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user_id"})
+			return uuid.Nil, false
+		}
+		if userID != principal.UserID {
+			c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+			return uuid.Nil, false
+		}
+	}
+
+	return principal.UserID, true
+}
+
+// writeUpgradeRequired responds with 402 and the gated feature when err is an UpgradeRequiredError
+// Returns false for any other error
+func writeUpgradeRequired(c *gin.Context, err error) bool {
+	var upgradeErr *service.UpgradeRequiredError
+	if !errors.As(err, &upgradeErr) {
+		return false
+	}
+	c.JSON(http.StatusPaymentRequired, gin.H{"error": upgradeErr.Error(), "upgrade": upgradeErr})
+	return true
+}
+
+// authorizeCrush loads a crush and ensures it belongs to the authenticated user
+// Crushes owned by someone else are reported as not found
+func (h *CrushHandler) authorizeCrush(c *gin.Context, userID, crushID uuid.UUID) bool {
+	crush, err := h.service.GetCrushByID(crushID)
+	if err != nil {
+		c.JSON(crushStateErrorStatus(err), gin.H{"error": err.Error()})
+		return false
+	}
+
+	if crush.UserID != userID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "crush not found"})
+		return false
+	}
+
+	return true
+}
+
 // CreateCrush handles POST /api/v1/crushes
 func (h *CrushHandler) CreateCrush(c *gin.Context) {
+	userID, ok := resolveUserID(c)
+	if !ok {
+		return
+	}
+
 	var req models.CreateCrushRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	// Crushes are always created for the authenticated user
+	if req.UserID != uuid.Nil && req.UserID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	req.UserID = userID
+
 	resp, err := h.service.CreateCrush(req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		if writeUpgradeRequired(c, err) {
+			return
+		}
+		status := http.StatusBadRequest
+		switch err.Error() {
+		case "crush limit reached", "user is suspended":
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
 		return
 	}
 
@@ -40,12 +114,21 @@ func (h *CrushHandler) CreateCrush(c *gin.Context) {
 
 // UpdateCrush handles PUT /api/v1/crushes/:id
 func (h *CrushHandler) UpdateCrush(c *gin.Context) {
+	userID, ok := resolveUserID(c)
+	if !ok {
+		return
+	}
+
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid crush id"})
 		return
 	}
 
+	if !h.authorizeCrush(c, userID, id) {
+		return
+	}
+
 	var req models.UpdateCrushRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -55,8 +138,11 @@ func (h *CrushHandler) UpdateCrush(c *gin.Context) {
 	resp, err := h.service.UpdateCrush(id, req)
 	if err != nil {
 		status := http.StatusBadRequest
-		if err.Error() == "crush not found" {
+		switch err.Error() {
+		case "crush not found":
 			status = http.StatusNotFound
+		case "user is suspended":
+			status = http.StatusForbidden
 		}
 		c.JSON(status, gin.H{"error": err.Error()})
 		return
@@ -65,23 +151,113 @@ func (h *CrushHandler) UpdateCrush(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"data": resp})
 }
 
-// ListCrushes handles GET /api/v1/crushes?user_id=<uuid>
-func (h *CrushHandler) ListCrushes(c *gin.Context) {
-	userIDStr := c.Query("user_id")
-	if userIDStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "user_id is required"})
+// crushStateErrorStatus maps errors from crush state changes to HTTP status codes
+func crushStateErrorStatus(err error) int {
+	switch err.Error() {
+	case "crush not found", "user not found":
+		return http.StatusNotFound
+	case "only active crushes can be archived", "crush is already active":
+		return http.StatusConflict
+	case "crush limit reached", "user is suspended":
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// DeleteCrush handles DELETE /api/v1/crushes/:id
+func (h *CrushHandler) DeleteCrush(c *gin.Context) {
+	userID, ok := resolveUserID(c)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid crush id"})
 		return
 	}
 
-	userID, err := uuid.Parse(userIDStr)
+	if !h.authorizeCrush(c, userID, id) {
+		return
+	}
+
+	resp, err := h.service.DeleteCrush(id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user_id"})
+		c.JSON(crushStateErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "crush deleted successfully", "data": resp})
+}
+
+// ArchiveCrush handles POST /api/v1/crushes/:id/archive
+func (h *CrushHandler) ArchiveCrush(c *gin.Context) {
+	userID, ok := resolveUserID(c)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid crush id"})
+		return
+	}
+
+	if !h.authorizeCrush(c, userID, id) {
+		return
+	}
+
+	resp, err := h.service.ArchiveCrush(id)
+	if err != nil {
+		c.JSON(crushStateErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
+
+// RestoreCrush handles POST /api/v1/crushes/:id/restore
+// Reactivates an archived or expired crush
+func (h *CrushHandler) RestoreCrush(c *gin.Context) {
+	userID, ok := resolveUserID(c)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid crush id"})
+		return
+	}
+
+	if !h.authorizeCrush(c, userID, id) {
+		return
+	}
+
+	resp, err := h.service.RestoreCrush(id)
+	if err != nil {
+		if writeUpgradeRequired(c, err) {
+			return
+		}
+		c.JSON(crushStateErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
+
+// ListCrushes handles GET /api/v1/crushes
+// Lists the authenticated user's crushes
+func (h *CrushHandler) ListCrushes(c *gin.Context) {
+	userID, ok := resolveUserID(c)
+	if !ok {
 		return
 	}
 
 	resp, err := h.service.ListCrushesByUserID(userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(crushStateErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -90,6 +266,11 @@ func (h *CrushHandler) ListCrushes(c *gin.Context) {
 
 // GetCrush handles GET /api/v1/crushes/:id
 func (h *CrushHandler) GetCrush(c *gin.Context) {
+	userID, ok := resolveUserID(c)
+	if !ok {
+		return
+	}
+
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid crush id"})
@@ -98,36 +279,61 @@ func (h *CrushHandler) GetCrush(c *gin.Context) {
 
 	resp, err := h.service.GetCrushByID(id)
 	if err != nil {
-		status := http.StatusInternalServerError
-		if err.Error() == "crush not found" {
-			status = http.StatusNotFound
-		}
-		c.JSON(status, gin.H{"error": err.Error()})
+		c.JSON(crushStateErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	if resp.UserID != userID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "crush not found"})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"data": resp})
 }
 
-// ListCrushesOnUser handles GET /api/v1/crushes/on-user?user_id=<uuid>
+// ListCrushesOnUser handles GET /api/v1/crushes/on-user
+// Lists crushes other people have on the authenticated user
 func (h *CrushHandler) ListCrushesOnUser(c *gin.Context) {
-	userIDStr := c.Query("user_id")
-	if userIDStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "user_id is required"})
+	userID, ok := resolveUserID(c)
+	if !ok {
 		return
 	}
 
-	userID, err := uuid.Parse(userIDStr)
+	resp, err := h.service.ListCrushesOnUser(userID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user_id"})
+		if writeUpgradeRequired(c, err) {
+			return
+		}
+		status := http.StatusInternalServerError
+		switch err.Error() {
+		case "user not found":
+			status = http.StatusNotFound
+		case "user is suspended":
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
 		return
 	}
 
-	resp, err := h.service.ListCrushesOnUser(userID)
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
+
+// ListMatches handles GET /api/v1/crushes/matches
+// Lists the authenticated user's mutual matches
+func (h *CrushHandler) ListMatches(c *gin.Context) {
+	userID, ok := resolveUserID(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.service.ListMatches(userID)
 	if err != nil {
 		status := http.StatusInternalServerError
-		if err.Error() == "user not found" {
+		switch err.Error() {
+		case "user not found":
 			status = http.StatusNotFound
+		case "user is suspended":
+			status = http.StatusForbidden
 		}
 		c.JSON(status, gin.H{"error": err.Error()})
 		return
@@ -156,7 +362,13 @@ func (h *CrushHandler) ListAllCrushes(c *gin.Context) {
 		}
 	}
 
-	resp, err := h.service.ListAllCrushesPaginated(page, pageSize)
+	// Only full admin keys see phones and handles in clear
+	reveal := false
+	if admin, ok := middleware.GetAdminPrincipal(c); ok {
+		reveal = admin.Role == middleware.RoleAdmin
+	}
+
+	resp, err := h.service.ListAllCrushesPaginated(page, pageSize, reveal)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
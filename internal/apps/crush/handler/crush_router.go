@@ -1,16 +1,38 @@
 package handler
 
-import "github.com/gin-gonic/gin"
+import (
+	"go-backend/internal/common/middleware"
+
+	"github.com/gin-gonic/gin"
+)
 
 // RegisterCrushRoutes registers all crush-related routes
-func RegisterCrushRoutes(router *gin.RouterGroup, handler *CrushHandler) {
+// requireAuth guards routes that act on behalf of the authenticated user,
+// adminGuard guards management listings and analytics
+// Invite replies arrive from the SMS provider and are authenticated by its signature
+func RegisterCrushRoutes(router *gin.RouterGroup, handler *CrushHandler, inviteHandler *InviteHandler, analyticsHandler *AnalyticsHandler, requireAuth gin.HandlerFunc, adminGuard middleware.AdminGuard) {
 	crushes := router.Group("/crushes")
 	{
-		crushes.POST("", handler.CreateCrush)
-		crushes.GET("/all", handler.ListAllCrushes)
-		crushes.GET("/:id", handler.GetCrush)
-		crushes.PUT("/:id", handler.UpdateCrush)
-		crushes.GET("", handler.ListCrushes)
-		crushes.GET("/on-user", handler.ListCrushesOnUser)
+		crushes.POST("", requireAuth, handler.CreateCrush)
+		crushes.GET("/all", adminGuard(), handler.ListAllCrushes)
+		crushes.GET("/:id", requireAuth, handler.GetCrush)
+		crushes.PUT("/:id", requireAuth, handler.UpdateCrush)
+		crushes.DELETE("/:id", requireAuth, handler.DeleteCrush)
+		crushes.POST("/:id/archive", requireAuth, handler.ArchiveCrush)
+		crushes.POST("/:id/restore", requireAuth, handler.RestoreCrush)
+		crushes.GET("", requireAuth, handler.ListCrushes)
+		crushes.GET("/on-user", requireAuth, handler.ListCrushesOnUser)
+		crushes.GET("/matches", requireAuth, handler.ListMatches)
+		crushes.POST("/invites/inbound", inviteHandler.InboundSMS)
+		crushes.POST("/invites/opt-outs", adminGuard(middleware.RoleAdmin, middleware.RoleSupport), inviteHandler.OptOut)
+	}
+
+	analytics := crushes.Group("/analytics", adminGuard())
+	{
+		analytics.GET("/crushes-per-day", analyticsHandler.CrushesPerDay)
+		analytics.GET("/matches-per-day", analyticsHandler.MatchesPerDay)
+		analytics.GET("/contact-methods", analyticsHandler.ContactMethods)
+		analytics.GET("/conversion", analyticsHandler.Conversion)
+		analytics.GET("/country-codes", analyticsHandler.CountryCodes)
 	}
 }
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"go-backend/internal/apps/crush/models"
+	"go-backend/internal/apps/crush/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InviteHandler handles invite opt-outs: inbound SMS replies and admin requests
+type InviteHandler struct {
+	service         service.InviteService
+	twilioAuthToken string
+	inboundURL      string
+}
+
+// NewInviteHandler creates a new instance of InviteHandler
+// twilioAuthToken and inboundURL (the public URL of the inbound webhook) are used to verify Twilio signatures;
+// inbound replies are rejected when they are not configured
+func NewInviteHandler(service service.InviteService, twilioAuthToken, inboundURL string) *InviteHandler {
+	return &InviteHandler{
+		service:         service,
+		twilioAuthToken: twilioAuthToken,
+		inboundURL:      inboundURL,
+	}
+}
+
+// InboundSMS handles POST /api/v1/crushes/invites/inbound
+// Twilio messaging webhook for replies to invites (STOP / START)
+func (h *InviteHandler) InboundSMS(c *gin.Context) {
+	if h.twilioAuthToken == "" || h.inboundURL == "" {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "inbound sms not configured"})
+		return
+	}
+
+	if err := c.Request.ParseForm(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid form body"})
+		return
+	}
+
+	if !validTwilioSignature(h.twilioAuthToken, h.inboundURL, c.Request.PostForm, c.GetHeader("X-Twilio-Signature")) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "invalid signature"})
+		return
+	}
+
+	if err := h.service.HandleInboundSMS(c.Request.PostForm.Get("From"), c.Request.PostForm.Get("Body")); err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "invalid sender number" {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Empty TwiML: the carrier sends its own opt-out confirmation
+	c.Data(http.StatusOK, "text/xml", []byte("<Response></Response>"))
+}
+
+// OptOut handles POST /api/v1/crushes/invites/opt-outs
+func (h *InviteHandler) OptOut(c *gin.Context) {
+	var req models.InviteOptOutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.OptOut(req.CountryCode, req.Phone, service.OptOutSourceAdmin); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "phone number opted out of invites"})
+}
+
+// validTwilioSignature checks X-Twilio-Signature: base64(HMAC-SHA1(authToken, url + sorted key/value pairs))
+func validTwilioSignature(authToken, requestURL string, form url.Values, signature string) bool {
+	if signature == "" {
+		return false
+	}
+
+	keys := make([]string, 0, len(form))
+	for key := range form {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(requestURL)
+	for _, key := range keys {
+		for _, value := range form[key] {
+			b.WriteString(key)
+			b.WriteString(value)
+		}
+	}
+
+	mac := hmac.New(sha1.New, []byte(authToken))
+	mac.Write([]byte(b.String()))
+	expected := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
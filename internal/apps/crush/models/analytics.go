@@ -0,0 +1,118 @@
+package models
+
+import (
+	"strconv"
+	"time"
+)
+
+// AnalyticsFilter scopes an analytics query to crushes or matches created in [From, To)
+// An empty AppName covers every app
+type AnalyticsFilter struct {
+	AppName string
+	From    time.Time
+	To      time.Time
+}
+
+// AnalyticsRange echoes the filter an analytics response covers; dates are inclusive and in UTC
+type AnalyticsRange struct {
+	AppName string `json:"app_name,omitempty"`
+	From    string `json:"from"`
+	To      string `json:"to"`
+}
+
+// DailyCount is the number of records created on a UTC day
+type DailyCount struct {
+	Date  string `json:"date"`
+	Count int64  `json:"count"`
+}
+
+// DailyCountsResponse represents a per-day series with every day in the range present
+type DailyCountsResponse struct {
+	AnalyticsRange
+	Total int64        `json:"total"`
+	Days  []DailyCount `json:"days"`
+}
+
+// CSVRows returns the series as CSV rows with a header
+func (r DailyCountsResponse) CSVRows() [][]string {
+	rows := [][]string{{"date", "count"}}
+	for _, day := range r.Days {
+		rows = append(rows, []string{day.Date, formatCount(day.Count)})
+	}
+	return rows
+}
+
+// ContactMethodCounts holds the number of crushes using each contact method
+// A crush can name several methods, so the per-method counts may add up to more than the total
+type ContactMethodCounts struct {
+	Total     int64 `json:"total"`
+	Phone     int64 `json:"phone"`
+	Instagram int64 `json:"instagram"`
+	Snapchat  int64 `json:"snapchat"`
+}
+
+// ContactMethodsResponse represents the distribution of crush contact methods
+type ContactMethodsResponse struct {
+	AnalyticsRange
+	ContactMethodCounts
+}
+
+// CSVRows returns the distribution as CSV rows with a header
+func (r ContactMethodsResponse) CSVRows() [][]string {
+	return [][]string{
+		{"method", "count"},
+		{"phone", formatCount(r.Phone)},
+		{"instagram", formatCount(r.Instagram)},
+		{"snapchat", formatCount(r.Snapchat)},
+		{"total", formatCount(r.Total)},
+	}
+}
+
+// ConversionCounts holds how many crush targets were already users and how many signed up afterwards
+type ConversionCounts struct {
+	Crushes           int64 `json:"crushes"`
+	AlreadyRegistered int64 `json:"already_registered"` // Target had an account when the crush was added
+	SignedUpAfter     int64 `json:"signed_up_after"`    // Target created an account after the crush was added
+}
+
+// ConversionResponse represents the crush to signup conversion of crush targets
+// ConversionRate is SignedUpAfter over the crushes whose target was not yet registered
+type ConversionResponse struct {
+	AnalyticsRange
+	ConversionCounts
+	ConversionRate float64 `json:"conversion_rate"`
+}
+
+// CSVRows returns the conversion as CSV rows with a header
+func (r ConversionResponse) CSVRows() [][]string {
+	return [][]string{
+		{"crushes", "already_registered", "signed_up_after", "conversion_rate"},
+		{formatCount(r.Crushes), formatCount(r.AlreadyRegistered), formatCount(r.SignedUpAfter), strconv.FormatFloat(r.ConversionRate, 'f', 4, 64)},
+	}
+}
+
+// CountryCodeCount is the number of crushes naming a phone with a country code
+type CountryCodeCount struct {
+	CountryCode string `json:"country_code"`
+	Count       int64  `json:"count"`
+}
+
+// CountryCodesResponse represents the most common crush country codes, most common first
+type CountryCodesResponse struct {
+	AnalyticsRange
+	CountryCodes []CountryCodeCount `json:"country_codes"`
+}
+
+// CSVRows returns the country codes as CSV rows with a header
+func (r CountryCodesResponse) CSVRows() [][]string {
+	rows := [][]string{{"country_code", "count"}}
+	for _, code := range r.CountryCodes {
+		rows = append(rows, []string{code.CountryCode, formatCount(code.Count)})
+	}
+	return rows
+}
+
+// formatCount formats a count for CSV
+func formatCount(count int64) string {
+	return strconv.FormatInt(count, 10)
+}
@@ -5,6 +5,9 @@ import (
 	"encoding/json"
 	"time"
 
+	"go-backend/pkg/identifier"
+	"go-backend/pkg/secure"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
@@ -33,19 +36,53 @@ func (m Metadata) Value() (driver.Value, error) {
 	return json.Marshal(m)
 }
 
+// Crush statuses; only active crushes take part in matching and count against a user's slots
+const (
+	CrushStatusActive   = "active"
+	CrushStatusArchived = "archived" // Hidden from matching by the user
+	CrushStatusExpired  = "expired"  // Past the app's expiry window
+)
+
 // Crush represents a crush entry in Crush Connect app
 type Crush struct {
 	ID          uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
 	UserID      uuid.UUID      `gorm:"type:uuid;not null" json:"user_id"`
 	Name        string         `gorm:"not null;size:255" json:"name"`
 	CountryCode *string        `gorm:"size:10" json:"country_code,omitempty"`
-	Phone       *string        `gorm:"size:20" json:"phone,omitempty"`
-	InstagramID *string        `gorm:"size:255" json:"instagram_id,omitempty"`
-	SnapchatID  *string        `gorm:"size:255" json:"snapchat_id,omitempty"`
+	Phone       *string        `gorm:"-" json:"phone,omitempty"`
+	InstagramID *string        `gorm:"-" json:"instagram_id,omitempty"`
+	SnapchatID  *string        `gorm:"-" json:"snapchat_id,omitempty"`
 	Metadata    Metadata       `gorm:"type:jsonb;not null;default:'{}'" json:"metadata"`
+	Status      string         `gorm:"not null;size:20;default:'active'" json:"status"`
+	ExpiresAt   *time.Time     `json:"expires_at,omitempty"`
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+
+	// Target identifiers are only held in clear in memory; they are stored encrypted
+	PhoneEncrypted     *string `gorm:"type:text" json:"-"`
+	InstagramEncrypted *string `gorm:"type:text" json:"-"`
+	SnapchatEncrypted  *string `gorm:"type:text" json:"-"`
+
+	// Blind indexes of the normalized identifiers, used for matching without plaintext
+	PhoneHash     *string `gorm:"size:64" json:"-"`
+	InstagramHash *string `gorm:"size:64" json:"-"`
+	SnapchatHash  *string `gorm:"size:64" json:"-"`
+
+	// Plaintext columns written before encryption; read as a fallback and cleared on save
+	LegacyPhone       *string `gorm:"column:phone;size:20" json:"-"`
+	LegacyInstagramID *string `gorm:"column:instagram_id;size:255" json:"-"`
+	LegacySnapchatID  *string `gorm:"column:snapchat_id;size:255" json:"-"`
+
+	// Canonical identifiers used for matching, derived on save and load
+	PhoneNormalized     *string `gorm:"-" json:"-"`
+	InstagramNormalized *string `gorm:"-" json:"-"`
+	SnapchatNormalized  *string `gorm:"-" json:"-"`
+}
+
+// IsActive reports whether the crush takes part in matching
+func (c *Crush) IsActive() bool {
+	return c.Status == CrushStatusActive
 }
 
 // BeforeCreate hook to generate UUID before creating record
@@ -53,18 +90,114 @@ func (c *Crush) BeforeCreate(tx *gorm.DB) error {
 	if c.ID == uuid.Nil {
 		c.ID = uuid.New()
 	}
+	if c.Status == "" {
+		c.Status = CrushStatusActive
+	}
+	return nil
+}
+
+// BeforeSave hook to encrypt the target identifiers and keep their blind indexes in sync
+func (c *Crush) BeforeSave(tx *gorm.DB) error {
+	c.NormalizeIdentifiers()
+
+	var err error
+	if c.PhoneEncrypted, err = encryptIdentifier(c.Phone); err != nil {
+		return err
+	}
+	if c.InstagramEncrypted, err = encryptIdentifier(c.InstagramID); err != nil {
+		return err
+	}
+	if c.SnapchatEncrypted, err = encryptIdentifier(c.SnapchatID); err != nil {
+		return err
+	}
+
+	if c.PhoneHash, err = identifier.OptionalBlindIndex(identifier.KindPhone, c.PhoneNormalized); err != nil {
+		return err
+	}
+	if c.InstagramHash, err = identifier.OptionalBlindIndex(identifier.KindInstagram, c.InstagramNormalized); err != nil {
+		return err
+	}
+	if c.SnapchatHash, err = identifier.OptionalBlindIndex(identifier.KindSnapchat, c.SnapchatNormalized); err != nil {
+		return err
+	}
+
+	c.LegacyPhone, c.LegacyInstagramID, c.LegacySnapchatID = nil, nil, nil
+	return nil
+}
+
+// AfterFind hook to decrypt the target identifiers, falling back to legacy plaintext columns
+func (c *Crush) AfterFind(tx *gorm.DB) error {
+	var err error
+	if c.Phone, err = decryptIdentifier(c.PhoneEncrypted, c.LegacyPhone); err != nil {
+		return err
+	}
+	if c.InstagramID, err = decryptIdentifier(c.InstagramEncrypted, c.LegacyInstagramID); err != nil {
+		return err
+	}
+	if c.SnapchatID, err = decryptIdentifier(c.SnapchatEncrypted, c.LegacySnapchatID); err != nil {
+		return err
+	}
+
+	c.NormalizeIdentifiers()
 	return nil
 }
 
+// encryptIdentifier encrypts an optional identifier; empty values are stored as NULL
+func encryptIdentifier(value *string) (*string, error) {
+	if value == nil || *value == "" {
+		return nil, nil
+	}
+	encrypted, err := secure.EncryptString(*value)
+	if err != nil {
+		return nil, err
+	}
+	return &encrypted, nil
+}
+
+// decryptIdentifier decrypts an optional identifier, returning the legacy value if it was never encrypted
+func decryptIdentifier(encrypted, legacy *string) (*string, error) {
+	if encrypted == nil || *encrypted == "" {
+		return legacy, nil
+	}
+	plaintext, err := secure.DecryptString(*encrypted)
+	if err != nil {
+		return nil, err
+	}
+	return &plaintext, nil
+}
+
+// NormalizeIdentifiers derives the normalized phone and social handles
+// Identifiers that cannot be normalized are left empty so they never match
+func (c *Crush) NormalizeIdentifiers() {
+	c.PhoneNormalized, c.InstagramNormalized, c.SnapchatNormalized = nil, nil, nil
+	if c.CountryCode != nil && c.Phone != nil {
+		if phone, err := identifier.NormalizePhone(*c.CountryCode, *c.Phone); err == nil {
+			c.PhoneNormalized = &phone
+		}
+	}
+	if c.InstagramID != nil {
+		if handle, err := identifier.NormalizeHandle(*c.InstagramID); err == nil {
+			c.InstagramNormalized = &handle
+		}
+	}
+	if c.SnapchatID != nil {
+		if handle, err := identifier.NormalizeHandle(*c.SnapchatID); err == nil {
+			c.SnapchatNormalized = &handle
+		}
+	}
+}
+
 // CreateCrushRequest represents the request body for creating a crush
+// UserID is taken from the authenticated principal
 type CreateCrushRequest struct {
-	UserID      uuid.UUID `json:"user_id" binding:"required"`
+	UserID      uuid.UUID `json:"user_id,omitempty"`
 	Name        string    `json:"name" binding:"required,min=1,max=255"`
 	CountryCode *string   `json:"country_code,omitempty"`
 	Phone       *string   `json:"phone,omitempty"`
 	InstagramID *string   `json:"instagram_id,omitempty"`
 	SnapchatID  *string   `json:"snapchat_id,omitempty"`
 	Metadata    Metadata  `json:"metadata,omitempty"`
+	Invite      bool      `json:"invite,omitempty"` // Send an anonymous SMS invite if the phone number is not registered in the app
 }
 
 // UpdateCrushRequest represents the request body for updating a crush
@@ -79,16 +212,18 @@ type UpdateCrushRequest struct {
 
 // CrushResponse represents the response payload for crush operations
 type CrushResponse struct {
-	ID          uuid.UUID `json:"id"`
-	UserID      uuid.UUID `json:"user_id"`
-	Name        string    `json:"name"`
-	CountryCode *string   `json:"country_code,omitempty"`
-	Phone       *string   `json:"phone,omitempty"`
-	InstagramID *string   `json:"instagram_id,omitempty"`
-	SnapchatID  *string   `json:"snapchat_id,omitempty"`
-	Metadata    Metadata  `json:"metadata"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          uuid.UUID  `json:"id"`
+	UserID      uuid.UUID  `json:"user_id"`
+	Name        string     `json:"name"`
+	CountryCode *string    `json:"country_code,omitempty"`
+	Phone       *string    `json:"phone,omitempty"`
+	InstagramID *string    `json:"instagram_id,omitempty"`
+	SnapchatID  *string    `json:"snapchat_id,omitempty"`
+	Metadata    Metadata   `json:"metadata"`
+	Status      string     `json:"status"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
 }
 
 // ToResponse converts Crush model to CrushResponse
@@ -102,19 +237,31 @@ func (c *Crush) ToResponse() CrushResponse {
 		InstagramID: c.InstagramID,
 		SnapchatID:  c.SnapchatID,
 		Metadata:    c.Metadata,
+		Status:      c.Status,
+		ExpiresAt:   c.ExpiresAt,
 		CreatedAt:   c.CreatedAt,
 		UpdatedAt:   c.UpdatedAt,
 	}
 }
 
+// DeleteCrushResponse reports the effect of deleting a crush on the user's slots
+type DeleteCrushResponse struct {
+	ID            uuid.UUID `json:"id"`
+	SlotsFreed    int       `json:"slots_freed"`    // 1 if the deleted crush was active, 0 otherwise
+	ActiveCrushes int64     `json:"active_crushes"` // Active crushes the user has left
+}
+
 // CrushOnUserResponse represents a minimal response for crushes on a user
+// The ID lets the user block or report the crush without learning who owns it
 type CrushOnUserResponse struct {
+	ID        uuid.UUID `json:"id"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
 // ToMinimalResponse converts Crush model to CrushOnUserResponse
 func (c *Crush) ToMinimalResponse() CrushOnUserResponse {
 	return CrushOnUserResponse{
+		ID:        c.ID,
 		CreatedAt: c.CreatedAt,
 	}
 }
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CrushMatch records two users who have added each other as crushes
+// The pair is stored in a canonical order (UserAID < UserBID) so each match has a single row
+type CrushMatch struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	AppName   string    `gorm:"not null;size:100" json:"app_name"`
+	UserAID   uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_crush_matches_pair" json:"user_a_id"`
+	UserBID   uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_crush_matches_pair;index" json:"user_b_id"`
+	CrushAID  uuid.UUID `gorm:"type:uuid;not null" json:"crush_a_id"` // User A's crush entry for user B
+	CrushBID  uuid.UUID `gorm:"type:uuid;not null" json:"crush_b_id"` // User B's crush entry for user A
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName sets the table name to 'crush_matches'
+func (CrushMatch) TableName() string { return "crush_matches" }
+
+// BeforeCreate hook to generate UUID before creating record
+func (m *CrushMatch) BeforeCreate(tx *gorm.DB) error {
+	if m.ID == uuid.Nil {
+		m.ID = uuid.New()
+	}
+	return nil
+}
+
+// MatchedUserResponse reveals the other party of a mutual match
+type MatchedUserResponse struct {
+	ID          uuid.UUID `json:"id"`
+	Name        *string   `json:"name,omitempty"`
+	CountryCode *string   `json:"country_code,omitempty"`
+	Phone       *string   `json:"phone,omitempty"`
+	Email       *string   `json:"email,omitempty"`
+	InstagramID *string   `json:"instagram_id,omitempty"`
+	SnapchatID  *string   `json:"snapchat_id,omitempty"`
+}
+
+// CrushMatchResponse represents a mutual match from the requesting user's point of view
+type CrushMatchResponse struct {
+	ID          uuid.UUID           `json:"id"`
+	UserID      uuid.UUID           `json:"user_id"`
+	Crush       CrushResponse       `json:"crush"` // The requesting user's crush entry for the matched user
+	MatchedUser MatchedUserResponse `json:"matched_user"`
+	MatchedAt   time.Time           `json:"matched_at"`
+}
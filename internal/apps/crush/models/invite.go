@@ -0,0 +1,65 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Invite statuses
+const (
+	InviteStatusPending = "pending"
+	InviteStatusSent    = "sent"
+	InviteStatusFailed  = "failed"
+)
+
+// Outcomes of reserving an invite
+const (
+	InviteReserved     = "reserved"
+	InviteDuplicate    = "duplicate"     // The target was already invited recently
+	InviteSenderCapped = "sender_capped" // The sender reached their daily invite cap
+)
+
+// CrushInvite records an SMS invite sent to a crush target who is not registered in the app
+// The target's phone number is only stored as a keyed hash
+type CrushInvite struct {
+	ID           uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	AppName      string    `gorm:"not null;size:100" json:"app_name"`
+	TargetHash   string    `gorm:"not null;size:64" json:"-"`
+	SenderUserID uuid.UUID `gorm:"type:uuid;not null" json:"sender_user_id"`
+	CrushID      uuid.UUID `gorm:"type:uuid;not null" json:"crush_id"`
+	Status       string    `gorm:"not null;size:20" json:"status"`
+	Provider     string    `gorm:"size:50" json:"provider,omitempty"`
+	Error        string    `gorm:"type:text" json:"error,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// TableName sets the table name to 'crush_invites'
+func (CrushInvite) TableName() string { return "crush_invites" }
+
+// BeforeCreate hook to generate UUID before creating record
+func (i *CrushInvite) BeforeCreate(tx *gorm.DB) error {
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
+	return nil
+}
+
+// InviteOptOut records a phone number that replied STOP (or was opted out by an admin)
+// Opt-outs apply to invites from every app
+type InviteOptOut struct {
+	TargetHash string    `gorm:"primaryKey;size:64" json:"-"`
+	Source     string    `gorm:"not null;size:20" json:"source"` // sms or admin
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// TableName sets the table name to 'invite_opt_outs'
+func (InviteOptOut) TableName() string { return "invite_opt_outs" }
+
+// InviteOptOutRequest payload to opt a phone number out of invites
+type InviteOptOutRequest struct {
+	CountryCode string `json:"country_code" binding:"required"`
+	Phone       string `json:"phone" binding:"required"`
+}
@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"go-backend/internal/apps/crush/models"
+	"go-backend/pkg/identifier"
+
+	"gorm.io/gorm"
+)
+
+// AnalyticsRepository defines aggregate queries over crushes and matches for the admin dashboard
+// Deleted crushes are left out; days are UTC
+type AnalyticsRepository interface {
+	CrushesPerDay(filter models.AnalyticsFilter) ([]models.DailyCount, error)
+	MatchesPerDay(filter models.AnalyticsFilter) ([]models.DailyCount, error)
+	ContactMethods(filter models.AnalyticsFilter) (*models.ContactMethodCounts, error)
+	Conversion(filter models.AnalyticsFilter) (*models.ConversionCounts, error)
+	TopCountryCodes(filter models.AnalyticsFilter, limit int) ([]models.CountryCodeCount, error)
+}
+
+// analyticsRepository implements AnalyticsRepository
+type analyticsRepository struct {
+	db *gorm.DB
+}
+
+// NewAnalyticsRepository creates a new instance of AnalyticsRepository
+func NewAnalyticsRepository(db *gorm.DB) AnalyticsRepository {
+	return &analyticsRepository{db: db}
+}
+
+// crushScope selects the filter's crushes as c, joined to their owners as u since crushes have no app_name
+// Named arguments come from filterArgs
+func crushScope(filter models.AnalyticsFilter) string {
+	scope := `FROM crushes c
+		JOIN users u ON u.id = c.user_id
+		WHERE c.deleted_at IS NULL AND c.created_at >= @from AND c.created_at < @to`
+	if filter.AppName != "" {
+		scope += " AND u.app_name = @app_name"
+	}
+	return scope
+}
+
+// filterArgs returns the named arguments of a filter; last is the final day of the range
+func filterArgs(filter models.AnalyticsFilter) map[string]interface{} {
+	return map[string]interface{}{
+		"from":     filter.From,
+		"to":       filter.To,
+		"app_name": filter.AppName,
+		"first":    filter.From.Format("2006-01-02"),
+		"last":     filter.To.AddDate(0, 0, -1).Format("2006-01-02"),
+	}
+}
+
+// dailySeries wraps a query of (day, count) rows so every day of the range is returned, empty days as 0
+func dailySeries(daily string) string {
+	return `WITH daily AS (` + daily + `)
+		SELECT to_char(s.day, 'YYYY-MM-DD') AS date, COALESCE(daily.count, 0) AS count
+		FROM generate_series(CAST(@first AS date), CAST(@last AS date), interval '1 day') AS s(day)
+		LEFT JOIN daily ON daily.day = CAST(s.day AS date)
+		ORDER BY s.day`
+}
+
+// CrushesPerDay counts crushes created on each day of the range
+func (r *analyticsRepository) CrushesPerDay(filter models.AnalyticsFilter) ([]models.DailyCount, error) {
+	query := dailySeries(`SELECT CAST(c.created_at AT TIME ZONE 'UTC' AS date) AS day, COUNT(*) AS count ` +
+		crushScope(filter) + ` GROUP BY 1`)
+
+	var days []models.DailyCount
+	if err := r.db.Raw(query, filterArgs(filter)).Scan(&days).Error; err != nil {
+		return nil, err
+	}
+	return days, nil
+}
+
+// MatchesPerDay counts matches made on each day of the range
+func (r *analyticsRepository) MatchesPerDay(filter models.AnalyticsFilter) ([]models.DailyCount, error) {
+	daily := `SELECT CAST(m.created_at AT TIME ZONE 'UTC' AS date) AS day, COUNT(*) AS count
+		FROM crush_matches m
+		WHERE m.created_at >= @from AND m.created_at < @to`
+	if filter.AppName != "" {
+		daily += " AND m.app_name = @app_name"
+	}
+	query := dailySeries(daily + ` GROUP BY 1`)
+
+	var days []models.DailyCount
+	if err := r.db.Raw(query, filterArgs(filter)).Scan(&days).Error; err != nil {
+		return nil, err
+	}
+	return days, nil
+}
+
+// ContactMethods counts the crushes naming a phone, an Instagram handle and a Snapchat handle
+// Crushes not yet encrypted are counted by their plaintext columns
+func (r *analyticsRepository) ContactMethods(filter models.AnalyticsFilter) (*models.ContactMethodCounts, error) {
+	query := `SELECT COUNT(*) AS total,
+		COUNT(*) FILTER (WHERE c.phone_encrypted IS NOT NULL OR c.phone IS NOT NULL) AS phone,
+		COUNT(*) FILTER (WHERE c.instagram_encrypted IS NOT NULL OR c.instagram_id IS NOT NULL) AS instagram,
+		COUNT(*) FILTER (WHERE c.snapchat_encrypted IS NOT NULL OR c.snapchat_id IS NOT NULL) AS snapchat ` +
+		crushScope(filter)
+
+	var counts models.ContactMethodCounts
+	if err := r.db.Raw(query, filterArgs(filter)).Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+	return &counts, nil
+}
+
+// Conversion compares when each crush was added with when its target first had an account in the owner's app
+// Targets are found through the shared blind indexes of users' phones and social handles,
+// so crushes and users that have not been indexed yet do not convert
+// Signups after the end of the range still count for crushes added within it
+func (r *analyticsRepository) Conversion(filter models.AnalyticsFilter) (*models.ConversionCounts, error) {
+	query := `SELECT COUNT(*) AS crushes,
+		COUNT(*) FILTER (WHERE t.first_signup <= c.created_at) AS already_registered,
+		COUNT(*) FILTER (WHERE t.first_signup > c.created_at) AS signed_up_after
+		FROM crushes c
+		JOIN users u ON u.id = c.user_id
+		LEFT JOIN LATERAL (
+			SELECT MIN(tu.created_at) AS first_signup
+			FROM users tu
+			WHERE tu.app_name = u.app_name AND tu.id <> c.user_id AND (
+				tu.phone_hash = c.phone_hash
+				OR tu.id IN (
+					SELECT i.user_id FROM user_identities i
+					WHERE i.app_name = u.app_name AND (
+						(i.type = @instagram AND i.value_hash = c.instagram_hash)
+						OR (i.type = @snapchat AND i.value_hash = c.snapchat_hash)
+					)
+				)
+			)
+		) t ON true
+		WHERE c.deleted_at IS NULL AND c.created_at >= @from AND c.created_at < @to`
+	if filter.AppName != "" {
+		query += " AND u.app_name = @app_name"
+	}
+
+	args := filterArgs(filter)
+	args["instagram"] = identifier.KindInstagram
+	args["snapchat"] = identifier.KindSnapchat
+
+	var counts models.ConversionCounts
+	if err := r.db.Raw(query, args).Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+	return &counts, nil
+}
+
+// TopCountryCodes returns the limit most common country codes of crush phones, most common first
+// Codes are compared as digits with a leading "+", so "91", "+91" and "0091" count together
+func (r *analyticsRepository) TopCountryCodes(filter models.AnalyticsFilter, limit int) ([]models.CountryCodeCount, error) {
+	query := `SELECT '+' || ltrim(regexp_replace(c.country_code, '[^0-9]', '', 'g'), '0') AS country_code, COUNT(*) AS count ` +
+		crushScope(filter) + `
+		AND ltrim(regexp_replace(c.country_code, '[^0-9]', '', 'g'), '0') <> ''
+		GROUP BY 1
+		ORDER BY count DESC, country_code ASC
+		LIMIT @limit`
+
+	args := filterArgs(filter)
+	args["limit"] = limit
+
+	var codes []models.CountryCodeCount
+	if err := r.db.Raw(query, args).Scan(&codes).Error; err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
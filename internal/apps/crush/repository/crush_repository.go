@@ -1,21 +1,41 @@
 package repository
 
 import (
+	"errors"
+	"time"
+
 	"go-backend/internal/apps/crush/models"
+	userModels "go-backend/internal/apps/user/models"
+	"go-backend/pkg/identifier"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // CrushRepository defines the interface for crush data operations
 type CrushRepository interface {
 	Create(crush *models.Crush) error
 	FindByID(id uuid.UUID) (*models.Crush, error)
+	FindByIDs(ids []uuid.UUID) ([]models.Crush, error)
 	FindByUserID(userID uuid.UUID) ([]models.Crush, error)
 	Update(crush *models.Crush) error
-	FindCrushesOnUser(countryCode, phone, instagramID, snapchatID *string) ([]models.Crush, error)
+	Delete(crush *models.Crush) error
+	DeleteByUserID(userID uuid.UUID) (int64, error)
+	FindCrushesOnUser(phone, instagram, snapchat *string) ([]models.Crush, error)
 	FindAllPaginated(page, pageSize int) ([]models.Crush, int64, error)
 	CountByUserID(userID uuid.UUID) (int64, error)
+	CountByUserIDs(userIDs []uuid.UUID) (map[uuid.UUID]int64, error)
+	CountActiveByUserID(userID uuid.UUID) (int64, error)
+	ExpireDue(now time.Time) (int64, error)
+	EncryptLegacyBatch(limit int) (int, error)
+	Transaction(fn func(repo CrushRepository) error) error
+	LockPair(userA, userB uuid.UUID) error
+	LockUser(userID uuid.UUID) error
+	FindUserIDsByIdentifiers(appName string, phone, instagram, snapchat *string) ([]uuid.UUID, error)
+	FindUserCrushesMatching(userID uuid.UUID, phone, instagram, snapchat *string) ([]models.Crush, error)
+	SaveMatch(match *models.CrushMatch) (bool, error)
+	FindMatchesByUserID(userID uuid.UUID) ([]models.CrushMatch, error)
 }
 
 // crushRepository implements CrushRepository
@@ -42,6 +62,18 @@ func (r *crushRepository) FindByID(id uuid.UUID) (*models.Crush, error) {
 	return &crush, nil
 }
 
+// FindByIDs retrieves the crushes with the given IDs in a single query; missing IDs are skipped
+func (r *crushRepository) FindByIDs(ids []uuid.UUID) ([]models.Crush, error) {
+	var crushes []models.Crush
+	if len(ids) == 0 {
+		return crushes, nil
+	}
+	if err := r.db.Where("id IN ?", ids).Find(&crushes).Error; err != nil {
+		return nil, err
+	}
+	return crushes, nil
+}
+
 // FindByUserID retrieves all crushes for a specific user
 func (r *crushRepository) FindByUserID(userID uuid.UUID) ([]models.Crush, error) {
 	var crushes []models.Crush
@@ -56,52 +88,82 @@ func (r *crushRepository) Update(crush *models.Crush) error {
 	return r.db.Save(crush).Error
 }
 
-// FindCrushesOnUser finds all crushes on a user by matching identifiers
-// Only non-nil identifiers are considered in the matching
-func (r *crushRepository) FindCrushesOnUser(countryCode, phone, instagramID, snapchatID *string) ([]models.Crush, error) {
-	var crushes []models.Crush
-	query := r.db.Model(&models.Crush{})
-
-	// Build OR conditions only for non-nil identifiers
-	var conditions []interface{}
+// Delete soft deletes a crush
+func (r *crushRepository) Delete(crush *models.Crush) error {
+	return r.db.Delete(crush).Error
+}
 
-	// Check phone match (both country code and phone must match)
-	if countryCode != nil && phone != nil && *countryCode != "" && *phone != "" {
-		conditions = append(conditions, r.db.Where("country_code = ? AND phone = ?", *countryCode, *phone))
-	}
+// DeleteByUserID soft deletes all crushes of a user
+// Returns the number of crushes deleted
+func (r *crushRepository) DeleteByUserID(userID uuid.UUID) (int64, error) {
+	result := r.db.Where("user_id = ?", userID).Delete(&models.Crush{})
+	return result.RowsAffected, result.Error
+}
 
-	// Check Instagram ID match
-	if instagramID != nil && *instagramID != "" {
-		conditions = append(conditions, r.db.Where("instagram_id = ?", *instagramID))
-	}
+// FindCrushesOnUser finds all active crushes on a user by matching normalized identifiers
+// (E.164 phone and canonical handles) against their blind indexes; only non-nil identifiers are considered in the matching
+// Crushes of suspended users are left out
+func (r *crushRepository) FindCrushesOnUser(phone, instagram, snapchat *string) ([]models.Crush, error) {
+	var crushes []models.Crush
 
-	// Check Snapchat ID match
-	if snapchatID != nil && *snapchatID != "" {
-		conditions = append(conditions, r.db.Where("snapchat_id = ?", *snapchatID))
+	condition, ok, err := r.identifierCondition(phone, instagram, snapchat)
+	if err != nil {
+		return nil, err
 	}
-
 	// If no valid identifiers provided, return empty list
-	if len(conditions) == 0 {
+	if !ok {
 		return crushes, nil
 	}
 
-	// Combine conditions with OR
-	for i, condition := range conditions {
-		if i == 0 {
-			query = query.Where(condition)
-		} else {
-			query = query.Or(condition)
-		}
-	}
-
 	// Order by creation time (most recent first)
-	if err := query.Order("created_at DESC").Find(&crushes).Error; err != nil {
+	if err := r.db.Model(&models.Crush{}).
+		Where("status = ?", models.CrushStatusActive).
+		Where("user_id NOT IN (?)", r.db.Table("users").Select("id").Where("suspended_at IS NOT NULL")).
+		Where(condition).
+		Order("created_at DESC").
+		Find(&crushes).Error; err != nil {
 		return nil, err
 	}
 
 	return crushes, nil
 }
 
+// identifierCondition builds an OR condition over the blind indexes of the non-empty normalized identifiers
+// Returns false if no valid identifier was provided
+func (r *crushRepository) identifierCondition(phone, instagram, snapchat *string) (*gorm.DB, bool, error) {
+	var conditions []*gorm.DB
+
+	for _, target := range []struct {
+		column string
+		kind   string
+		value  *string
+	}{
+		{"phone_hash", identifier.KindPhone, phone},             // Phone in E.164 form
+		{"instagram_hash", identifier.KindInstagram, instagram}, // Instagram handle
+		{"snapchat_hash", identifier.KindSnapchat, snapchat},    // Snapchat handle
+	} {
+		if target.value == nil || *target.value == "" {
+			continue
+		}
+		hash, err := identifier.BlindIndex(target.kind, *target.value)
+		if err != nil {
+			return nil, false, err
+		}
+		conditions = append(conditions, r.db.Where(target.column+" = ?", hash))
+	}
+
+	if len(conditions) == 0 {
+		return nil, false, nil
+	}
+
+	// Combine conditions with OR
+	condition := conditions[0]
+	for _, c := range conditions[1:] {
+		condition = condition.Or(c)
+	}
+	return condition, true, nil
+}
+
 // FindAllPaginated retrieves crushes with pagination
 func (r *crushRepository) FindAllPaginated(page, pageSize int) ([]models.Crush, int64, error) {
 	var crushes []models.Crush
@@ -131,3 +193,189 @@ func (r *crushRepository) CountByUserID(userID uuid.UUID) (int64, error) {
 	}
 	return count, nil
 }
+
+// CountByUserIDs counts the crushes of each of the given users in a single query
+// Users without crushes are absent from the result
+func (r *crushRepository) CountByUserIDs(userIDs []uuid.UUID) (map[uuid.UUID]int64, error) {
+	counts := make(map[uuid.UUID]int64, len(userIDs))
+	if len(userIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		UserID uuid.UUID
+		Count  int64
+	}
+	if err := r.db.Model(&models.Crush{}).
+		Select("user_id, COUNT(*) AS count").
+		Where("user_id IN ?", userIDs).
+		Group("user_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		counts[row.UserID] = row.Count
+	}
+	return counts, nil
+}
+
+// CountActiveByUserID counts the crushes of a user that are active
+func (r *crushRepository) CountActiveByUserID(userID uuid.UUID) (int64, error) {
+	var count int64
+	if err := r.db.Model(&models.Crush{}).Where("user_id = ? AND status = ?", userID, models.CrushStatusActive).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// ExpireDue marks active crushes whose expiry has passed as expired
+// Returns the number of crushes expired
+func (r *crushRepository) ExpireDue(now time.Time) (int64, error) {
+	result := r.db.Model(&models.Crush{}).
+		Where("status = ? AND expires_at <= ?", models.CrushStatusActive, now).
+		Update("status", models.CrushStatusExpired)
+	return result.RowsAffected, result.Error
+}
+
+// legacyTargetColumns are the columns written when encrypting a crush's plaintext target identifiers
+var legacyTargetColumns = []string{
+	"phone", "instagram_id", "snapchat_id",
+	"phone_encrypted", "instagram_encrypted", "snapchat_encrypted",
+	"phone_hash", "instagram_hash", "snapchat_hash",
+}
+
+// EncryptLegacyBatch re-saves up to limit crushes (including deleted ones) that still hold target identifiers in clear
+// Saving encrypts the identifiers, fills their blind indexes and clears the plaintext columns; the rows are locked
+// and no other column is written, so concurrent edits of the crushes are neither lost nor blocked on
+// Returns the number of crushes encrypted; 0 means none are left
+func (r *crushRepository) EncryptLegacyBatch(limit int) (int, error) {
+	var crushes []models.Crush
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("phone IS NOT NULL OR instagram_id IS NOT NULL OR snapchat_id IS NOT NULL").
+			Order("created_at ASC").
+			Limit(limit).
+			Find(&crushes).Error; err != nil {
+			return err
+		}
+		for i := range crushes {
+			if err := tx.Unscoped().Select(legacyTargetColumns).Save(&crushes[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(crushes), nil
+}
+
+// Transaction runs fn with a repository bound to a single database transaction
+func (r *crushRepository) Transaction(fn func(repo CrushRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&crushRepository{db: tx})
+	})
+}
+
+// LockPair takes a transaction-scoped advisory lock for an unordered pair of users
+// Concurrent crush writes between the same two users are serialized so a match cannot be missed
+func (r *crushRepository) LockPair(userA, userB uuid.UUID) error {
+	if userB.String() < userA.String() {
+		userA, userB = userB, userA
+	}
+	return lockKey(r.db, "crush_match:"+userA.String()+":"+userB.String())
+}
+
+// LockUser takes a transaction-scoped advisory lock for a user's crush slots
+// Concurrent creates and restores by the same user are serialized so the crush limit cannot be exceeded
+func (r *crushRepository) LockUser(userID uuid.UUID) error {
+	return lockKey(r.db, "crush_slots:"+userID.String())
+}
+
+// FindUserIDsByIdentifiers finds users of an app whose normalized phone or social identities match the given identifiers
+// Suspended users are left out so they take no part in matching
+func (r *crushRepository) FindUserIDsByIdentifiers(appName string, phone, instagram, snapchat *string) ([]uuid.UUID, error) {
+	var conditions []*gorm.DB
+	if phone != nil && *phone != "" {
+		conditions = append(conditions, r.db.Where("phone_normalized = ?", *phone))
+	}
+	if instagram != nil && *instagram != "" {
+		conditions = append(conditions, r.db.Where("id IN (?)", r.identityOwners(appName, userModels.IdentityTypeInstagram, *instagram)))
+	}
+	if snapchat != nil && *snapchat != "" {
+		conditions = append(conditions, r.db.Where("id IN (?)", r.identityOwners(appName, userModels.IdentityTypeSnapchat, *snapchat)))
+	}
+
+	var ids []uuid.UUID
+	if len(conditions) == 0 {
+		return ids, nil
+	}
+
+	condition := conditions[0]
+	for _, c := range conditions[1:] {
+		condition = condition.Or(c)
+	}
+
+	err := r.db.Table("users").
+		Where("app_name = ? AND deleted_at IS NULL AND suspended_at IS NULL", appName).
+		Where(condition).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// identityOwners builds a subquery selecting the user holding a verified social identity in an app
+// Unverified claims take no part in matching
+func (r *crushRepository) identityOwners(appName, identityType, value string) *gorm.DB {
+	return r.db.Table("user_identities").Select("user_id").Where("app_name = ? AND type = ? AND value = ? AND verified = true", appName, identityType, value)
+}
+
+// FindUserCrushesMatching finds a user's active crushes that target any of the given normalized identifiers
+func (r *crushRepository) FindUserCrushesMatching(userID uuid.UUID, phone, instagram, snapchat *string) ([]models.Crush, error) {
+	var crushes []models.Crush
+
+	condition, ok, err := r.identifierCondition(phone, instagram, snapchat)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return crushes, nil
+	}
+
+	if err := r.db.Where("user_id = ? AND status = ?", userID, models.CrushStatusActive).Where(condition).Order("created_at ASC").Find(&crushes).Error; err != nil {
+		return nil, err
+	}
+	return crushes, nil
+}
+
+// SaveMatch records a match and reports whether it is new; for an existing pair only the crush entries are refreshed
+// Callers must hold the pair lock (LockPair) so the lookup and insert cannot race
+func (r *crushRepository) SaveMatch(match *models.CrushMatch) (bool, error) {
+	var existing models.CrushMatch
+	err := r.db.Where("user_a_id = ? AND user_b_id = ?", match.UserAID, match.UserBID).First(&existing).Error
+	if err == nil {
+		if err := r.db.Model(&existing).Updates(map[string]interface{}{
+			"crush_a_id": match.CrushAID,
+			"crush_b_id": match.CrushBID,
+		}).Error; err != nil {
+			return false, err
+		}
+		*match = existing
+		return false, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, err
+	}
+	return true, r.db.Create(match).Error
+}
+
+// FindMatchesByUserID retrieves all matches the user is part of
+func (r *crushRepository) FindMatchesByUserID(userID uuid.UUID) ([]models.CrushMatch, error) {
+	var matches []models.CrushMatch
+	if err := r.db.Where("user_a_id = ? OR user_b_id = ?", userID, userID).Order("created_at DESC").Find(&matches).Error; err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
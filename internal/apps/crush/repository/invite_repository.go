@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"hash/fnv"
+	"time"
+
+	"go-backend/internal/apps/crush/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// InviteRepository defines data operations for crush invites and opt-outs
+type InviteRepository interface {
+	Reserve(invite *models.CrushInvite, dedupSince time.Time, senderCap int, senderSince time.Time) (string, error)
+	UpdateStatus(id uuid.UUID, status, provider, errMsg string) error
+	IsOptedOut(targetHash string) (bool, error)
+	CreateOptOut(optOut *models.InviteOptOut) error
+	DeleteOptOut(targetHash string) error
+}
+
+// inviteRepository implements InviteRepository
+type inviteRepository struct {
+	db *gorm.DB
+}
+
+// NewInviteRepository creates a new instance of InviteRepository
+func NewInviteRepository(db *gorm.DB) InviteRepository {
+	return &inviteRepository{db: db}
+}
+
+// Reserve stores a pending invite unless the target was already invited by the app since dedupSince,
+// or the sender already triggered senderCap invites since senderSince
+// Advisory locks on the sender and the target make the checks and insert atomic across concurrent requests
+// Failed invites count towards neither limit
+func (r *inviteRepository) Reserve(invite *models.CrushInvite, dedupSince time.Time, senderCap int, senderSince time.Time) (string, error) {
+	outcome := models.InviteReserved
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Always lock the sender before the target so concurrent reservations cannot deadlock
+		if err := lockKey(tx, "crush_invite_sender:"+invite.SenderUserID.String()); err != nil {
+			return err
+		}
+		if err := lockKey(tx, "crush_invite_target:"+invite.AppName+":"+invite.TargetHash); err != nil {
+			return err
+		}
+
+		var count int64
+		if err := tx.Model(&models.CrushInvite{}).
+			Where("app_name = ? AND target_hash = ? AND status <> ? AND created_at >= ?", invite.AppName, invite.TargetHash, models.InviteStatusFailed, dedupSince).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			outcome = models.InviteDuplicate
+			return nil
+		}
+
+		if err := tx.Model(&models.CrushInvite{}).
+			Where("sender_user_id = ? AND status <> ? AND created_at >= ?", invite.SenderUserID, models.InviteStatusFailed, senderSince).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count >= int64(senderCap) {
+			outcome = models.InviteSenderCapped
+			return nil
+		}
+
+		invite.Status = models.InviteStatusPending
+		return tx.Create(invite).Error
+	})
+	return outcome, err
+}
+
+// lockKey takes a transaction-scoped advisory lock derived from the given name
+func lockKey(tx *gorm.DB, name string) error {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	return tx.Exec("SELECT pg_advisory_xact_lock(?)", int64(h.Sum64())).Error
+}
+
+// UpdateStatus records the outcome of sending an invite
+func (r *inviteRepository) UpdateStatus(id uuid.UUID, status, provider, errMsg string) error {
+	return r.db.Model(&models.CrushInvite{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"status": status, "provider": provider, "error": errMsg}).Error
+}
+
+// IsOptedOut reports whether the target has opted out of invites
+func (r *inviteRepository) IsOptedOut(targetHash string) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.InviteOptOut{}).Where("target_hash = ?", targetHash).Count(&count).Error
+	return count > 0, err
+}
+
+// CreateOptOut records an opt-out; repeated opt-outs are ignored
+func (r *inviteRepository) CreateOptOut(optOut *models.InviteOptOut) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(optOut).Error
+}
+
+// DeleteOptOut removes an opt-out (e.g. after the target replies START)
+func (r *inviteRepository) DeleteOptOut(targetHash string) error {
+	return r.db.Where("target_hash = ?", targetHash).Delete(&models.InviteOptOut{}).Error
+}
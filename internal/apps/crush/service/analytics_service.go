@@ -0,0 +1,198 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"go-backend/internal/apps/crush/models"
+	"go-backend/internal/apps/crush/repository"
+)
+
+// Analytics date ranges are whole UTC days
+const (
+	analyticsDateLayout  = "2006-01-02"
+	defaultAnalyticsDays = 30
+	maxAnalyticsDays     = 366
+	defaultCountryCodes  = 10
+	maxCountryCodes      = 100
+	conversionRatePlaces = 10000 // Conversion rates are rounded to 4 decimal places
+)
+
+// AnalyticsService defines Crush Connect analytics for the admin dashboard
+// from and to are inclusive YYYY-MM-DD dates; they default to the last 30 days
+type AnalyticsService interface {
+	CrushesPerDay(appName, from, to string) (*models.DailyCountsResponse, error)
+	MatchesPerDay(appName, from, to string) (*models.DailyCountsResponse, error)
+	ContactMethods(appName, from, to string) (*models.ContactMethodsResponse, error)
+	Conversion(appName, from, to string) (*models.ConversionResponse, error)
+	TopCountryCodes(appName, from, to string, limit int) (*models.CountryCodesResponse, error)
+}
+
+// analyticsService implements AnalyticsService
+type analyticsService struct {
+	repo repository.AnalyticsRepository
+}
+
+// NewAnalyticsService creates a new instance of AnalyticsService
+func NewAnalyticsService(repo repository.AnalyticsRepository) AnalyticsService {
+	return &analyticsService{repo: repo}
+}
+
+// CrushesPerDay counts crushes added on each day of the range
+func (s *analyticsService) CrushesPerDay(appName, from, to string) (*models.DailyCountsResponse, error) {
+	filter, err := parseAnalyticsFilter(appName, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	days, err := s.repo.CrushesPerDay(filter)
+	if err != nil {
+		return nil, err
+	}
+	return dailyCountsResponse(filter, days), nil
+}
+
+// MatchesPerDay counts matches made on each day of the range
+func (s *analyticsService) MatchesPerDay(appName, from, to string) (*models.DailyCountsResponse, error) {
+	filter, err := parseAnalyticsFilter(appName, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	days, err := s.repo.MatchesPerDay(filter)
+	if err != nil {
+		return nil, err
+	}
+	return dailyCountsResponse(filter, days), nil
+}
+
+// ContactMethods counts crushes by the contact methods they name
+func (s *analyticsService) ContactMethods(appName, from, to string) (*models.ContactMethodsResponse, error) {
+	filter, err := parseAnalyticsFilter(appName, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := s.repo.ContactMethods(filter)
+	if err != nil {
+		return nil, err
+	}
+	return &models.ContactMethodsResponse{
+		AnalyticsRange:      analyticsRange(filter),
+		ContactMethodCounts: *counts,
+	}, nil
+}
+
+// Conversion measures how many crush targets without an account signed up after the crush
+func (s *analyticsService) Conversion(appName, from, to string) (*models.ConversionResponse, error) {
+	filter, err := parseAnalyticsFilter(appName, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := s.repo.Conversion(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	rate := 0.0
+	if unregistered := counts.Crushes - counts.AlreadyRegistered; unregistered > 0 {
+		rate = math.Round(float64(counts.SignedUpAfter)/float64(unregistered)*conversionRatePlaces) / conversionRatePlaces
+	}
+
+	return &models.ConversionResponse{
+		AnalyticsRange:   analyticsRange(filter),
+		ConversionCounts: *counts,
+		ConversionRate:   rate,
+	}, nil
+}
+
+// TopCountryCodes returns the most common crush country codes; limit defaults to 10
+func (s *analyticsService) TopCountryCodes(appName, from, to string, limit int) (*models.CountryCodesResponse, error) {
+	filter, err := parseAnalyticsFilter(appName, from, to)
+	if err != nil {
+		return nil, err
+	}
+	if limit < 1 {
+		limit = defaultCountryCodes
+	}
+	if limit > maxCountryCodes {
+		limit = maxCountryCodes
+	}
+
+	codes, err := s.repo.TopCountryCodes(filter, limit)
+	if err != nil {
+		return nil, err
+	}
+	if codes == nil {
+		codes = []models.CountryCodeCount{}
+	}
+
+	return &models.CountryCodesResponse{
+		AnalyticsRange: analyticsRange(filter),
+		CountryCodes:   codes,
+	}, nil
+}
+
+// parseAnalyticsFilter turns inclusive from and to dates into a half-open UTC range
+// A missing to defaults to today and a missing from to 30 days before to
+func parseAnalyticsFilter(appName, from, to string) (models.AnalyticsFilter, error) {
+	end := time.Now().UTC().Truncate(24 * time.Hour)
+	if to != "" {
+		parsed, err := time.Parse(analyticsDateLayout, to)
+		if err != nil {
+			return models.AnalyticsFilter{}, errors.New("invalid to date")
+		}
+		end = parsed
+	}
+
+	start := end.AddDate(0, 0, -(defaultAnalyticsDays - 1))
+	if from != "" {
+		parsed, err := time.Parse(analyticsDateLayout, from)
+		if err != nil {
+			return models.AnalyticsFilter{}, errors.New("invalid from date")
+		}
+		start = parsed
+	}
+
+	if start.After(end) {
+		return models.AnalyticsFilter{}, errors.New("from must not be after to")
+	}
+	if end.Sub(start) >= maxAnalyticsDays*24*time.Hour {
+		return models.AnalyticsFilter{}, errors.New("date range cannot exceed 366 days")
+	}
+
+	return models.AnalyticsFilter{
+		AppName: appName,
+		From:    start,
+		To:      end.AddDate(0, 0, 1),
+	}, nil
+}
+
+// analyticsRange describes a filter with inclusive dates
+func analyticsRange(filter models.AnalyticsFilter) models.AnalyticsRange {
+	return models.AnalyticsRange{
+		AppName: filter.AppName,
+		From:    filter.From.Format(analyticsDateLayout),
+		To:      filter.To.AddDate(0, 0, -1).Format(analyticsDateLayout),
+	}
+}
+
+// dailyCountsResponse builds a per-day series response with its total
+func dailyCountsResponse(filter models.AnalyticsFilter, days []models.DailyCount) *models.DailyCountsResponse {
+	if days == nil {
+		days = []models.DailyCount{}
+	}
+
+	var total int64
+	for _, day := range days {
+		total += day.Count
+	}
+
+	return &models.DailyCountsResponse{
+		AnalyticsRange: analyticsRange(filter),
+		Total:          total,
+		Days:           days,
+	}
+}
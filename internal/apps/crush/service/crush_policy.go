@@ -0,0 +1,89 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Features gated behind a subscription
+const (
+	FeatureCrushSlots   = "crush_slots"   // Active crushes beyond the free limit
+	FeatureCrushReveals = "crush_reveals" // Details of who has a crush on the user
+)
+
+// CrushPolicy holds the per-app rules for crush entries
+// Crush limits of 0 mean unlimited
+type CrushPolicy struct {
+	ExpireAfterDays            int  `json:"expire_after_days"`            // Active crushes expire this many days after creation or restore; 0 keeps them forever
+	FreeCrushLimit             int  `json:"free_crush_limit"`             // Active crushes a user without a subscription may have
+	SubscriberCrushLimit       int  `json:"subscriber_crush_limit"`       // Active crushes a subscriber may have
+	RevealsRequireSubscription bool `json:"reveals_require_subscription"` // Only subscribers see who has a crush on them
+}
+
+// CrushLimit returns the active crush limit for the tier
+func (p CrushPolicy) CrushLimit(subscribed bool) int {
+	if subscribed {
+		return p.SubscriberCrushLimit
+	}
+	return p.FreeCrushLimit
+}
+
+// UpgradeRequiredError is returned when a feature needs an active subscription
+// Clients use it to prompt the user to subscribe
+type UpgradeRequiredError struct {
+	Feature string `json:"feature"`
+	Limit   int    `json:"limit,omitempty"`  // Free tier limit that was reached
+	Used    int64  `json:"used,omitempty"`   // Usage counted against the limit
+	Locked  int    `json:"locked,omitempty"` // Items hidden until the user subscribes
+}
+
+// Error implements the error interface
+func (e *UpgradeRequiredError) Error() string {
+	return "upgrade_required"
+}
+
+// ExpiresAt returns when a crush activated at from expires under the policy, or nil if it never does
+func (p CrushPolicy) ExpiresAt(from time.Time) *time.Time {
+	if p.ExpireAfterDays <= 0 {
+		return nil
+	}
+	expiresAt := from.Add(time.Duration(p.ExpireAfterDays) * 24 * time.Hour)
+	return &expiresAt
+}
+
+// CrushPolicies resolves crush rules per app
+type CrushPolicies map[string]CrushPolicy
+
+// For returns the app's crush policy; apps without configuration use the zero policy
+func (p CrushPolicies) For(appName string) CrushPolicy {
+	return p[appName]
+}
+
+// ParseCrushPolicies parses per-app crush rules, e.g. {"apps":{"app":{"expire_after_days":90,"free_crush_limit":3}}}
+func ParseCrushPolicies(raw string) (CrushPolicies, error) {
+	var parsed struct {
+		Apps map[string]CrushPolicy `json:"apps"`
+	}
+	if raw != "" {
+		if err := json.Unmarshal([]byte(raw), &parsed); err != nil {
+			return nil, fmt.Errorf("invalid crush policy configuration: %w", err)
+		}
+	}
+
+	policies := make(CrushPolicies, len(parsed.Apps))
+	for appName, policy := range parsed.Apps {
+		if policy.ExpireAfterDays < 0 {
+			return nil, fmt.Errorf("expire_after_days must not be negative for %s", appName)
+		}
+		if policy.FreeCrushLimit < 0 || policy.SubscriberCrushLimit < 0 {
+			return nil, fmt.Errorf("crush limits must not be negative for %s", appName)
+		}
+		// A subscription must never lower the limit
+		if policy.SubscriberCrushLimit > 0 && (policy.FreeCrushLimit == 0 || policy.SubscriberCrushLimit < policy.FreeCrushLimit) {
+			return nil, fmt.Errorf("subscriber_crush_limit must be at least free_crush_limit for %s", appName)
+		}
+		policies[appName] = policy
+	}
+	return policies, nil
+}
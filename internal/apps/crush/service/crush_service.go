@@ -1,13 +1,21 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"sort"
 	"strings"
+	"time"
 
 	"go-backend/internal/apps/crush/models"
 	"go-backend/internal/apps/crush/repository"
+	moderationRepository "go-backend/internal/apps/moderation/repository"
+	notificationModels "go-backend/internal/apps/notification/models"
+	subscriptionRepository "go-backend/internal/apps/razorpay/subscription/repository"
 	userModels "go-backend/internal/apps/user/models"
 	userRepository "go-backend/internal/apps/user/repository"
+	"go-backend/pkg/identifier"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -17,24 +25,125 @@ import (
 type CrushService interface {
 	CreateCrush(req models.CreateCrushRequest) (*models.CrushResponse, error)
 	UpdateCrush(id uuid.UUID, req models.UpdateCrushRequest) (*models.CrushResponse, error)
+	DeleteCrush(id uuid.UUID) (*models.DeleteCrushResponse, error)
+	ArchiveCrush(id uuid.UUID) (*models.CrushResponse, error)
+	RestoreCrush(id uuid.UUID) (*models.CrushResponse, error)
+	ExpireCrushes() (int64, error)
+	EncryptLegacyCrushes(ctx context.Context) (int64, error)
 	GetCrushByID(id uuid.UUID) (*models.CrushResponse, error)
 	ListCrushesByUserID(userID uuid.UUID) ([]models.CrushResponse, error)
 	ListCrushesOnUser(userID uuid.UUID) ([]models.CrushOnUserResponse, error)
-	ListAllCrushesPaginated(page, pageSize int) (*models.PaginatedCrushesResponse, error)
+	ListAllCrushesPaginated(page, pageSize int, reveal bool) (*models.PaginatedCrushesResponse, error)
+	ListMatches(userID uuid.UUID) ([]models.CrushMatchResponse, error)
+}
+
+// Notifier delivers crush events to users
+type Notifier interface {
+	Notify(userID uuid.UUID, appName, notificationType string, payload map[string]interface{}) error
 }
 
 // crushService implements CrushService
 type crushService struct {
-	repo     repository.CrushRepository
-	userRepo userRepository.UserRepository
+	repo             repository.CrushRepository
+	userRepo         userRepository.UserRepository
+	notifier         Notifier
+	inviter          InviteService
+	policies         CrushPolicies
+	subscriptionRepo subscriptionRepository.SubscriptionRepository
+	identityRepo     userRepository.IdentityRepository
+	blockRepo        moderationRepository.BlockRepository
 }
 
 // NewCrushService creates a new instance of CrushService
-func NewCrushService(repo repository.CrushRepository, userRepo userRepository.UserRepository) CrushService {
+// subscriptionRepo decides the user's tier for the app's crush limits and reveals;
+// identityRepo provides the social identities users are matched on; blockRepo the blocks between users
+func NewCrushService(repo repository.CrushRepository, userRepo userRepository.UserRepository, identityRepo userRepository.IdentityRepository, blockRepo moderationRepository.BlockRepository, notifier Notifier, inviter InviteService, policies CrushPolicies, subscriptionRepo subscriptionRepository.SubscriptionRepository) CrushService {
 	return &crushService{
-		repo:     repo,
-		userRepo: userRepo,
+		repo:             repo,
+		userRepo:         userRepo,
+		notifier:         notifier,
+		inviter:          inviter,
+		policies:         policies,
+		subscriptionRepo: subscriptionRepo,
+		identityRepo:     identityRepo,
+		blockRepo:        blockRepo,
+	}
+}
+
+// findActiveUser loads a user, rejecting users suspended by moderation
+func (s *crushService) findActiveUser(userID uuid.UUID) (*userModels.User, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+	if user.IsSuspended() {
+		return nil, errors.New("user is suspended")
+	}
+	return user, nil
+}
+
+// blockedUsers returns which of the other users have a block with the user, in either direction
+func (s *crushService) blockedUsers(userID uuid.UUID, otherIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	blocks, err := s.blockRepo.FindBetween(userID, otherIDs)
+	if err != nil {
+		return nil, err
+	}
+	blocked := make(map[uuid.UUID]bool, len(blocks))
+	for _, block := range blocks {
+		if block.BlockerID == userID {
+			blocked[block.BlockedID] = true
+			continue
+		}
+		blocked[block.BlockerID] = true
+	}
+	return blocked, nil
+}
+
+// isSubscriber reports whether the user has an active subscription for their app
+func (s *crushService) isSubscriber(user *userModels.User) (bool, error) {
+	_, err := s.subscriptionRepo.FindActiveByUserIDAndAppName(user.ID, user.AppName)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// checkCrushSlot ensures the user can have one more active crush under the app's tier limits
+// Must run inside the transaction that activates the crush, after LockUser
+func (s *crushService) checkCrushSlot(repo repository.CrushRepository, user *userModels.User) error {
+	policy := s.policies.For(user.AppName)
+	if policy.FreeCrushLimit == 0 && policy.SubscriberCrushLimit == 0 {
+		return nil
+	}
+
+	subscribed, err := s.isSubscriber(user)
+	if err != nil {
+		return err
+	}
+	limit := policy.CrushLimit(subscribed)
+	if limit == 0 {
+		return nil
+	}
+
+	used, err := repo.CountActiveByUserID(user.ID)
+	if err != nil {
+		return err
+	}
+	if used < int64(limit) {
+		return nil
+	}
+
+	// Subscribers, or apps without a higher paid limit, have nothing to upgrade to
+	if subscribed || (policy.SubscriberCrushLimit > 0 && policy.SubscriberCrushLimit <= limit) {
+		return errors.New("crush limit reached")
 	}
+	return &UpgradeRequiredError{Feature: FeatureCrushSlots, Limit: limit, Used: used}
 }
 
 // validateContactMethod ensures at least one contact method is provided
@@ -62,35 +171,48 @@ func validateContactMethod(countryCode, phone, instagramID, snapchatID *string)
 	return nil
 }
 
-// isSelfCrush checks if the crush identifiers match the user's own identifiers
-func isSelfCrush(user *userModels.User, crushCountryCode, crushPhone, crushInstagramID, crushSnapchatID *string) bool {
-	// Check phone match
-	if crushCountryCode != nil && crushPhone != nil && *crushCountryCode != "" && *crushPhone != "" {
-		if user.CountryCode != nil && user.Phone != nil &&
-			*user.CountryCode == *crushCountryCode && *user.Phone == *crushPhone {
-			return true
+// validateIdentifierFormats ensures the provided phone number and social handles can be normalized
+func validateIdentifierFormats(countryCode, phone, instagramID, snapchatID *string) error {
+	if phone != nil && strings.TrimSpace(*phone) != "" {
+		cc := ""
+		if countryCode != nil {
+			cc = *countryCode
+		}
+		if _, err := identifier.ParsePhone(cc, *phone); err != nil {
+			return err
 		}
 	}
-
-	// Check Instagram ID match from user metadata
-	if crushInstagramID != nil && *crushInstagramID != "" && user.Metadata != nil {
-		if userInsta, ok := user.Metadata["instagram_id"].(string); ok && userInsta != "" {
-			if userInsta == *crushInstagramID {
-				return true
-			}
+	if instagramID != nil && strings.TrimSpace(*instagramID) != "" {
+		if _, err := identifier.NormalizeHandle(*instagramID); err != nil {
+			return errors.New("invalid instagram_id")
 		}
 	}
-
-	// Check Snapchat ID match from user metadata
-	if crushSnapchatID != nil && *crushSnapchatID != "" && user.Metadata != nil {
-		if userSnap, ok := user.Metadata["snapchat_id"].(string); ok && userSnap != "" {
-			if userSnap == *crushSnapchatID {
-				return true
-			}
+	if snapchatID != nil && strings.TrimSpace(*snapchatID) != "" {
+		if _, err := identifier.NormalizeHandle(*snapchatID); err != nil {
+			return errors.New("invalid snapchat_id")
 		}
 	}
+	return nil
+}
 
-	return false
+// userIdentifiers holds the normalized identifiers a user can be matched on
+type userIdentifiers struct {
+	Phone     *string
+	Instagram *string
+	Snapchat  *string
+}
+
+// identifiersMatchUser checks if the crush's normalized identifiers match the user's own
+// The crush must have been normalized (NormalizeIdentifiers) first
+func identifiersMatchUser(ids userIdentifiers, crush *models.Crush) bool {
+	return sameIdentifier(ids.Phone, crush.PhoneNormalized) ||
+		sameIdentifier(ids.Instagram, crush.InstagramNormalized) ||
+		sameIdentifier(ids.Snapchat, crush.SnapchatNormalized)
+}
+
+// sameIdentifier reports whether two normalized identifiers are present and equal
+func sameIdentifier(a, b *string) bool {
+	return a != nil && b != nil && *a != "" && *a == *b
 }
 
 // CreateCrush creates a new crush entry
@@ -102,22 +224,17 @@ func (s *crushService) CreateCrush(req models.CreateCrushRequest) (*models.Crush
 	}
 
 	// Prevent users from adding themselves as a crush
-	user, err := s.userRepo.FindByID(req.UserID)
+	user, err := s.findActiveUser(req.UserID)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("user not found")
-		}
 		return nil, err
 	}
 
-	// Check if crush identifiers match the user's own identifiers
-	if isSelfCrush(user, req.CountryCode, req.Phone, req.InstagramID, req.SnapchatID) {
-		return nil, errors.New("you cannot add yourself as a crush")
-	}
-
 	if err := validateContactMethod(req.CountryCode, req.Phone, req.InstagramID, req.SnapchatID); err != nil {
 		return nil, err
 	}
+	if err := validateIdentifierFormats(req.CountryCode, req.Phone, req.InstagramID, req.SnapchatID); err != nil {
+		return nil, err
+	}
 
 	// Build model
 	crush := &models.Crush{
@@ -128,11 +245,53 @@ func (s *crushService) CreateCrush(req models.CreateCrushRequest) (*models.Crush
 		InstagramID: req.InstagramID,
 		SnapchatID:  req.SnapchatID,
 		Metadata:    req.Metadata,
+		Status:      models.CrushStatusActive,
+		ExpiresAt:   s.policies.For(user.AppName).ExpiresAt(time.Now()),
 	}
+	crush.NormalizeIdentifiers()
 
-	if err := s.repo.Create(crush); err != nil {
+	// Check if crush identifiers match the user's own identifiers
+	ids, err := s.userIdentifiers(user)
+	if err != nil {
 		return nil, err
 	}
+	if identifiersMatchUser(ids, crush) {
+		return nil, errors.New("you cannot add yourself as a crush")
+	}
+
+	// Create the crush and record any resulting match atomically
+	var targetIDs []uuid.UUID
+	var matches []*models.CrushMatch
+	err = s.repo.Transaction(func(repo repository.CrushRepository) error {
+		if err := repo.LockUser(user.ID); err != nil {
+			return err
+		}
+		if err := s.checkCrushSlot(repo, user); err != nil {
+			return err
+		}
+		if err := repo.Create(crush); err != nil {
+			return err
+		}
+		targetIDs, matches, err = s.recordMatches(repo, user, ids, crush)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Notify only after commit so clients never see events for rolled back writes
+	s.notifyMatches(user, matches)
+	s.notifyCrushReceived(user, targetIDs, matches)
+
+	// Invites are sent in the background so the response never reveals whether one went out
+	if req.Invite {
+		go func() {
+			if err := s.inviter.InviteIfUnregistered(user, crush); err != nil {
+				fmt.Printf("[CrushInvite] Invite for crush %s failed: %v\n", crush.ID, err)
+			}
+		}()
+	}
+
 	resp := crush.ToResponse()
 	return &resp, nil
 }
@@ -181,29 +340,194 @@ func (s *crushService) UpdateCrush(id uuid.UUID, req models.UpdateCrushRequest)
 	if err := validateContactMethod(crush.CountryCode, crush.Phone, crush.InstagramID, crush.SnapchatID); err != nil {
 		return nil, err
 	}
+	if err := validateIdentifierFormats(crush.CountryCode, crush.Phone, crush.InstagramID, crush.SnapchatID); err != nil {
+		return nil, err
+	}
+	crush.NormalizeIdentifiers()
 
 	// Prevent users from updating crush to match their own identifiers
-	user, err := s.userRepo.FindByID(crush.UserID)
+	user, err := s.findActiveUser(crush.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids, err := s.userIdentifiers(user)
+	if err != nil {
+		return nil, err
+	}
+	if identifiersMatchUser(ids, crush) {
+		return nil, errors.New("you cannot add yourself as a crush")
+	}
+
+	// Save the crush and record any resulting match atomically
+	// Archived and expired crushes can still be edited but do not match until restored
+	var matches []*models.CrushMatch
+	err = s.repo.Transaction(func(repo repository.CrushRepository) error {
+		if err := repo.Update(crush); err != nil {
+			return err
+		}
+		if !crush.IsActive() {
+			return nil
+		}
+		_, matches, err = s.recordMatches(repo, user, ids, crush)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifyMatches(user, matches)
+
+	resp := crush.ToResponse()
+	return &resp, nil
+}
+
+// DeleteCrush soft deletes a crush entry and reports how many slots it freed
+func (s *crushService) DeleteCrush(id uuid.UUID) (*models.DeleteCrushResponse, error) {
+	crush, err := s.repo.FindByID(id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("user not found")
+			return nil, errors.New("crush not found")
 		}
 		return nil, err
 	}
+	if _, err := s.findActiveUser(crush.UserID); err != nil {
+		return nil, err
+	}
 
-	if isSelfCrush(user, crush.CountryCode, crush.Phone, crush.InstagramID, crush.SnapchatID) {
-		return nil, errors.New("you cannot add yourself as a crush")
+	if err := s.repo.Delete(crush); err != nil {
+		return nil, err
+	}
+
+	// Only active crushes occupy a slot
+	slotsFreed := 0
+	if crush.IsActive() {
+		slotsFreed = 1
+	}
+	activeCrushes, err := s.repo.CountActiveByUserID(crush.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.DeleteCrushResponse{
+		ID:            crush.ID,
+		SlotsFreed:    slotsFreed,
+		ActiveCrushes: activeCrushes,
+	}, nil
+}
+
+// ArchiveCrush hides an active crush from matching without deleting it
+func (s *crushService) ArchiveCrush(id uuid.UUID) (*models.CrushResponse, error) {
+	crush, err := s.repo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("crush not found")
+		}
+		return nil, err
+	}
+	if _, err := s.findActiveUser(crush.UserID); err != nil {
+		return nil, err
+	}
+
+	if !crush.IsActive() {
+		return nil, errors.New("only active crushes can be archived")
 	}
 
+	crush.Status = models.CrushStatusArchived
+	crush.ExpiresAt = nil
 	if err := s.repo.Update(crush); err != nil {
 		return nil, err
 	}
+
+	resp := crush.ToResponse()
+	return &resp, nil
+}
+
+// RestoreCrush makes an archived or expired crush active again with a fresh expiry window
+func (s *crushService) RestoreCrush(id uuid.UUID) (*models.CrushResponse, error) {
+	crush, err := s.repo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("crush not found")
+		}
+		return nil, err
+	}
+
+	if crush.IsActive() {
+		return nil, errors.New("crush is already active")
+	}
+
+	user, err := s.findActiveUser(crush.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids, err := s.userIdentifiers(user)
+	if err != nil {
+		return nil, err
+	}
+
+	crush.Status = models.CrushStatusActive
+	crush.ExpiresAt = s.policies.For(user.AppName).ExpiresAt(time.Now())
+
+	// Restoring rejoins matching, so record any match that formed meanwhile
+	var matches []*models.CrushMatch
+	err = s.repo.Transaction(func(repo repository.CrushRepository) error {
+		if err := repo.LockUser(user.ID); err != nil {
+			return err
+		}
+		if err := s.checkCrushSlot(repo, user); err != nil {
+			return err
+		}
+		if err := repo.Update(crush); err != nil {
+			return err
+		}
+		_, matches, err = s.recordMatches(repo, user, ids, crush)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifyMatches(user, matches)
+
 	resp := crush.ToResponse()
 	return &resp, nil
 }
 
+// ExpireCrushes marks active crushes past their expiry as expired
+// Returns the number of crushes expired
+func (s *crushService) ExpireCrushes() (int64, error) {
+	return s.repo.ExpireDue(time.Now())
+}
+
+// legacyCrushBatchSize is the number of plaintext crushes encrypted per transaction
+const legacyCrushBatchSize = 500
+
+// EncryptLegacyCrushes encrypts crushes still holding target identifiers in clear, in batches until none are left
+// Crushes are only matched through their blind indexes, so this runs as a job right after the deploy
+// Returns the number of crushes encrypted
+func (s *crushService) EncryptLegacyCrushes(ctx context.Context) (int64, error) {
+	var total int64
+	for ctx.Err() == nil {
+		n, err := s.repo.EncryptLegacyBatch(legacyCrushBatchSize)
+		if err != nil {
+			return total, err
+		}
+		if n == 0 {
+			return total, nil
+		}
+		total += int64(n)
+	}
+	return total, ctx.Err()
+}
+
 // ListCrushesByUserID retrieves all crushes for a specific user
 func (s *crushService) ListCrushesByUserID(userID uuid.UUID) ([]models.CrushResponse, error) {
+	if _, err := s.findActiveUser(userID); err != nil {
+		return nil, err
+	}
+
 	crushes, err := s.repo.FindByUserID(userID)
 	if err != nil {
 		return nil, err
@@ -216,7 +540,7 @@ func (s *crushService) ListCrushesByUserID(userID uuid.UUID) ([]models.CrushResp
 	return responses, nil
 }
 
-// GetCrushByID retrieves a crush by its ID
+// GetCrushByID retrieves a crush by its ID; crushes of suspended users are not available to them
 func (s *crushService) GetCrushByID(id uuid.UUID) (*models.CrushResponse, error) {
 	crush, err := s.repo.FindByID(id)
 	if err != nil {
@@ -225,38 +549,59 @@ func (s *crushService) GetCrushByID(id uuid.UUID) (*models.CrushResponse, error)
 		}
 		return nil, err
 	}
+	if _, err := s.findActiveUser(crush.UserID); err != nil {
+		return nil, err
+	}
 	resp := crush.ToResponse()
 	return &resp, nil
 }
 
 // ListCrushesOnUser lists all people who have a crush on the user
-// Matches based on user's phone, Instagram ID, or Snapchat ID
+// Matches based on user's phone, Instagram ID, or Snapchat ID; crushes of blocked users are omitted
 func (s *crushService) ListCrushesOnUser(userID uuid.UUID) ([]models.CrushOnUserResponse, error) {
 	// Get user details
-	user, err := s.userRepo.FindByID(userID)
+	user, err := s.findActiveUser(userID)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("user not found")
-		}
 		return nil, err
 	}
 
-	// Extract Instagram and Snapchat IDs from metadata if present
-	var instagramID, snapchatID *string
-	if user.Metadata != nil {
-		if insta, ok := user.Metadata["instagram_id"].(string); ok && insta != "" {
-			instagramID = &insta
-		}
-		if snap, ok := user.Metadata["snapchat_id"].(string); ok && snap != "" {
-			snapchatID = &snap
-		}
+	// Find all crushes matching any of the user's normalized identifiers
+	ids, err := s.userIdentifiers(user)
+	if err != nil {
+		return nil, err
+	}
+	crushes, err := s.repo.FindCrushesOnUser(ids.Phone, ids.Instagram, ids.Snapchat)
+	if err != nil {
+		return nil, err
 	}
 
-	// Find all crushes matching any of the user's identifiers
-	crushes, err := s.repo.FindCrushesOnUser(user.CountryCode, user.Phone, instagramID, snapchatID)
+	// Leave out crushes of users with a block between them and the user
+	ownerIDs := make([]uuid.UUID, 0, len(crushes))
+	for _, crush := range crushes {
+		ownerIDs = append(ownerIDs, crush.UserID)
+	}
+	blocked, err := s.blockedUsers(user.ID, ownerIDs)
 	if err != nil {
 		return nil, err
 	}
+	visible := crushes[:0]
+	for _, crush := range crushes {
+		if !blocked[crush.UserID] {
+			visible = append(visible, crush)
+		}
+	}
+	crushes = visible
+
+	// Apps can keep the details for subscribers; others only learn how many crushes there are
+	if s.policies.For(user.AppName).RevealsRequireSubscription && len(crushes) > 0 {
+		subscribed, err := s.isSubscriber(user)
+		if err != nil {
+			return nil, err
+		}
+		if !subscribed {
+			return nil, &UpgradeRequiredError{Feature: FeatureCrushReveals, Locked: len(crushes)}
+		}
+	}
 
 	responses := make([]models.CrushOnUserResponse, len(crushes))
 	for i, crush := range crushes {
@@ -265,8 +610,248 @@ func (s *crushService) ListCrushesOnUser(userID uuid.UUID) ([]models.CrushOnUser
 	return responses, nil
 }
 
+// ListMatches lists the user's mutual matches, revealing the other party
+// Matches whose crush entries no longer point at each other, or are no longer active, are omitted,
+// as are matches with users who are suspended or have a block with the user
+func (s *crushService) ListMatches(userID uuid.UUID) ([]models.CrushMatchResponse, error) {
+	me, err := s.findActiveUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	myIDs, err := s.userIdentifiers(me)
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err := s.repo.FindMatchesByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	otherUserIDs := make([]uuid.UUID, 0, len(matches))
+	crushIDs := make([]uuid.UUID, 0, 2*len(matches))
+	for _, match := range matches {
+		otherUserID := match.UserAID
+		if otherUserID == userID {
+			otherUserID = match.UserBID
+		}
+		otherUserIDs = append(otherUserIDs, otherUserID)
+		crushIDs = append(crushIDs, match.CrushAID, match.CrushBID)
+	}
+	blocked, err := s.blockedUsers(userID, otherUserIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Load both crush entries and the other party of every match in batches rather than per match
+	crushes, err := s.repo.FindByIDs(crushIDs)
+	if err != nil {
+		return nil, err
+	}
+	crushesByID := make(map[uuid.UUID]*models.Crush, len(crushes))
+	for i := range crushes {
+		crushesByID[crushes[i].ID] = &crushes[i]
+	}
+	otherUsers, err := s.userRepo.FindByIDs(otherUserIDs)
+	if err != nil {
+		return nil, err
+	}
+	usersByID := make(map[uuid.UUID]*userModels.User, len(otherUsers))
+	for i := range otherUsers {
+		usersByID[otherUsers[i].ID] = &otherUsers[i]
+	}
+	identities, err := s.identityRepo.FindByUserIDs(otherUserIDs)
+	if err != nil {
+		return nil, err
+	}
+	identitiesByUser := make(map[uuid.UUID][]userModels.UserIdentity, len(otherUsers))
+	for _, identity := range identities {
+		identitiesByUser[identity.UserID] = append(identitiesByUser[identity.UserID], identity)
+	}
+
+	responses := make([]models.CrushMatchResponse, 0, len(matches))
+	for _, match := range matches {
+		myCrushID, otherUserID, otherCrushID := match.CrushAID, match.UserBID, match.CrushBID
+		if match.UserBID == userID {
+			myCrushID, otherUserID, otherCrushID = match.CrushBID, match.UserAID, match.CrushAID
+		}
+
+		// Deleted crushes or users end the match
+		myCrush, otherCrush, otherUser := crushesByID[myCrushID], crushesByID[otherCrushID], usersByID[otherUserID]
+		if myCrush == nil || otherCrush == nil || otherUser == nil {
+			continue
+		}
+
+		// Blocks and suspensions end the match
+		if blocked[otherUserID] || otherUser.IsSuspended() {
+			continue
+		}
+
+		// Archived or expired crushes hide the match until restored
+		if !myCrush.IsActive() || !otherCrush.IsActive() {
+			continue
+		}
+
+		// Only reveal the other party while both crushes still point at each other
+		otherIDs := identifiersOf(otherUser, identitiesByUser[otherUserID])
+		if !identifiersMatchUser(otherIDs, myCrush) || !identifiersMatchUser(myIDs, otherCrush) {
+			continue
+		}
+
+		responses = append(responses, models.CrushMatchResponse{
+			ID:     match.ID,
+			UserID: userID,
+			Crush:  myCrush.ToResponse(),
+			MatchedUser: models.MatchedUserResponse{
+				ID:          otherUser.ID,
+				Name:        otherUser.Name,
+				CountryCode: otherUser.CountryCode,
+				Phone:       otherUser.Phone,
+				Email:       otherUser.Email,
+				InstagramID: otherIDs.Instagram,
+				SnapchatID:  otherIDs.Snapchat,
+			},
+			MatchedAt: match.CreatedAt,
+		})
+	}
+	return responses, nil
+}
+
+// recordMatches records a match for every user the crush resolves to who already has the crush owner as a crush
+// Must run inside the transaction that wrote the crush; the pair lock is taken after the write so that of two
+// concurrent writers, the second always sees the first's committed crush
+// Users with any block with the owner are skipped, so they neither match nor hear of the crush; the crush itself
+// is saved as usual so its owner cannot tell they were blocked
+// Returns the users the crush resolves to and the matches that are new
+func (s *crushService) recordMatches(repo repository.CrushRepository, user *userModels.User, ids userIdentifiers, crush *models.Crush) ([]uuid.UUID, []*models.CrushMatch, error) {
+	resolvedIDs, err := repo.FindUserIDsByIdentifiers(user.AppName, crush.PhoneNormalized, crush.InstagramNormalized, crush.SnapchatNormalized)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	blocked, err := s.blockedUsers(user.ID, resolvedIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	targetIDs := make([]uuid.UUID, 0, len(resolvedIDs))
+	for _, id := range resolvedIDs {
+		if !blocked[id] {
+			targetIDs = append(targetIDs, id)
+		}
+	}
+	// Lock pairs in a stable order to avoid deadlocks between concurrent writers
+	sort.Slice(targetIDs, func(i, j int) bool { return targetIDs[i].String() < targetIDs[j].String() })
+
+	var created []*models.CrushMatch
+	for _, targetID := range targetIDs {
+		if targetID == user.ID {
+			continue
+		}
+
+		if err := repo.LockPair(user.ID, targetID); err != nil {
+			return nil, nil, err
+		}
+
+		reciprocal, err := repo.FindUserCrushesMatching(targetID, ids.Phone, ids.Instagram, ids.Snapchat)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(reciprocal) == 0 {
+			continue
+		}
+
+		match := &models.CrushMatch{
+			AppName:  user.AppName,
+			UserAID:  user.ID,
+			UserBID:  targetID,
+			CrushAID: crush.ID,
+			CrushBID: reciprocal[0].ID,
+		}
+		// Store the pair in canonical order
+		if match.UserBID.String() < match.UserAID.String() {
+			match.UserAID, match.UserBID = match.UserBID, match.UserAID
+			match.CrushAID, match.CrushBID = match.CrushBID, match.CrushAID
+		}
+		isNew, err := repo.SaveMatch(match)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to record match: %w", err)
+		}
+		if isNew {
+			fmt.Printf("[CrushMatch] Users %s and %s matched\n", match.UserAID, match.UserBID)
+			created = append(created, match)
+		}
+	}
+	return targetIDs, created, nil
+}
+
+// notifyMatches tells both parties of each new match
+// Delivery failures are logged; the crush write has already succeeded
+func (s *crushService) notifyMatches(user *userModels.User, matches []*models.CrushMatch) {
+	for _, match := range matches {
+		parties := map[uuid.UUID]uuid.UUID{match.UserAID: match.UserBID, match.UserBID: match.UserAID}
+		for userID, matchedUserID := range parties {
+			payload := map[string]interface{}{
+				"match_id":        match.ID,
+				"matched_user_id": matchedUserID,
+			}
+			if err := s.notifier.Notify(userID, user.AppName, notificationModels.TypeCrushMatch, payload); err != nil {
+				fmt.Printf("[CrushMatch] Failed to notify user %s of match %s: %v\n", userID, match.ID, err)
+			}
+		}
+	}
+}
+
+// notifyCrushReceived tells each user a new crush resolves to that someone has a crush on them
+// The crush owner stays anonymous; users who matched are told about the match instead
+func (s *crushService) notifyCrushReceived(user *userModels.User, targetIDs []uuid.UUID, matches []*models.CrushMatch) {
+	matched := make(map[uuid.UUID]bool, len(matches))
+	for _, match := range matches {
+		matched[match.UserAID] = true
+		matched[match.UserBID] = true
+	}
+
+	for _, targetID := range targetIDs {
+		if targetID == user.ID || matched[targetID] {
+			continue
+		}
+		if err := s.notifier.Notify(targetID, user.AppName, notificationModels.TypeCrushReceived, map[string]interface{}{}); err != nil {
+			fmt.Printf("[CrushNotify] Failed to notify user %s of a new crush: %v\n", targetID, err)
+		}
+	}
+}
+
+// userIdentifiers loads the user's normalized phone and their verified social identities
+// Unverified identities are left out so a claimed handle never matches or reveals crushes
+func (s *crushService) userIdentifiers(user *userModels.User) (userIdentifiers, error) {
+	identities, err := s.identityRepo.FindByUserID(user.ID)
+	if err != nil {
+		return userIdentifiers{Phone: user.PhoneNormalized}, err
+	}
+	return identifiersOf(user, identities), nil
+}
+
+// identifiersOf builds the user's identifiers from their already loaded identities, skipping unverified ones
+func identifiersOf(user *userModels.User, identities []userModels.UserIdentity) userIdentifiers {
+	ids := userIdentifiers{Phone: user.PhoneNormalized}
+	for _, identity := range identities {
+		if !identity.Verified {
+			continue
+		}
+		value := identity.Value
+		switch identity.Type {
+		case userModels.IdentityTypeInstagram:
+			ids.Instagram = &value
+		case userModels.IdentityTypeSnapchat:
+			ids.Snapchat = &value
+		}
+	}
+	return ids
+}
+
 // ListAllCrushesPaginated retrieves all crushes with pagination
-func (s *crushService) ListAllCrushesPaginated(page, pageSize int) (*models.PaginatedCrushesResponse, error) {
+// Phones and handles are masked unless reveal is set
+func (s *crushService) ListAllCrushesPaginated(page, pageSize int, reveal bool) (*models.PaginatedCrushesResponse, error) {
 	// Validate page and pageSize
 	if page < 1 {
 		page = 1
@@ -283,17 +868,30 @@ func (s *crushService) ListAllCrushesPaginated(page, pageSize int) (*models.Pagi
 		return nil, err
 	}
 
+	// Load the page's owners in one query to fetch their phone numbers
+	userIDs := make([]uuid.UUID, 0, len(crushes))
+	for _, crush := range crushes {
+		userIDs = append(userIDs, crush.UserID)
+	}
+	users, err := s.userRepo.FindByIDs(userIDs)
+	if err != nil {
+		return nil, err
+	}
+	usersByID := make(map[uuid.UUID]userModels.User, len(users))
+	for _, user := range users {
+		usersByID[user.ID] = user
+	}
+
 	// Build response with user phone numbers
 	responses := make([]models.AllCrushesResponse, 0, len(crushes))
 	for _, crush := range crushes {
-		// Get user details to fetch phone number
-		user, err := s.userRepo.FindByID(crush.UserID)
-		if err != nil {
+		user, ok := usersByID[crush.UserID]
+		if !ok {
 			// Skip crushes where user is not found
 			continue
 		}
 
-		responses = append(responses, models.AllCrushesResponse{
+		resp := models.AllCrushesResponse{
 			UserCountryCode:  user.CountryCode,
 			UserPhone:        user.Phone,
 			CrushCountryCode: crush.CountryCode,
@@ -301,7 +899,14 @@ func (s *crushService) ListAllCrushesPaginated(page, pageSize int) (*models.Pagi
 			InstagramID:      crush.InstagramID,
 			SnapchatID:       crush.SnapchatID,
 			CreatedAt:        crush.CreatedAt,
-		})
+		}
+		if !reveal {
+			resp.UserPhone = maskValue(resp.UserPhone, identifier.MaskPhone)
+			resp.CrushPhone = maskValue(resp.CrushPhone, identifier.MaskPhone)
+			resp.InstagramID = maskValue(resp.InstagramID, identifier.MaskHandle)
+			resp.SnapchatID = maskValue(resp.SnapchatID, identifier.MaskHandle)
+		}
+		responses = append(responses, resp)
 	}
 
 	// Calculate total pages
@@ -331,3 +936,12 @@ func (s *crushService) ListAllCrushesPaginated(page, pageSize int) (*models.Pagi
 		PrevPage:   prevPage,
 	}, nil
 }
+
+// maskValue applies mask to an optional value
+func maskValue(value *string, mask func(string) string) *string {
+	if value == nil {
+		return nil
+	}
+	masked := mask(*value)
+	return &masked
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// defaultInviteMessage is used when an app does not configure its own wording
+// It never names the sender; the STOP line is required by most carriers for promotional SMS
+const defaultInviteMessage = "Someone has a crush on you on {{.AppName}}! Find out who: {{.Link}}\nReply STOP to opt out."
+
+// InviteConfig holds the per-app settings for SMS invites to unregistered crush targets
+type InviteConfig struct {
+	Enabled        bool   `json:"enabled"`
+	Message        string `json:"message"`          // text/template with .AppName, .SenderName and .Link
+	Link           string `json:"link"`             // Where the invitee can sign up
+	SenderName     string `json:"sender_name"`      // Name shown to recipients; defaults to the app name
+	TemplateID     string `json:"template_id"`      // Provider (DLT) template for the invite, if the provider needs one
+	DedupDays      int    `json:"dedup_days"`       // A target is invited at most once per this many days
+	SenderDailyCap int    `json:"sender_daily_cap"` // Invites a single user can trigger per 24 hours
+
+	message *template.Template
+}
+
+// InviteData is the data available to invite message templates
+type InviteData struct {
+	AppName    string
+	SenderName string
+	Link       string
+}
+
+// DedupWindow returns how long a target is protected from repeat invites
+func (c InviteConfig) DedupWindow() time.Duration {
+	return time.Duration(c.DedupDays) * 24 * time.Hour
+}
+
+// Render builds the invite text for the app
+func (c InviteConfig) Render(appName string) (string, error) {
+	senderName := c.SenderName
+	if senderName == "" {
+		senderName = appName
+	}
+
+	var b strings.Builder
+	if err := c.message.Execute(&b, InviteData{AppName: appName, SenderName: senderName, Link: c.Link}); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// InviteConfigs resolves invite settings per app
+type InviteConfigs map[string]InviteConfig
+
+// For returns the app's invite settings; apps without configuration have invites disabled
+func (c InviteConfigs) For(appName string) (InviteConfig, bool) {
+	config, ok := c[appName]
+	return config, ok && config.Enabled
+}
+
+// ParseInviteConfigs parses per-app invite settings, e.g. {"apps":{"app":{"enabled":true,"link":"https://..."}}}
+func ParseInviteConfigs(raw string) (InviteConfigs, error) {
+	var parsed struct {
+		Apps map[string]InviteConfig `json:"apps"`
+	}
+	if raw != "" {
+		if err := json.Unmarshal([]byte(raw), &parsed); err != nil {
+			return nil, fmt.Errorf("invalid invite configuration: %w", err)
+		}
+	}
+
+	configs := make(InviteConfigs, len(parsed.Apps))
+	for appName, config := range parsed.Apps {
+		if config.Message == "" {
+			config.Message = defaultInviteMessage
+		}
+		if config.DedupDays <= 0 {
+			config.DedupDays = 30
+		}
+		if config.SenderDailyCap <= 0 {
+			config.SenderDailyCap = 3
+		}
+		if config.Enabled && config.Link == "" {
+			return nil, fmt.Errorf("invite link is required for %s", appName)
+		}
+
+		message, err := template.New(appName).Option("missingkey=error").Parse(config.Message)
+		if err != nil {
+			return nil, fmt.Errorf("invalid invite message for %s: %w", appName, err)
+		}
+		config.message = message
+		if _, err := config.Render(appName); err != nil {
+			return nil, fmt.Errorf("invalid invite message for %s: %w", appName, err)
+		}
+		if !strings.Contains(strings.ToUpper(config.Message), "STOP") {
+			return nil, fmt.Errorf("invite message for %s must tell recipients how to opt out (STOP)", appName)
+		}
+		configs[appName] = config
+	}
+	return configs, nil
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-backend/internal/apps/crush/models"
+	"go-backend/internal/apps/crush/repository"
+	otpModels "go-backend/internal/apps/otp/models"
+	otpService "go-backend/internal/apps/otp/service"
+	userModels "go-backend/internal/apps/user/models"
+	userRepository "go-backend/internal/apps/user/repository"
+	"go-backend/pkg/identifier"
+	"go-backend/pkg/secure"
+
+	"gorm.io/gorm"
+)
+
+// Keywords recognised in inbound SMS replies (matching the carrier-standard opt-out keywords)
+var (
+	optOutKeywords = map[string]bool{"STOP": true, "STOPALL": true, "UNSUBSCRIBE": true, "CANCEL": true, "END": true, "QUIT": true}
+	optInKeywords  = map[string]bool{"START": true, "UNSTOP": true, "YES": true}
+)
+
+// Opt-out sources
+const (
+	OptOutSourceSMS   = "sms"
+	OptOutSourceAdmin = "admin"
+)
+
+// InviteService sends anonymous SMS invites to crush targets who are not registered in the app
+type InviteService interface {
+	InviteIfUnregistered(sender *userModels.User, crush *models.Crush) error
+	HandleInboundSMS(from, body string) error
+	OptOut(countryCode, phone, source string) error
+}
+
+// inviteService implements InviteService
+type inviteService struct {
+	repo     repository.InviteRepository
+	userRepo userRepository.UserRepository
+	provider otpService.OTPProvider
+	configs  InviteConfigs
+}
+
+// NewInviteService creates a new instance of InviteService
+// Invites are delivered through the OTP provider routing (SMS channel only)
+func NewInviteService(repo repository.InviteRepository, userRepo userRepository.UserRepository, provider otpService.OTPProvider, configs InviteConfigs) InviteService {
+	return &inviteService{
+		repo:     repo,
+		userRepo: userRepo,
+		provider: provider,
+		configs:  configs,
+	}
+}
+
+// InviteIfUnregistered sends an invite when the crush targets a phone number with no user in the sender's app
+// Returns nil without sending when invites are disabled, the target is registered, opted out, recently invited,
+// or the sender reached their daily cap; the outcome is never reported to the sender
+func (s *inviteService) InviteIfUnregistered(sender *userModels.User, crush *models.Crush) error {
+	config, ok := s.configs.For(sender.AppName)
+	if !ok || crush.PhoneNormalized == nil {
+		return nil
+	}
+	phone, err := identifier.ParsePhone("", *crush.PhoneNormalized)
+	if err != nil {
+		return nil
+	}
+
+	if _, err := s.userRepo.FindByAppAndContact(sender.AppName, phone.CountryCode, phone.Number); err == nil {
+		return nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	targetHash, err := secure.KeyedHash("crush_invite", phone.E164())
+	if err != nil {
+		return err
+	}
+
+	optedOut, err := s.repo.IsOptedOut(targetHash)
+	if err != nil {
+		return err
+	}
+	if optedOut {
+		return nil
+	}
+
+	now := time.Now()
+	invite := &models.CrushInvite{
+		AppName:      sender.AppName,
+		TargetHash:   targetHash,
+		SenderUserID: sender.ID,
+		CrushID:      crush.ID,
+	}
+	outcome, err := s.repo.Reserve(invite, now.Add(-config.DedupWindow()), config.SenderDailyCap, now.Add(-24*time.Hour))
+	if err != nil {
+		return err
+	}
+	if outcome != models.InviteReserved {
+		fmt.Printf("[CrushInvite] Not inviting target of crush %s: %s\n", crush.ID, outcome)
+		return nil
+	}
+
+	body, err := config.Render(sender.AppName)
+	if err != nil {
+		_ = s.repo.UpdateStatus(invite.ID, models.InviteStatusFailed, "", err.Error())
+		return err
+	}
+
+	delivery, err := s.provider.SendOTP(otpService.OTPMessage{
+		CountryCode:      phone.CountryCode,
+		Phone:            phone.Number,
+		AppName:          sender.AppName,
+		Channel:          otpModels.PhoneChannelSMS,
+		FallbackChannels: []string{}, // Invites are plain SMS; never fall back to OTP-only channels
+		AllowedChannels:  []string{otpModels.PhoneChannelSMS},
+		SenderName:       config.SenderName,
+		TemplateID:       config.TemplateID,
+		Body:             body,
+	})
+	if err != nil {
+		if updateErr := s.repo.UpdateStatus(invite.ID, models.InviteStatusFailed, "", err.Error()); updateErr != nil {
+			fmt.Printf("[CrushInvite] Failed to record failed invite %s: %v\n", invite.ID, updateErr)
+		}
+		return fmt.Errorf("failed to send invite: %w", err)
+	}
+
+	fmt.Printf("[CrushInvite] Sent invite %s via %s\n", invite.ID, delivery.Provider)
+	return s.repo.UpdateStatus(invite.ID, models.InviteStatusSent, delivery.Provider, "")
+}
+
+// HandleInboundSMS processes a reply to an invite: STOP-style keywords opt the number out, START-style keywords opt it back in
+// from must be a full international number (e.g. +919876543210)
+func (s *inviteService) HandleInboundSMS(from, body string) error {
+	if !strings.HasPrefix(strings.TrimSpace(from), "+") {
+		return errors.New("invalid sender number")
+	}
+	from, err := identifier.NormalizePhone("", from)
+	if err != nil {
+		return errors.New("invalid sender number")
+	}
+
+	keyword := strings.ToUpper(strings.TrimSpace(body))
+	if fields := strings.Fields(keyword); len(fields) > 0 {
+		keyword = fields[0]
+	}
+
+	targetHash, err := secure.KeyedHash("crush_invite", from)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case optOutKeywords[keyword]:
+		fmt.Println("[CrushInvite] Recorded SMS opt-out")
+		return s.repo.CreateOptOut(&models.InviteOptOut{TargetHash: targetHash, Source: OptOutSourceSMS})
+	case optInKeywords[keyword]:
+		fmt.Println("[CrushInvite] Removed opt-out after SMS opt-in")
+		return s.repo.DeleteOptOut(targetHash)
+	default:
+		return nil
+	}
+}
+
+// OptOut records an opt-out for a phone number
+func (s *inviteService) OptOut(countryCode, phone, source string) error {
+	targetHash, err := inviteTargetHash(countryCode, phone)
+	if err != nil {
+		return err
+	}
+	return s.repo.CreateOptOut(&models.InviteOptOut{TargetHash: targetHash, Source: source})
+}
+
+// inviteTargetHash returns the keyed hash identifying a phone number for dedup and opt-outs
+// The number is hashed in E.164 form so it matches the sender of inbound replies
+func inviteTargetHash(countryCode, phone string) (string, error) {
+	normalized, err := identifier.NormalizePhone(countryCode, phone)
+	if err != nil {
+		return "", err
+	}
+	return secure.KeyedHash("crush_invite", normalized)
+}
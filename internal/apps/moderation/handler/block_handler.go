@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"net/http"
+
+	"go-backend/internal/apps/moderation/models"
+	"go-backend/internal/apps/moderation/service"
+	"go-backend/internal/common/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// BlockHandler handles HTTP requests for user blocks
+type BlockHandler struct {
+	service service.BlockService
+}
+
+// NewBlockHandler creates a new instance of BlockHandler
+func NewBlockHandler(service service.BlockService) *BlockHandler {
+	return &BlockHandler{service: service}
+}
+
+// subjectErrorStatus maps errors from blocking or reporting a user to HTTP status codes
+func subjectErrorStatus(err error) int {
+	switch err.Error() {
+	case "user not found", "crush not found", "block not found":
+		return http.StatusNotFound
+	case "either user_id or crush_id is required", "you cannot block yourself", "you cannot report yourself":
+		return http.StatusBadRequest
+	case "user already blocked":
+		return http.StatusConflict
+	case "user is suspended":
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// currentUserID returns the authenticated user's ID
+func currentUserID(c *gin.Context) (uuid.UUID, bool) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+		return uuid.Nil, false
+	}
+	return principal.UserID, true
+}
+
+// BlockUser handles POST /api/v1/blocks
+func (h *BlockHandler) BlockUser(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req models.CreateBlockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.service.BlockUser(userID, req)
+	if err != nil {
+		c.JSON(subjectErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": resp})
+}
+
+// ListBlocks handles GET /api/v1/blocks
+func (h *BlockHandler) ListBlocks(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.service.ListBlocks(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
+
+// Unblock handles DELETE /api/v1/blocks/:id
+func (h *BlockHandler) Unblock(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid block id"})
+		return
+	}
+
+	if err := h.service.Unblock(userID, id); err != nil {
+		c.JSON(subjectErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "user unblocked successfully"})
+}
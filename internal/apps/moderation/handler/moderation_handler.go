@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"go-backend/internal/apps/moderation/models"
+	"go-backend/internal/apps/moderation/service"
+	"go-backend/internal/common/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ModerationHandler handles HTTP requests for abuse reports and the moderation queue
+type ModerationHandler struct {
+	service service.ModerationService
+}
+
+// NewModerationHandler creates a new instance of ModerationHandler
+func NewModerationHandler(service service.ModerationService) *ModerationHandler {
+	return &ModerationHandler{service: service}
+}
+
+// moderationErrorStatus maps moderation errors to HTTP status codes
+func moderationErrorStatus(err error) int {
+	switch err.Error() {
+	case "report not found", "user not found":
+		return http.StatusNotFound
+	case "report is dismissed", "only open reports can be dismissed", "user is not suspended":
+		return http.StatusConflict
+	case "invalid action":
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// CreateReport handles POST /api/v1/reports
+func (h *ModerationHandler) CreateReport(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req models.CreateReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.service.CreateReport(userID, req)
+	if err != nil {
+		c.JSON(subjectErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": resp})
+}
+
+// ListReports handles GET /api/v1/moderation/reports
+// Supports app_name and status (default open) filters and page/page_size pagination
+func (h *ModerationHandler) ListReports(c *gin.Context) {
+	// Default pagination values
+	page := 1
+	pageSize := 10
+
+	// Parse page parameter
+	if pageStr := c.Query("page"); pageStr != "" {
+		if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
+			page = p
+		}
+	}
+
+	// Parse page_size parameter
+	if pageSizeStr := c.Query("page_size"); pageSizeStr != "" {
+		if ps, err := strconv.Atoi(pageSizeStr); err == nil && ps > 0 {
+			pageSize = ps
+		}
+	}
+
+	status := c.Query("status")
+	switch status {
+	case "", models.ReportStatusOpen, models.ReportStatusActioned, models.ReportStatusDismissed:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid status"})
+		return
+	}
+
+	resp, err := h.service.ListReports(c.Query("app_name"), status, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetReport handles GET /api/v1/moderation/reports/:id
+func (h *ModerationHandler) GetReport(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid report id"})
+		return
+	}
+
+	resp, err := h.service.GetReport(id)
+	if err != nil {
+		c.JSON(moderationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
+
+// TakeAction handles POST /api/v1/moderation/reports/:id/actions
+// Warns or suspends the reported user, purges their crushes, or dismisses the report
+func (h *ModerationHandler) TakeAction(c *gin.Context) {
+	admin, ok := middleware.GetAdminPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "admin api key required"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid report id"})
+		return
+	}
+
+	var req models.ModerationActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.service.TakeAction(id, req, admin)
+	if err != nil {
+		c.JSON(moderationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": resp})
+}
+
+// ReinstateUser handles POST /api/v1/moderation/users/:id/reinstate
+// Lifts a user's suspension
+func (h *ModerationHandler) ReinstateUser(c *gin.Context) {
+	admin, ok := middleware.GetAdminPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "admin api key required"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+
+	// The note is optional, so an empty body is accepted
+	var req models.ReinstateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.service.ReinstateUser(id, req, admin)
+	if err != nil {
+		c.JSON(moderationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"go-backend/internal/common/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterModerationRoutes registers block, report and moderation queue routes
+// requireAuth guards routes that act on behalf of the authenticated user,
+// adminGuard guards the moderation queue; acting on reports needs an admin or support key
+func RegisterModerationRoutes(router *gin.RouterGroup, blockHandler *BlockHandler, handler *ModerationHandler, requireAuth gin.HandlerFunc, adminGuard middleware.AdminGuard) {
+	blocks := router.Group("/blocks")
+	{
+		blocks.POST("", requireAuth, blockHandler.BlockUser)
+		blocks.GET("", requireAuth, blockHandler.ListBlocks)
+		blocks.DELETE("/:id", requireAuth, blockHandler.Unblock)
+	}
+
+	router.POST("/reports", requireAuth, handler.CreateReport)
+
+	moderation := router.Group("/moderation")
+	{
+		moderation.GET("/reports", adminGuard(), handler.ListReports)
+		moderation.GET("/reports/:id", adminGuard(), handler.GetReport)
+		moderation.POST("/reports/:id/actions", adminGuard(middleware.RoleAdmin, middleware.RoleSupport), handler.TakeAction)
+		moderation.POST("/users/:id/reinstate", adminGuard(middleware.RoleAdmin), handler.ReinstateUser)
+	}
+}
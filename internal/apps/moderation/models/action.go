@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Moderation actions
+const (
+	ActionWarn         = "warn"          // Notify the reported user
+	ActionSuspendUser  = "suspend_user"  // Suspend the reported user
+	ActionPurgeCrushes = "purge_crushes" // Delete all of the reported user's crushes
+	ActionDismiss      = "dismiss"       // Close the report without acting
+	ActionReinstate    = "reinstate"     // Lift a user's suspension
+)
+
+// ModerationAction is the audit record of an action taken by an admin key
+type ModerationAction struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	ReportID   *uuid.UUID `gorm:"type:uuid" json:"report_id,omitempty"`
+	UserID     uuid.UUID  `gorm:"type:uuid;not null" json:"user_id"`
+	Action     string     `gorm:"not null;size:20" json:"action"`
+	Note       *string    `gorm:"size:500" json:"note,omitempty"`
+	Affected   int64      `gorm:"not null;default:0" json:"affected"` // Crushes purged
+	AdminKeyID uuid.UUID  `gorm:"type:uuid;not null" json:"admin_key_id"`
+	AdminName  string     `gorm:"not null;size:255" json:"admin_name"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// TableName sets the table name to 'moderation_actions'
+func (ModerationAction) TableName() string { return "moderation_actions" }
+
+// BeforeCreate hook to generate UUID before creating record
+func (a *ModerationAction) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
+
+// ModerationActionRequest represents the request body for acting on a report
+type ModerationActionRequest struct {
+	Action string  `json:"action" binding:"required,oneof=warn suspend_user purge_crushes dismiss"`
+	Note   *string `json:"note,omitempty" binding:"omitempty,max=500"`
+}
+
+// ReinstateUserRequest represents the request body for lifting a suspension
+type ReinstateUserRequest struct {
+	Note *string `json:"note,omitempty" binding:"omitempty,max=500"`
+}
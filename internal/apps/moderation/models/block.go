@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Block stops the blocked user from adding the blocker as a crush and hides their crushes from the blocker
+// Blocks made from a crush keep its owner anonymous to the blocker
+type Block struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	AppName   string     `gorm:"not null;size:100" json:"app_name"`
+	BlockerID uuid.UUID  `gorm:"type:uuid;not null" json:"blocker_id"`
+	BlockedID uuid.UUID  `gorm:"type:uuid;not null" json:"blocked_id"`
+	CrushID   *uuid.UUID `gorm:"type:uuid" json:"crush_id,omitempty"` // Crush on the blocker the block was made from
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// TableName sets the table name to 'blocks'
+func (Block) TableName() string { return "blocks" }
+
+// BeforeCreate hook to generate UUID before creating record
+func (b *Block) BeforeCreate(tx *gorm.DB) error {
+	if b.ID == uuid.Nil {
+		b.ID = uuid.New()
+	}
+	return nil
+}
+
+// CreateBlockRequest represents the request body for blocking a user
+// Exactly one of UserID or CrushID (a crush on the caller, whose owner stays anonymous) must be set
+type CreateBlockRequest struct {
+	UserID  *uuid.UUID `json:"user_id,omitempty"`
+	CrushID *uuid.UUID `json:"crush_id,omitempty"`
+}
+
+// BlockResponse represents a block as seen by the blocker
+type BlockResponse struct {
+	ID            uuid.UUID  `json:"id"`
+	BlockedUserID *uuid.UUID `json:"blocked_user_id,omitempty"` // Omitted for blocks made from a crush
+	CrushID       *uuid.UUID `json:"crush_id,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// ToResponse converts Block model to BlockResponse
+func (b *Block) ToResponse() BlockResponse {
+	resp := BlockResponse{
+		ID:        b.ID,
+		CrushID:   b.CrushID,
+		CreatedAt: b.CreatedAt,
+	}
+	if b.CrushID == nil {
+		blockedID := b.BlockedID
+		resp.BlockedUserID = &blockedID
+	}
+	return resp
+}
@@ -0,0 +1,127 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Metadata is a custom type for JSONB fields
+type Metadata map[string]interface{}
+
+// Scan implements the sql.Scanner interface for Metadata
+func (m *Metadata) Scan(value interface{}) error {
+	if value == nil {
+		*m = make(Metadata)
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(bytes, m)
+}
+
+// Value implements the driver.Valuer interface for Metadata
+func (m Metadata) Value() (driver.Value, error) {
+	if m == nil {
+		return json.Marshal(make(map[string]interface{}))
+	}
+	return json.Marshal(m)
+}
+
+// Report reason codes
+const (
+	ReasonHarassment            = "harassment"
+	ReasonInappropriateName     = "inappropriate_name"     // Offensive crush name
+	ReasonInappropriateMetadata = "inappropriate_metadata" // Offensive notes or other crush metadata
+	ReasonSpam                  = "spam"
+	ReasonImpersonation         = "impersonation"
+	ReasonOther                 = "other"
+)
+
+// Report statuses; open reports make up the moderation queue
+const (
+	ReportStatusOpen      = "open"
+	ReportStatusActioned  = "actioned"
+	ReportStatusDismissed = "dismissed"
+)
+
+// Report is a user's complaint about another user, optionally about one of their crushes
+type Report struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	AppName        string     `gorm:"not null;size:100" json:"app_name"`
+	ReporterID     uuid.UUID  `gorm:"type:uuid;not null" json:"reporter_id"`
+	ReportedUserID uuid.UUID  `gorm:"type:uuid;not null" json:"reported_user_id"`
+	CrushID        *uuid.UUID `gorm:"type:uuid" json:"crush_id,omitempty"`
+	Reason         string     `gorm:"not null;size:50" json:"reason"`
+	Details        *string    `gorm:"size:1000" json:"details,omitempty"`
+	Evidence       Metadata   `gorm:"type:jsonb;not null;default:'{}'" json:"evidence"` // Snapshot of the reported crush's name and metadata
+	Status         string     `gorm:"not null;size:20;default:'open'" json:"status"`
+	ResolvedAt     *time.Time `json:"resolved_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// TableName sets the table name to 'reports'
+func (Report) TableName() string { return "reports" }
+
+// BeforeCreate hook to generate UUID before creating record
+func (r *Report) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	if r.Status == "" {
+		r.Status = ReportStatusOpen
+	}
+	return nil
+}
+
+// CreateReportRequest represents the request body for reporting a user or a crush on the caller
+// Exactly one of UserID or CrushID must be set
+type CreateReportRequest struct {
+	UserID  *uuid.UUID `json:"user_id,omitempty"`
+	CrushID *uuid.UUID `json:"crush_id,omitempty"`
+	Reason  string     `json:"reason" binding:"required,oneof=harassment inappropriate_name inappropriate_metadata spam impersonation other"`
+	Details *string    `json:"details,omitempty" binding:"omitempty,max=1000"`
+}
+
+// ReportReceiptResponse represents a report as seen by the reporter
+type ReportReceiptResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Reason    string    `json:"reason"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ToReceipt converts Report model to ReportReceiptResponse
+func (r *Report) ToReceipt() ReportReceiptResponse {
+	return ReportReceiptResponse{
+		ID:        r.ID,
+		Reason:    r.Reason,
+		Status:    r.Status,
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+// ReportDetailResponse represents a report in the moderation queue with its history
+type ReportDetailResponse struct {
+	Report             Report             `json:"report"`
+	Actions            []ModerationAction `json:"actions"`
+	ReportsAgainstUser int64              `json:"reports_against_user"`
+	UserSuspended      bool               `json:"user_suspended"`
+}
+
+// PaginatedReportsResponse represents the paginated moderation queue
+type PaginatedReportsResponse struct {
+	Data       []Report `json:"data"`
+	Page       int      `json:"page"`
+	PageSize   int      `json:"page_size"`
+	Total      int64    `json:"total"`
+	TotalPages int      `json:"total_pages"`
+	NextPage   *int     `json:"next_page"`
+	PrevPage   *int     `json:"prev_page"`
+}
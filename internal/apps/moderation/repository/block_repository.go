@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"go-backend/internal/apps/moderation/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// BlockRepository defines data operations for user blocks
+type BlockRepository interface {
+	Create(block *models.Block) error
+	FindByID(id uuid.UUID) (*models.Block, error)
+	FindByBlockerID(blockerID uuid.UUID) ([]models.Block, error)
+	FindBetween(userID uuid.UUID, otherIDs []uuid.UUID) ([]models.Block, error)
+	Delete(block *models.Block) error
+}
+
+// blockRepository implements BlockRepository
+type blockRepository struct {
+	db *gorm.DB
+}
+
+// NewBlockRepository creates a new instance of BlockRepository
+func NewBlockRepository(db *gorm.DB) BlockRepository {
+	return &blockRepository{db: db}
+}
+
+// Create creates a new block
+func (r *blockRepository) Create(block *models.Block) error {
+	return r.db.Create(block).Error
+}
+
+// FindByID retrieves a block by its ID
+func (r *blockRepository) FindByID(id uuid.UUID) (*models.Block, error) {
+	var block models.Block
+	if err := r.db.First(&block, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &block, nil
+}
+
+// FindByBlockerID retrieves all blocks made by a user
+func (r *blockRepository) FindByBlockerID(blockerID uuid.UUID) ([]models.Block, error) {
+	var blocks []models.Block
+	if err := r.db.Where("blocker_id = ?", blockerID).Order("created_at DESC").Find(&blocks).Error; err != nil {
+		return nil, err
+	}
+	return blocks, nil
+}
+
+// FindBetween retrieves blocks in either direction between a user and any of the other users
+func (r *blockRepository) FindBetween(userID uuid.UUID, otherIDs []uuid.UUID) ([]models.Block, error) {
+	var blocks []models.Block
+	if len(otherIDs) == 0 {
+		return blocks, nil
+	}
+	if err := r.db.Where("(blocker_id = ? AND blocked_id IN ?) OR (blocked_id = ? AND blocker_id IN ?)", userID, otherIDs, userID, otherIDs).
+		Find(&blocks).Error; err != nil {
+		return nil, err
+	}
+	return blocks, nil
+}
+
+// Delete removes a block
+func (r *blockRepository) Delete(block *models.Block) error {
+	return r.db.Delete(block).Error
+}
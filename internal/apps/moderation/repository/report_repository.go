@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"go-backend/internal/apps/moderation/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ReportRepository defines data operations for reports and the moderation actions taken on them
+type ReportRepository interface {
+	Create(report *models.Report) error
+	FindByID(id uuid.UUID) (*models.Report, error)
+	FindPaginated(appName, status string, page, pageSize int) ([]models.Report, int64, error)
+	FindOpenDuplicate(reporterID, reportedUserID uuid.UUID, crushID *uuid.UUID) (*models.Report, error)
+	CountByReportedUserID(userID uuid.UUID) (int64, error)
+	Update(report *models.Report) error
+	CreateAction(action *models.ModerationAction) error
+	FindActionsByReportID(reportID uuid.UUID) ([]models.ModerationAction, error)
+}
+
+// reportRepository implements ReportRepository
+type reportRepository struct {
+	db *gorm.DB
+}
+
+// NewReportRepository creates a new instance of ReportRepository
+func NewReportRepository(db *gorm.DB) ReportRepository {
+	return &reportRepository{db: db}
+}
+
+// Create creates a new report
+func (r *reportRepository) Create(report *models.Report) error {
+	return r.db.Create(report).Error
+}
+
+// FindByID retrieves a report by its ID
+func (r *reportRepository) FindByID(id uuid.UUID) (*models.Report, error) {
+	var report models.Report
+	if err := r.db.First(&report, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// FindPaginated retrieves reports with pagination, oldest first, with optional app_name and status filters
+func (r *reportRepository) FindPaginated(appName, status string, page, pageSize int) ([]models.Report, int64, error) {
+	var reports []models.Report
+	var total int64
+
+	query := r.db.Model(&models.Report{})
+	if appName != "" {
+		query = query.Where("app_name = ?", appName)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	// Get total count
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Calculate offset
+	offset := (page - 1) * pageSize
+
+	// Get paginated results
+	if err := query.Order("created_at ASC").Offset(offset).Limit(pageSize).Find(&reports).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return reports, total, nil
+}
+
+// FindOpenDuplicate retrieves an open report by the same reporter against the same user and crush
+func (r *reportRepository) FindOpenDuplicate(reporterID, reportedUserID uuid.UUID, crushID *uuid.UUID) (*models.Report, error) {
+	var report models.Report
+	query := r.db.Where("reporter_id = ? AND reported_user_id = ? AND status = ?", reporterID, reportedUserID, models.ReportStatusOpen)
+	if crushID != nil {
+		query = query.Where("crush_id = ?", *crushID)
+	} else {
+		query = query.Where("crush_id IS NULL")
+	}
+	if err := query.First(&report).Error; err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// CountByReportedUserID counts all reports made against a user
+func (r *reportRepository) CountByReportedUserID(userID uuid.UUID) (int64, error) {
+	var count int64
+	if err := r.db.Model(&models.Report{}).Where("reported_user_id = ?", userID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// Update updates an existing report
+func (r *reportRepository) Update(report *models.Report) error {
+	return r.db.Save(report).Error
+}
+
+// CreateAction records a moderation action
+func (r *reportRepository) CreateAction(action *models.ModerationAction) error {
+	return r.db.Create(action).Error
+}
+
+// FindActionsByReportID retrieves the actions taken on a report, oldest first
+func (r *reportRepository) FindActionsByReportID(reportID uuid.UUID) ([]models.ModerationAction, error) {
+	var actions []models.ModerationAction
+	if err := r.db.Where("report_id = ?", reportID).Order("created_at ASC").Find(&actions).Error; err != nil {
+		return nil, err
+	}
+	return actions, nil
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	crushRepository "go-backend/internal/apps/crush/repository"
+	"go-backend/internal/apps/moderation/models"
+	"go-backend/internal/apps/moderation/repository"
+	userModels "go-backend/internal/apps/user/models"
+	userRepository "go-backend/internal/apps/user/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// BlockService defines business logic for users blocking each other
+type BlockService interface {
+	BlockUser(blockerID uuid.UUID, req models.CreateBlockRequest) (*models.BlockResponse, error)
+	ListBlocks(blockerID uuid.UUID) ([]models.BlockResponse, error)
+	Unblock(blockerID, blockID uuid.UUID) error
+}
+
+// blockService implements BlockService
+type blockService struct {
+	repo      repository.BlockRepository
+	userRepo  userRepository.UserRepository
+	crushRepo crushRepository.CrushRepository
+}
+
+// NewBlockService creates a new instance of BlockService
+func NewBlockService(repo repository.BlockRepository, userRepo userRepository.UserRepository, crushRepo crushRepository.CrushRepository) BlockService {
+	return &blockService{
+		repo:      repo,
+		userRepo:  userRepo,
+		crushRepo: crushRepo,
+	}
+}
+
+// BlockUser blocks a user, given directly or as the owner of a crush
+func (s *blockService) BlockUser(blockerID uuid.UUID, req models.CreateBlockRequest) (*models.BlockResponse, error) {
+	blocker, err := findUser(s.userRepo, blockerID)
+	if err != nil {
+		return nil, err
+	}
+
+	blockedID, err := resolveSubject(s.userRepo, s.crushRepo, blocker, req.UserID, req.CrushID)
+	if err != nil {
+		return nil, err
+	}
+	if blockedID == blocker.ID {
+		return nil, errors.New("you cannot block yourself")
+	}
+
+	existing, err := s.repo.FindBetween(blocker.ID, []uuid.UUID{blockedID})
+	if err != nil {
+		return nil, err
+	}
+	for _, block := range existing {
+		if block.BlockerID == blocker.ID {
+			return nil, errors.New("user already blocked")
+		}
+	}
+
+	block := &models.Block{
+		AppName:   blocker.AppName,
+		BlockerID: blocker.ID,
+		BlockedID: blockedID,
+		CrushID:   req.CrushID,
+	}
+	if err := s.repo.Create(block); err != nil {
+		if isUniqueViolation(err) {
+			return nil, errors.New("user already blocked")
+		}
+		return nil, err
+	}
+
+	resp := block.ToResponse()
+	return &resp, nil
+}
+
+// ListBlocks lists the blocks a user has made
+func (s *blockService) ListBlocks(blockerID uuid.UUID) ([]models.BlockResponse, error) {
+	blocks, err := s.repo.FindByBlockerID(blockerID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.BlockResponse, len(blocks))
+	for i, block := range blocks {
+		responses[i] = block.ToResponse()
+	}
+	return responses, nil
+}
+
+// Unblock removes a block made by the user
+func (s *blockService) Unblock(blockerID, blockID uuid.UUID) error {
+	block, err := s.repo.FindByID(blockID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("block not found")
+		}
+		return err
+	}
+	if block.BlockerID != blockerID {
+		return errors.New("block not found")
+	}
+	return s.repo.Delete(block)
+}
+
+// findUser loads a user, mapping a missing record to "user not found"
+func findUser(userRepo userRepository.UserRepository, userID uuid.UUID) (*userModels.User, error) {
+	user, err := userRepo.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// resolveSubject returns the user a block or report is about, given either directly or as the owner of a crush
+// Users and crushes of other apps are reported as not found
+func resolveSubject(userRepo userRepository.UserRepository, crushRepo crushRepository.CrushRepository, caller *userModels.User, userID, crushID *uuid.UUID) (uuid.UUID, error) {
+	if (userID == nil) == (crushID == nil) {
+		return uuid.Nil, errors.New("either user_id or crush_id is required")
+	}
+
+	if crushID != nil {
+		crush, err := crushRepo.FindByID(*crushID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return uuid.Nil, errors.New("crush not found")
+			}
+			return uuid.Nil, err
+		}
+		owner, err := userRepo.FindByID(crush.UserID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return uuid.Nil, err
+		}
+		if err != nil || owner.AppName != caller.AppName {
+			return uuid.Nil, errors.New("crush not found")
+		}
+		return owner.ID, nil
+	}
+
+	subject, err := findUser(userRepo, *userID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if subject.AppName != caller.AppName {
+		return uuid.Nil, errors.New("user not found")
+	}
+	return subject.ID, nil
+}
+
+// isUniqueViolation reports whether err is a Postgres unique constraint violation (SQLSTATE 23505)
+func isUniqueViolation(err error) bool {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return true
+	}
+	return strings.Contains(err.Error(), "23505")
+}
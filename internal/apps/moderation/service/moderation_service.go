@@ -0,0 +1,309 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	crushRepository "go-backend/internal/apps/crush/repository"
+	"go-backend/internal/apps/moderation/models"
+	"go-backend/internal/apps/moderation/repository"
+	notificationModels "go-backend/internal/apps/notification/models"
+	userRepository "go-backend/internal/apps/user/repository"
+	"go-backend/internal/common/middleware"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ModerationService defines business logic for abuse reports and the moderation queue
+type ModerationService interface {
+	CreateReport(reporterID uuid.UUID, req models.CreateReportRequest) (*models.ReportReceiptResponse, error)
+	ListReports(appName, status string, page, pageSize int) (*models.PaginatedReportsResponse, error)
+	GetReport(id uuid.UUID) (*models.ReportDetailResponse, error)
+	TakeAction(reportID uuid.UUID, req models.ModerationActionRequest, admin *middleware.AdminPrincipal) (*models.ModerationAction, error)
+	ReinstateUser(userID uuid.UUID, req models.ReinstateUserRequest, admin *middleware.AdminPrincipal) (*models.ModerationAction, error)
+}
+
+// Notifier delivers moderation warnings to users
+type Notifier interface {
+	Notify(userID uuid.UUID, appName, notificationType string, payload map[string]interface{}) error
+}
+
+// moderationService implements ModerationService
+type moderationService struct {
+	repo      repository.ReportRepository
+	userRepo  userRepository.UserRepository
+	crushRepo crushRepository.CrushRepository
+	notifier  Notifier
+}
+
+// NewModerationService creates a new instance of ModerationService
+func NewModerationService(repo repository.ReportRepository, userRepo userRepository.UserRepository, crushRepo crushRepository.CrushRepository, notifier Notifier) ModerationService {
+	return &moderationService{
+		repo:      repo,
+		userRepo:  userRepo,
+		crushRepo: crushRepo,
+		notifier:  notifier,
+	}
+}
+
+// CreateReport files a report against a user, given directly or as the owner of a crush
+// Crush reports keep a snapshot of the crush's name and metadata so edits cannot hide the abuse
+func (s *moderationService) CreateReport(reporterID uuid.UUID, req models.CreateReportRequest) (*models.ReportReceiptResponse, error) {
+	reporter, err := findUser(s.userRepo, reporterID)
+	if err != nil {
+		return nil, err
+	}
+	if reporter.IsSuspended() {
+		return nil, errors.New("user is suspended")
+	}
+
+	reportedID, err := resolveSubject(s.userRepo, s.crushRepo, reporter, req.UserID, req.CrushID)
+	if err != nil {
+		return nil, err
+	}
+	if reportedID == reporter.ID {
+		return nil, errors.New("you cannot report yourself")
+	}
+
+	// Repeated reports of the same thing return the open one
+	existing, err := s.repo.FindOpenDuplicate(reporter.ID, reportedID, req.CrushID)
+	if err == nil {
+		receipt := existing.ToReceipt()
+		return &receipt, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	evidence := models.Metadata{}
+	if req.CrushID != nil {
+		crush, err := s.crushRepo.FindByID(*req.CrushID)
+		if err != nil {
+			return nil, err
+		}
+		evidence["crush_name"] = crush.Name
+		evidence["crush_metadata"] = crush.Metadata
+	}
+
+	report := &models.Report{
+		AppName:        reporter.AppName,
+		ReporterID:     reporter.ID,
+		ReportedUserID: reportedID,
+		CrushID:        req.CrushID,
+		Reason:         req.Reason,
+		Details:        req.Details,
+		Evidence:       evidence,
+		Status:         models.ReportStatusOpen,
+	}
+	if err := s.repo.Create(report); err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("[Moderation] Report %s filed against user %s (%s)\n", report.ID, reportedID, report.Reason)
+
+	receipt := report.ToReceipt()
+	return &receipt, nil
+}
+
+// ListReports retrieves reports with pagination, oldest first; status defaults to open
+func (s *moderationService) ListReports(appName, status string, page, pageSize int) (*models.PaginatedReportsResponse, error) {
+	// Validate page and pageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10 // default page size
+	}
+	if pageSize > 100 {
+		pageSize = 100 // max page size
+	}
+	if status == "" {
+		status = models.ReportStatusOpen
+	}
+
+	reports, total, err := s.repo.FindPaginated(appName, status, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	// Calculate total pages
+	totalPages := int(total) / pageSize
+	if int(total)%pageSize > 0 {
+		totalPages++
+	}
+
+	// Calculate next and previous pages
+	var nextPage, prevPage *int
+	if page > 1 {
+		prev := page - 1
+		prevPage = &prev
+	}
+	if page < totalPages {
+		next := page + 1
+		nextPage = &next
+	}
+
+	return &models.PaginatedReportsResponse{
+		Data:       reports,
+		Page:       page,
+		PageSize:   pageSize,
+		Total:      total,
+		TotalPages: totalPages,
+		NextPage:   nextPage,
+		PrevPage:   prevPage,
+	}, nil
+}
+
+// GetReport retrieves a report with the actions taken on it and the reported user's history
+func (s *moderationService) GetReport(id uuid.UUID) (*models.ReportDetailResponse, error) {
+	report, err := s.findReport(id)
+	if err != nil {
+		return nil, err
+	}
+
+	actions, err := s.repo.FindActionsByReportID(report.ID)
+	if err != nil {
+		return nil, err
+	}
+	count, err := s.repo.CountByReportedUserID(report.ReportedUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	suspended := false
+	if user, err := s.userRepo.FindByID(report.ReportedUserID); err == nil {
+		suspended = user.IsSuspended()
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	return &models.ReportDetailResponse{
+		Report:             *report,
+		Actions:            actions,
+		ReportsAgainstUser: count,
+		UserSuspended:      suspended,
+	}, nil
+}
+
+// TakeAction applies a moderation action to the user a report is about and records it
+// Dismissing only applies to open reports; other actions can follow each other on the same report
+func (s *moderationService) TakeAction(reportID uuid.UUID, req models.ModerationActionRequest, admin *middleware.AdminPrincipal) (*models.ModerationAction, error) {
+	report, err := s.findReport(reportID)
+	if err != nil {
+		return nil, err
+	}
+	if report.Status == models.ReportStatusDismissed {
+		return nil, errors.New("report is dismissed")
+	}
+
+	action := &models.ModerationAction{
+		ReportID:   &report.ID,
+		UserID:     report.ReportedUserID,
+		Action:     req.Action,
+		Note:       req.Note,
+		AdminKeyID: admin.KeyID,
+		AdminName:  admin.Name,
+	}
+
+	switch req.Action {
+	case models.ActionWarn:
+		payload := map[string]interface{}{"report_id": report.ID, "reason": report.Reason}
+		if req.Note != nil {
+			payload["note"] = *req.Note
+		}
+		if err := s.notifier.Notify(report.ReportedUserID, report.AppName, notificationModels.TypeModerationWarning, payload); err != nil {
+			return nil, err
+		}
+	case models.ActionSuspendUser:
+		if err := s.suspendUser(report.ReportedUserID, report.Reason, req.Note); err != nil {
+			return nil, err
+		}
+	case models.ActionPurgeCrushes:
+		purged, err := s.crushRepo.DeleteByUserID(report.ReportedUserID)
+		if err != nil {
+			return nil, err
+		}
+		action.Affected = purged
+	case models.ActionDismiss:
+		if report.Status != models.ReportStatusOpen {
+			return nil, errors.New("only open reports can be dismissed")
+		}
+	default:
+		return nil, errors.New("invalid action")
+	}
+
+	if err := s.repo.CreateAction(action); err != nil {
+		return nil, err
+	}
+
+	if report.Status == models.ReportStatusOpen {
+		now := time.Now()
+		report.Status = models.ReportStatusActioned
+		if req.Action == models.ActionDismiss {
+			report.Status = models.ReportStatusDismissed
+		}
+		report.ResolvedAt = &now
+		if err := s.repo.Update(report); err != nil {
+			return nil, err
+		}
+	}
+
+	fmt.Printf("[Moderation] %s applied %s to user %s (report %s)\n", admin.Name, action.Action, action.UserID, report.ID)
+	return action, nil
+}
+
+// ReinstateUser lifts a user's suspension and records it
+func (s *moderationService) ReinstateUser(userID uuid.UUID, req models.ReinstateUserRequest, admin *middleware.AdminPrincipal) (*models.ModerationAction, error) {
+	user, err := findUser(s.userRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+	reinstated, err := s.userRepo.Reinstate(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !reinstated {
+		return nil, errors.New("user is not suspended")
+	}
+
+	action := &models.ModerationAction{
+		UserID:     user.ID,
+		Action:     models.ActionReinstate,
+		Note:       req.Note,
+		AdminKeyID: admin.KeyID,
+		AdminName:  admin.Name,
+	}
+	if err := s.repo.CreateAction(action); err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("[Moderation] %s reinstated user %s\n", admin.Name, user.ID)
+	return action, nil
+}
+
+// suspendUser marks a user as suspended; suspending an already suspended user keeps the original time
+func (s *moderationService) suspendUser(userID uuid.UUID, reason string, note *string) error {
+	if _, err := findUser(s.userRepo, userID); err != nil {
+		return err
+	}
+
+	if note != nil {
+		reason = reason + ": " + *note
+	}
+	_, err := s.userRepo.Suspend(userID, reason)
+	return err
+}
+
+// findReport loads a report, mapping a missing record to "report not found"
+func (s *moderationService) findReport(id uuid.UUID) (*models.Report, error) {
+	report, err := s.repo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("report not found")
+		}
+		return nil, err
+	}
+	return report, nil
+}
@@ -0,0 +1,238 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-backend/internal/apps/notification/models"
+	"go-backend/internal/apps/notification/service"
+	"go-backend/internal/common/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	streamPollInterval      = 5 * time.Second  // Picks up notifications stored by other instances
+	streamHeartbeatInterval = 25 * time.Second // Keeps proxies from closing idle streams
+	streamBatchSize         = 50
+)
+
+// NotificationHandler handles HTTP requests for user notifications
+type NotificationHandler struct {
+	service service.NotificationService
+}
+
+// NewNotificationHandler creates a new instance of NotificationHandler
+func NewNotificationHandler(service service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{service: service}
+}
+
+// ListNotifications handles GET /api/v1/notifications
+func (h *NotificationHandler) ListNotifications(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+		return
+	}
+
+	// Default pagination values
+	page := 1
+	pageSize := 20
+
+	// Parse page parameter
+	if pageStr := c.Query("page"); pageStr != "" {
+		if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
+			page = p
+		}
+	}
+
+	// Parse page_size parameter
+	if pageSizeStr := c.Query("page_size"); pageSizeStr != "" {
+		if ps, err := strconv.Atoi(pageSizeStr); err == nil && ps > 0 {
+			pageSize = ps
+		}
+	}
+
+	unreadOnly := c.Query("unread") == "true"
+
+	resp, err := h.service.ListNotifications(principal.UserID, unreadOnly, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetUnreadCount handles GET /api/v1/notifications/unread-count
+func (h *NotificationHandler) GetUnreadCount(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+		return
+	}
+
+	resp, err := h.service.UnreadCount(principal.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
+
+// MarkRead handles POST /api/v1/notifications/read
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+		return
+	}
+
+	var req models.MarkReadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.service.MarkRead(principal.UserID, req.IDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
+
+// MarkAllRead handles POST /api/v1/notifications/read-all
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+		return
+	}
+
+	resp, err := h.service.MarkAllRead(principal.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
+
+// Stream handles GET /api/v1/notifications/stream
+// Sends notifications as Server-Sent Events; reconnecting clients resume after their Last-Event-ID
+// The stream ends with an expired event when the access token expires, and closes once the user is suspended
+func (h *NotificationHandler) Stream(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+		return
+	}
+	if err := h.service.EnsureActive(principal.UserID); err != nil {
+		status := http.StatusInternalServerError
+		switch err.Error() {
+		case "user not found":
+			status = http.StatusNotFound
+		case "user is suspended":
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+	cursor, err := h.service.StreamCursor(principal.UserID, lastEventID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "invalid last event id" {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Subscribe before the first fetch so nothing stored in between is missed
+	wake, unsubscribe := h.service.Subscribe(principal.UserID)
+	defer unsubscribe()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	// send writes every notification after the cursor and advances it
+	send := func() error {
+		for {
+			notifications, err := h.service.ListAfter(principal.UserID, cursor, streamBatchSize)
+			if err != nil {
+				return err
+			}
+			for _, notification := range notifications {
+				data, err := json.Marshal(notification)
+				if err != nil {
+					return err
+				}
+				if _, err := fmt.Fprintf(c.Writer, "id: %s\nevent: %s\ndata: %s\n\n", notification.ID, notification.Type, data); err != nil {
+					return err
+				}
+				cursor = models.Cursor{CreatedAt: notification.CreatedAt, ID: notification.ID}
+			}
+			c.Writer.Flush()
+			if len(notifications) < streamBatchSize {
+				return nil
+			}
+		}
+	}
+
+	if err := send(); err != nil {
+		fmt.Printf("[NotificationStream] Stream for user %s failed: %v\n", principal.UserID, err)
+		return
+	}
+
+	poll := time.NewTicker(streamPollInterval)
+	defer poll.Stop()
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+	expiry := time.NewTimer(time.Until(principal.ExpiresAt))
+	defer expiry.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-expiry.C:
+			// Clients reconnect with a fresh access token
+			fmt.Fprint(c.Writer, "event: expired\ndata: {}\n\n")
+			c.Writer.Flush()
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(c.Writer, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		case <-wake:
+			if err := send(); err != nil {
+				fmt.Printf("[NotificationStream] Stream for user %s failed: %v\n", principal.UserID, err)
+				return
+			}
+		case <-poll.C:
+			if err := h.service.EnsureActive(principal.UserID); err != nil {
+				fmt.Printf("[NotificationStream] Closing stream for user %s: %v\n", principal.UserID, err)
+				return
+			}
+			if err := send(); err != nil {
+				fmt.Printf("[NotificationStream] Stream for user %s failed: %v\n", principal.UserID, err)
+				return
+			}
+		}
+	}
+}
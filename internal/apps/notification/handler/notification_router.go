@@ -0,0 +1,21 @@
+package handler
+
+import (
+	"go-backend/internal/common/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterNotificationRoutes registers all notification routes for the authenticated user
+// The stream also accepts the access token as an access_token query parameter, since
+// browser EventSource clients cannot set an Authorization header
+func RegisterNotificationRoutes(router *gin.RouterGroup, handler *NotificationHandler, requireAuth gin.HandlerFunc) {
+	notifications := router.Group("/notifications")
+	{
+		notifications.GET("", requireAuth, handler.ListNotifications)
+		notifications.GET("/unread-count", requireAuth, handler.GetUnreadCount)
+		notifications.POST("/read", requireAuth, handler.MarkRead)
+		notifications.POST("/read-all", requireAuth, handler.MarkAllRead)
+		notifications.GET("/stream", middleware.QueryTokenAuth(), requireAuth, handler.Stream)
+	}
+}
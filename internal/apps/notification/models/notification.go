@@ -0,0 +1,118 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Notification types
+const (
+	TypeCrushReceived     = "crush_received"     // Someone added a crush targeting the user's phone or social handles
+	TypeCrushMatch        = "crush_match"        // A mutual match formed
+	TypeModerationWarning = "moderation_warning" // Moderation warned the user about reported behaviour
+)
+
+// Payload is a custom type for the JSONB notification payload
+type Payload map[string]interface{}
+
+// Scan implements the sql.Scanner interface for Payload
+func (p *Payload) Scan(value interface{}) error {
+	if value == nil {
+		*p = make(Payload)
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(bytes, p)
+}
+
+// Value implements the driver.Valuer interface for Payload
+func (p Payload) Value() (driver.Value, error) {
+	if p == nil {
+		return json.Marshal(make(map[string]interface{}))
+	}
+	return json.Marshal(p)
+}
+
+// Notification is an event delivered to a user's inbox
+type Notification struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	AppName   string     `gorm:"not null;size:100" json:"app_name"`
+	Type      string     `gorm:"not null;size:50" json:"type"`
+	Payload   Payload    `gorm:"type:jsonb;not null;default:'{}'" json:"payload"`
+	ReadAt    *time.Time `json:"read_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// TableName sets the table name to 'notifications'
+func (Notification) TableName() string { return "notifications" }
+
+// BeforeCreate hook to generate UUID before creating record
+func (n *Notification) BeforeCreate(tx *gorm.DB) error {
+	if n.ID == uuid.Nil {
+		n.ID = uuid.New()
+	}
+	return nil
+}
+
+// NotificationResponse represents a notification returned to the client
+type NotificationResponse struct {
+	ID        uuid.UUID  `json:"id"`
+	Type      string     `json:"type"`
+	Payload   Payload    `json:"payload"`
+	Read      bool       `json:"read"`
+	ReadAt    *time.Time `json:"read_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// ToResponse converts Notification model to NotificationResponse
+func (n *Notification) ToResponse() NotificationResponse {
+	return NotificationResponse{
+		ID:        n.ID,
+		Type:      n.Type,
+		Payload:   n.Payload,
+		Read:      n.ReadAt != nil,
+		ReadAt:    n.ReadAt,
+		CreatedAt: n.CreatedAt,
+	}
+}
+
+// Cursor marks a position in a user's notification stream
+type Cursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// MarkReadRequest payload to mark specific notifications as read
+type MarkReadRequest struct {
+	IDs []uuid.UUID `json:"ids" binding:"required,min=1,max=100"`
+}
+
+// MarkReadResponse reports how many notifications changed state
+type MarkReadResponse struct {
+	Updated int64 `json:"updated"`
+}
+
+// UnreadCountResponse reports the number of unread notifications
+type UnreadCountResponse struct {
+	Unread int64 `json:"unread"`
+}
+
+// PaginatedNotificationsResponse represents a page of the user's inbox
+type PaginatedNotificationsResponse struct {
+	Data       []NotificationResponse `json:"data"`
+	Page       int                    `json:"page"`
+	PageSize   int                    `json:"page_size"`
+	Total      int64                  `json:"total"`
+	TotalPages int                    `json:"total_pages"`
+	NextPage   *int                   `json:"next_page"`
+	PrevPage   *int                   `json:"prev_page"`
+	Unread     int64                  `json:"unread"`
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"time"
+
+	"go-backend/internal/apps/notification/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// NotificationRepository defines data operations for notifications
+type NotificationRepository interface {
+	Create(notification *models.Notification) error
+	FindByID(userID, id uuid.UUID) (*models.Notification, error)
+	FindByUserIDPaginated(userID uuid.UUID, unreadOnly bool, page, pageSize int) ([]models.Notification, int64, error)
+	FindAfter(userID uuid.UUID, cursor models.Cursor, limit int) ([]models.Notification, error)
+	CountUnread(userID uuid.UUID) (int64, error)
+	MarkRead(userID uuid.UUID, ids []uuid.UUID) (int64, error)
+	MarkAllRead(userID uuid.UUID) (int64, error)
+}
+
+// notificationRepository implements NotificationRepository
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationRepository creates an instance of NotificationRepository
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepository{db: db}
+}
+
+// Create stores a new notification
+func (r *notificationRepository) Create(notification *models.Notification) error {
+	return r.db.Create(notification).Error
+}
+
+// FindByID retrieves one of the user's notifications
+func (r *notificationRepository) FindByID(userID, id uuid.UUID) (*models.Notification, error) {
+	var notification models.Notification
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&notification).Error; err != nil {
+		return nil, err
+	}
+	return &notification, nil
+}
+
+// FindByUserIDPaginated retrieves a page of the user's notifications, newest first
+func (r *notificationRepository) FindByUserIDPaginated(userID uuid.UUID, unreadOnly bool, page, pageSize int) ([]models.Notification, int64, error) {
+	var notifications []models.Notification
+	var total int64
+
+	query := r.db.Model(&models.Notification{}).Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+
+	// Get total count
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Get paginated results
+	offset := (page - 1) * pageSize
+	if err := query.Order("created_at DESC, id DESC").Offset(offset).Limit(pageSize).Find(&notifications).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return notifications, total, nil
+}
+
+// FindAfter retrieves the user's notifications created after the cursor, oldest first
+func (r *notificationRepository) FindAfter(userID uuid.UUID, cursor models.Cursor, limit int) ([]models.Notification, error) {
+	var notifications []models.Notification
+	err := r.db.Where("user_id = ? AND (created_at, id) > (?, ?)", userID, cursor.CreatedAt, cursor.ID).
+		Order("created_at ASC, id ASC").
+		Limit(limit).
+		Find(&notifications).Error
+	return notifications, err
+}
+
+// CountUnread counts the user's unread notifications
+func (r *notificationRepository) CountUnread(userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Notification{}).Where("user_id = ? AND read_at IS NULL", userID).Count(&count).Error
+	return count, err
+}
+
+// MarkRead marks the given notifications of the user as read and returns how many changed
+func (r *notificationRepository) MarkRead(userID uuid.UUID, ids []uuid.UUID) (int64, error) {
+	result := r.db.Model(&models.Notification{}).
+		Where("user_id = ? AND id IN ? AND read_at IS NULL", userID, ids).
+		Update("read_at", time.Now())
+	return result.RowsAffected, result.Error
+}
+
+// MarkAllRead marks all of the user's notifications as read and returns how many changed
+func (r *notificationRepository) MarkAllRead(userID uuid.UUID) (int64, error) {
+	result := r.db.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", time.Now())
+	return result.RowsAffected, result.Error
+}
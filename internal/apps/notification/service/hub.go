@@ -0,0 +1,53 @@
+package service
+
+import (
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// hub wakes up a user's open streams when a notification is stored on this instance
+// Streams on other instances pick the notification up on their next poll
+type hub struct {
+	mu          sync.Mutex
+	subscribers map[uuid.UUID]map[chan struct{}]struct{}
+}
+
+// newHub creates an empty hub
+func newHub() *hub {
+	return &hub{subscribers: make(map[uuid.UUID]map[chan struct{}]struct{})}
+}
+
+// subscribe registers a wake-up channel for the user and returns it with its cancel function
+func (h *hub) subscribe(userID uuid.UUID) (<-chan struct{}, func()) {
+	// Buffered so a publish never blocks; one pending wake-up is enough to trigger a fetch
+	ch := make(chan struct{}, 1)
+
+	h.mu.Lock()
+	if h.subscribers[userID] == nil {
+		h.subscribers[userID] = make(map[chan struct{}]struct{})
+	}
+	h.subscribers[userID][ch] = struct{}{}
+	h.mu.Unlock()
+
+	return ch, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		delete(h.subscribers[userID], ch)
+		if len(h.subscribers[userID]) == 0 {
+			delete(h.subscribers, userID)
+		}
+	}
+}
+
+// publish wakes up every stream of the user
+func (h *hub) publish(userID uuid.UUID) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subscribers[userID] {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
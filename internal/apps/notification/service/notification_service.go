@@ -0,0 +1,195 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"go-backend/internal/apps/notification/models"
+	"go-backend/internal/apps/notification/repository"
+	userRepository "go-backend/internal/apps/user/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// NotificationService defines business logic for user notifications
+type NotificationService interface {
+	Notify(userID uuid.UUID, appName, notificationType string, payload map[string]interface{}) error
+	ListNotifications(userID uuid.UUID, unreadOnly bool, page, pageSize int) (*models.PaginatedNotificationsResponse, error)
+	UnreadCount(userID uuid.UUID) (*models.UnreadCountResponse, error)
+	MarkRead(userID uuid.UUID, ids []uuid.UUID) (*models.MarkReadResponse, error)
+	MarkAllRead(userID uuid.UUID) (*models.MarkReadResponse, error)
+	Subscribe(userID uuid.UUID) (<-chan struct{}, func())
+	StreamCursor(userID uuid.UUID, lastEventID string) (models.Cursor, error)
+	ListAfter(userID uuid.UUID, cursor models.Cursor, limit int) ([]models.NotificationResponse, error)
+	EnsureActive(userID uuid.UUID) error
+}
+
+// notificationService implements NotificationService
+type notificationService struct {
+	repo     repository.NotificationRepository
+	userRepo userRepository.UserRepository
+	hub      *hub
+}
+
+// NewNotificationService creates a new instance of NotificationService
+// userRepo is used to end the streams of users who were suspended or deleted
+func NewNotificationService(repo repository.NotificationRepository, userRepo userRepository.UserRepository) NotificationService {
+	return &notificationService{repo: repo, userRepo: userRepo, hub: newHub()}
+}
+
+// Notify stores a notification for the user and wakes up their open streams
+func (s *notificationService) Notify(userID uuid.UUID, appName, notificationType string, payload map[string]interface{}) error {
+	notification := &models.Notification{
+		UserID:  userID,
+		AppName: appName,
+		Type:    notificationType,
+		Payload: models.Payload(payload),
+	}
+	if err := s.repo.Create(notification); err != nil {
+		return err
+	}
+
+	s.hub.publish(userID)
+	return nil
+}
+
+// ListNotifications retrieves a page of the user's inbox, newest first
+func (s *notificationService) ListNotifications(userID uuid.UUID, unreadOnly bool, page, pageSize int) (*models.PaginatedNotificationsResponse, error) {
+	// Validate page and pageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 20 // default page size
+	}
+	if pageSize > 100 {
+		pageSize = 100 // max page size
+	}
+
+	notifications, total, err := s.repo.FindByUserIDPaginated(userID, unreadOnly, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	unread, err := s.repo.CountUnread(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.NotificationResponse, len(notifications))
+	for i, notification := range notifications {
+		responses[i] = notification.ToResponse()
+	}
+
+	// Calculate total pages
+	totalPages := int(total) / pageSize
+	if int(total)%pageSize > 0 {
+		totalPages++
+	}
+
+	// Calculate next and previous pages
+	var nextPage, prevPage *int
+	if page > 1 {
+		prev := page - 1
+		prevPage = &prev
+	}
+	if page < totalPages {
+		next := page + 1
+		nextPage = &next
+	}
+
+	return &models.PaginatedNotificationsResponse{
+		Data:       responses,
+		Page:       page,
+		PageSize:   pageSize,
+		Total:      total,
+		TotalPages: totalPages,
+		NextPage:   nextPage,
+		PrevPage:   prevPage,
+		Unread:     unread,
+	}, nil
+}
+
+// UnreadCount returns the number of unread notifications
+func (s *notificationService) UnreadCount(userID uuid.UUID) (*models.UnreadCountResponse, error) {
+	unread, err := s.repo.CountUnread(userID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.UnreadCountResponse{Unread: unread}, nil
+}
+
+// MarkRead marks the given notifications as read; IDs of other users' notifications are ignored
+func (s *notificationService) MarkRead(userID uuid.UUID, ids []uuid.UUID) (*models.MarkReadResponse, error) {
+	updated, err := s.repo.MarkRead(userID, ids)
+	if err != nil {
+		return nil, err
+	}
+	return &models.MarkReadResponse{Updated: updated}, nil
+}
+
+// MarkAllRead marks every notification of the user as read
+func (s *notificationService) MarkAllRead(userID uuid.UUID) (*models.MarkReadResponse, error) {
+	updated, err := s.repo.MarkAllRead(userID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.MarkReadResponse{Updated: updated}, nil
+}
+
+// Subscribe returns a channel that signals when new notifications may be available for the user
+func (s *notificationService) Subscribe(userID uuid.UUID) (<-chan struct{}, func()) {
+	return s.hub.subscribe(userID)
+}
+
+// StreamCursor resolves where a stream should start
+// A reconnecting client resumes after its Last-Event-ID; a new client only receives notifications from now on
+func (s *notificationService) StreamCursor(userID uuid.UUID, lastEventID string) (models.Cursor, error) {
+	if lastEventID == "" {
+		return models.Cursor{CreatedAt: time.Now()}, nil
+	}
+
+	id, err := uuid.Parse(lastEventID)
+	if err != nil {
+		return models.Cursor{}, errors.New("invalid last event id")
+	}
+
+	notification, err := s.repo.FindByID(userID, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.Cursor{}, errors.New("invalid last event id")
+		}
+		return models.Cursor{}, err
+	}
+	return models.Cursor{CreatedAt: notification.CreatedAt, ID: notification.ID}, nil
+}
+
+// ListAfter retrieves notifications created after the cursor, oldest first
+func (s *notificationService) ListAfter(userID uuid.UUID, cursor models.Cursor, limit int) ([]models.NotificationResponse, error) {
+	notifications, err := s.repo.FindAfter(userID, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.NotificationResponse, len(notifications))
+	for i, notification := range notifications {
+		responses[i] = notification.ToResponse()
+	}
+	return responses, nil
+}
+
+// EnsureActive checks that the user still exists and is not suspended, so an open stream may go on
+func (s *notificationService) EnsureActive(userID uuid.UUID) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("user not found")
+		}
+		return err
+	}
+	if user.IsSuspended() {
+		return errors.New("user is suspended")
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"go-backend/internal/apps/otp/models"
+	"go-backend/internal/apps/otp/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MagicLinkHandler handles HTTP endpoints for magic link email login
+type MagicLinkHandler struct {
+	service service.MagicLinkService
+}
+
+// NewMagicLinkHandler creates a new instance of MagicLinkHandler
+func NewMagicLinkHandler(service service.MagicLinkService) *MagicLinkHandler {
+	return &MagicLinkHandler{service: service}
+}
+
+// CreateMagicLink handles POST /api/v1/otp/email/magic-link
+func (h *MagicLinkHandler) CreateMagicLink(c *gin.Context) {
+	var req models.CreateMagicLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.service.CreateMagicLink(req, c.ClientIP())
+	if err != nil {
+		if errors.Is(err, service.ErrRedirectURLNotAllowed) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": "redirect_url_not_allowed"})
+			return
+		}
+		status, body := otpErrorResponse(err)
+		c.JSON(status, body)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": resp})
+}
+
+// VerifyMagicLink handles GET /api/v1/otp/email/magic-link/verify?token=...
+func (h *MagicLinkHandler) VerifyMagicLink(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "token is required"})
+		return
+	}
+
+	resp, err := h.service.VerifyMagicLink(token)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "magic link not found" {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
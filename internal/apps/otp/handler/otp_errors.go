@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	authService "go-backend/internal/apps/auth/service"
+	"go-backend/internal/apps/otp/service"
+	"go-backend/pkg/identifier"
+
+	"github.com/gin-gonic/gin"
+)
+
+// throttleErrorCode maps OTP throttling errors to a machine-readable code
+func throttleErrorCode(err error) (string, bool) {
+	switch {
+	case errors.Is(err, service.ErrOTPAttemptsExceeded):
+		return "otp_attempts_exceeded", true
+	case errors.Is(err, service.ErrOTPResendCooldown):
+		return "otp_resend_cooldown", true
+	case errors.Is(err, service.ErrOTPRecipientLimit):
+		return "otp_recipient_limit", true
+	case errors.Is(err, service.ErrOTPIPLimit):
+		return "otp_ip_limit", true
+	}
+	return "", false
+}
+
+// policyErrorCode maps OTP policy rejections to a machine-readable code
+func policyErrorCode(err error) (string, bool) {
+	switch {
+	case errors.Is(err, service.ErrOTPChannelNotAllowed):
+		return "otp_channel_not_allowed", true
+	case errors.Is(err, service.ErrOTPCountryNotAllowed):
+		return "otp_country_not_allowed", true
+	}
+	return "", false
+}
+
+// otpErrorResponse returns the status and body for an OTP service error
+func otpErrorResponse(err error) (int, gin.H) {
+	if code, ok := throttleErrorCode(err); ok {
+		return http.StatusTooManyRequests, gin.H{"error": err.Error(), "code": code}
+	}
+	if code, ok := policyErrorCode(err); ok {
+		return http.StatusBadRequest, gin.H{"error": err.Error(), "code": code}
+	}
+	if errors.Is(err, identifier.ErrInvalidPhone) || errors.Is(err, identifier.ErrInvalidCountryCode) || errors.Is(err, identifier.ErrInvalidEmail) {
+		return http.StatusBadRequest, gin.H{"error": err.Error()}
+	}
+	if errors.Is(err, authService.ErrUserSuspended) {
+		return http.StatusForbidden, gin.H{"error": err.Error(), "code": "user_suspended"}
+	}
+	status := http.StatusInternalServerError
+	if err.Error() == "otp not found" {
+		status = http.StatusNotFound
+	}
+	return status, gin.H{"error": err.Error()}
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"go-backend/internal/apps/otp/models"
+	"go-backend/internal/apps/otp/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// OTPPolicyHandler handles HTTP endpoints for managing per-app OTP policies
+type OTPPolicyHandler struct {
+	service service.OTPPolicyService
+}
+
+// NewOTPPolicyHandler creates a new instance of OTPPolicyHandler
+func NewOTPPolicyHandler(service service.OTPPolicyService) *OTPPolicyHandler {
+	return &OTPPolicyHandler{service: service}
+}
+
+// policyErrorStatus maps OTP policy service errors to HTTP status codes
+func policyErrorStatus(err error) int {
+	switch {
+	case err.Error() == "otp policy not found":
+		return http.StatusNotFound
+	case err.Error() == "otp policy already exists for app_name":
+		return http.StatusConflict
+	case strings.HasPrefix(err.Error(), "invalid otp policy"):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// CreatePolicy handles POST /api/v1/otp/policies
+func (h *OTPPolicyHandler) CreatePolicy(c *gin.Context) {
+	var req models.CreateOTPPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	policy, err := h.service.CreatePolicy(req)
+	if err != nil {
+		c.JSON(policyErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": policy})
+}
+
+// ListPolicies handles GET /api/v1/otp/policies
+func (h *OTPPolicyHandler) ListPolicies(c *gin.Context) {
+	policies, err := h.service.ListPolicies()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": policies})
+}
+
+// GetPolicy handles GET /api/v1/otp/policies/:id
+func (h *OTPPolicyHandler) GetPolicy(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid UUID"})
+		return
+	}
+
+	policy, err := h.service.GetPolicy(id)
+	if err != nil {
+		c.JSON(policyErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": policy})
+}
+
+// UpdatePolicy handles PUT /api/v1/otp/policies/:id
+func (h *OTPPolicyHandler) UpdatePolicy(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid UUID"})
+		return
+	}
+
+	var req models.UpdateOTPPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	policy, err := h.service.UpdatePolicy(id, req)
+	if err != nil {
+		c.JSON(policyErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": policy})
+}
+
+// DeletePolicy handles DELETE /api/v1/otp/policies/:id
+func (h *OTPPolicyHandler) DeletePolicy(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid UUID"})
+		return
+	}
+
+	if err := h.service.DeletePolicy(id); err != nil {
+		c.JSON(policyErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "otp policy deleted successfully"})
+}
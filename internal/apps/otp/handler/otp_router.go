@@ -1,11 +1,21 @@
 package handler
 
 import (
+	"go-backend/internal/common/middleware"
+
 	"github.com/gin-gonic/gin"
 )
 
 // RegisterOTPRoutes registers all OTP routes
-func RegisterOTPRoutes(router *gin.RouterGroup, phoneOTPHandler *PhoneOTPHandler, emailOTPHandler *EmailOTPHandler) {
+// Policy management is restricted to admin API keys; writes require the admin role
+func RegisterOTPRoutes(
+	router *gin.RouterGroup,
+	phoneOTPHandler *PhoneOTPHandler,
+	emailOTPHandler *EmailOTPHandler,
+	magicLinkHandler *MagicLinkHandler,
+	policyHandler *OTPPolicyHandler,
+	adminGuard middleware.AdminGuard,
+) {
 	otp := router.Group("/otp")
 	{
 		// Phone OTP routes
@@ -20,6 +30,18 @@ func RegisterOTPRoutes(router *gin.RouterGroup, phoneOTPHandler *PhoneOTPHandler
 		{
 			email.POST("", emailOTPHandler.CreateOrUpdateOTP)
 			email.POST("/verify", emailOTPHandler.VerifyOTP)
+			email.POST("/magic-link", magicLinkHandler.CreateMagicLink)
+			email.GET("/magic-link/verify", magicLinkHandler.VerifyMagicLink)
+		}
+
+		// Per-app OTP policy routes
+		policies := otp.Group("/policies")
+		{
+			policies.GET("", adminGuard(), policyHandler.ListPolicies)
+			policies.GET("/:id", adminGuard(), policyHandler.GetPolicy)
+			policies.POST("", adminGuard(middleware.RoleAdmin), policyHandler.CreatePolicy)
+			policies.PUT("/:id", adminGuard(middleware.RoleAdmin), policyHandler.UpdatePolicy)
+			policies.DELETE("/:id", adminGuard(middleware.RoleAdmin), policyHandler.DeletePolicy)
 		}
 	}
 }
@@ -27,9 +27,10 @@ func (h *PhoneOTPHandler) CreateOrUpdateOTP(c *gin.Context) {
 		return
 	}
 
-	otp, err := h.service.CreateOrUpdateOTP(req)
+	otp, err := h.service.CreateOrUpdateOTP(req, c.ClientIP())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		status, body := otpErrorResponse(err)
+		c.JSON(status, body)
 		return
 	}
 	c.JSON(http.StatusCreated, gin.H{"data": otp})
@@ -45,11 +46,8 @@ func (h *PhoneOTPHandler) VerifyOTP(c *gin.Context) {
 
 	resp, err := h.service.VerifyOTP(req)
 	if err != nil {
-		status := http.StatusInternalServerError
-		if err.Error() == "otp not found" {
-			status = http.StatusNotFound
-		}
-		c.JSON(status, gin.H{"error": err.Error()})
+		status, body := otpErrorResponse(err)
+		c.JSON(status, body)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"data": resp})
@@ -3,6 +3,8 @@ package models
 import (
 	"time"
 
+	authModels "go-backend/internal/apps/auth/models"
+
 	"github.com/google/uuid"
 )
 
@@ -10,8 +12,9 @@ import (
 type EmailOTP struct {
 	ID        uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
 	AppName   string    `gorm:"size:255;not null" json:"app_name"`
-	Email     string    `gorm:"size:255;not null" json:"email"`
-	Value     string    `gorm:"size:6;not null" json:"-"`
+	Email     string    `gorm:"size:255;not null" json:"email"` // Normalized address
+	Value     string    `gorm:"size:64;not null" json:"-"`      // Keyed hash of the OTP, never the plaintext
+	Attempts  int       `gorm:"not null;default:0" json:"-"`
 	ExpiresAt time.Time `json:"expires_at"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
@@ -39,7 +42,12 @@ type VerifyEmailOTPRequest struct {
 }
 
 // VerifyEmailOTPResponse indicates verification result
+// Session is only present when the OTP is valid; MFA replaces it when the user has enrolled a second factor
 type VerifyEmailOTPResponse struct {
-	Valid   bool   `json:"valid"`
-	Message string `json:"message"`
+	Valid             bool                             `json:"valid"`
+	Message           string                           `json:"message"`
+	AttemptsRemaining *int                             `json:"attempts_remaining,omitempty"`
+	RedirectURL       string                           `json:"redirect_url,omitempty"` // Set when verified through a magic link
+	Session           *authModels.SessionResponse      `json:"session,omitempty"`
+	MFA               *authModels.MFAChallengeResponse `json:"mfa,omitempty"`
 }
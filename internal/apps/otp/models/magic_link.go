@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MagicLinkToken is a single-use email login link; only a hash of the signed token is stored
+type MagicLinkToken struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	AppName     string     `gorm:"size:255;not null" json:"app_name"`
+	Email       string     `gorm:"size:255;not null" json:"email"` // Normalized address
+	TokenHash   string     `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	RedirectURL string     `gorm:"size:2048;not null" json:"redirect_url"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	ConsumedAt  *time.Time `json:"consumed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// TableName sets the table name to 'magic_link_tokens'
+func (MagicLinkToken) TableName() string { return "magic_link_tokens" }
+
+// CreateMagicLinkRequest payload to email a magic login link
+// RedirectURL must be whitelisted in the app's OTP policy; the token is appended as ?token=
+type CreateMagicLinkRequest struct {
+	AppName     string `json:"app_name" binding:"required"`
+	Email       string `json:"email" binding:"required,email"`
+	RedirectURL string `json:"redirect_url" binding:"required,url"`
+}
+
+// MagicLinkResponse represents the response after sending a magic link (without exposing the token)
+type MagicLinkResponse struct {
+	ExpiresAt time.Time `json:"expires_at"`
+}
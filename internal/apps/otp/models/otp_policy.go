@@ -0,0 +1,102 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Email OTP channel name used in a policy's allowed channels
+// Phone channels are PhoneChannelSMS, PhoneChannelWhatsApp and PhoneChannelVoice
+const PolicyChannelEmail = "email"
+
+// StringList is a custom type for JSONB string arrays
+type StringList []string
+
+// Scan implements the sql.Scanner interface for StringList
+func (l *StringList) Scan(value interface{}) error {
+	if value == nil {
+		*l = StringList{}
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(bytes, l)
+}
+
+// Value implements the driver.Valuer interface for StringList
+func (l StringList) Value() (driver.Value, error) {
+	if l == nil {
+		return json.Marshal([]string{})
+	}
+	return json.Marshal([]string(l))
+}
+
+// OTPPolicy is the persisted OTP configuration for an app
+// Zero values and empty lists fall back to the server defaults
+type OTPPolicy struct {
+	ID                    uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	AppName               string         `gorm:"not null;size:100" json:"app_name"`
+	TTLSeconds            int            `gorm:"not null;default:0" json:"ttl_seconds"`
+	Length                int            `gorm:"not null;default:0" json:"length"`
+	ResendCooldownSeconds int            `gorm:"not null;default:0" json:"resend_cooldown_seconds"`
+	MaxAttempts           int            `gorm:"not null;default:0" json:"max_attempts"`
+	HourlyRecipientCap    int            `gorm:"not null;default:0" json:"hourly_recipient_cap"`
+	HourlyIPCap           int            `gorm:"not null;default:0" json:"hourly_ip_cap"`
+	AllowedChannels       StringList     `gorm:"type:jsonb;not null;default:'[]'" json:"allowed_channels"`
+	AllowedCountryCodes   StringList     `gorm:"type:jsonb;not null;default:'[]'" json:"allowed_country_codes"`
+	AllowedRedirectURLs   StringList     `gorm:"type:jsonb;not null;default:'[]'" json:"allowed_redirect_urls"`
+	SenderName            string         `gorm:"size:100" json:"sender_name"`
+	TemplateID            string         `gorm:"size:255" json:"template_id"`
+	CreatedAt             time.Time      `json:"created_at"`
+	UpdatedAt             time.Time      `json:"updated_at"`
+	DeletedAt             gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// TableName sets the table name to 'otp_policies'
+func (OTPPolicy) TableName() string { return "otp_policies" }
+
+// BeforeCreate hook to generate UUID before creating record
+func (p *OTPPolicy) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return nil
+}
+
+// CreateOTPPolicyRequest represents the request body for creating an app's OTP policy
+type CreateOTPPolicyRequest struct {
+	AppName               string     `json:"app_name" binding:"required,min=1,max=100"`
+	TTLSeconds            int        `json:"ttl_seconds" binding:"omitempty,min=30,max=86400"`
+	Length                int        `json:"length" binding:"omitempty,min=4,max=8"`
+	ResendCooldownSeconds int        `json:"resend_cooldown_seconds" binding:"omitempty,min=0,max=3600"`
+	MaxAttempts           int        `json:"max_attempts" binding:"omitempty,min=1,max=20"`
+	HourlyRecipientCap    int        `json:"hourly_recipient_cap" binding:"omitempty,min=1"`
+	HourlyIPCap           int        `json:"hourly_ip_cap" binding:"omitempty,min=1"`
+	AllowedChannels       StringList `json:"allowed_channels" binding:"omitempty,dive,oneof=sms whatsapp voice email"`
+	AllowedCountryCodes   StringList `json:"allowed_country_codes" binding:"omitempty,dive,startswith=+"`
+	AllowedRedirectURLs   StringList `json:"allowed_redirect_urls" binding:"omitempty,dive,url"`
+	SenderName            string     `json:"sender_name" binding:"max=100"`
+	TemplateID            string     `json:"template_id" binding:"max=255"`
+}
+
+// UpdateOTPPolicyRequest represents the request body for updating an app's OTP policy
+// Omitted fields are left unchanged; zero or empty values reset a field to the server default
+type UpdateOTPPolicyRequest struct {
+	TTLSeconds            *int       `json:"ttl_seconds,omitempty" binding:"omitempty,min=0,max=86400"`
+	Length                *int       `json:"length,omitempty" binding:"omitempty,min=0,max=8"`
+	ResendCooldownSeconds *int       `json:"resend_cooldown_seconds,omitempty" binding:"omitempty,min=0,max=3600"`
+	MaxAttempts           *int       `json:"max_attempts,omitempty" binding:"omitempty,min=0,max=20"`
+	HourlyRecipientCap    *int       `json:"hourly_recipient_cap,omitempty" binding:"omitempty,min=0"`
+	HourlyIPCap           *int       `json:"hourly_ip_cap,omitempty" binding:"omitempty,min=0"`
+	AllowedChannels       StringList `json:"allowed_channels,omitempty" binding:"omitempty,dive,oneof=sms whatsapp voice email"`
+	AllowedCountryCodes   StringList `json:"allowed_country_codes,omitempty" binding:"omitempty,dive,startswith=+"`
+	AllowedRedirectURLs   StringList `json:"allowed_redirect_urls,omitempty" binding:"omitempty,dive,url"`
+	SenderName            *string    `json:"sender_name,omitempty" binding:"omitempty,max=100"`
+	TemplateID            *string    `json:"template_id,omitempty" binding:"omitempty,max=255"`
+}
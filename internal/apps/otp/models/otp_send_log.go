@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OTP delivery channels recorded in send logs
+const (
+	OTPChannelPhone = "phone"
+	OTPChannelEmail = "email"
+)
+
+// OTPSendLog records every OTP issuance, used to throttle resends per recipient and per IP
+type OTPSendLog struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	AppName   string    `gorm:"size:255;not null" json:"app_name"`
+	Channel   string    `gorm:"size:20;not null" json:"channel"`
+	Recipient string    `gorm:"size:255;not null" json:"recipient"`
+	IPAddress string    `gorm:"size:64" json:"ip_address"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName sets the table name to 'otp_send_logs'
+func (OTPSendLog) TableName() string { return "otp_send_logs" }
@@ -3,16 +3,28 @@ package models
 import (
 	"time"
 
+	authModels "go-backend/internal/apps/auth/models"
+
 	"github.com/google/uuid"
 )
 
+// Phone OTP delivery channels
+const (
+	PhoneChannelSMS      = "sms"
+	PhoneChannelWhatsApp = "whatsapp"
+	PhoneChannelVoice    = "voice"
+)
+
 // PhoneOTP represents a one-time password for phone-based authentication
 type PhoneOTP struct {
 	ID          uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
 	AppName     string    `gorm:"size:255;not null" json:"app_name"`
-	CountryCode string    `gorm:"size:5;not null" json:"country_code"`
-	Phone       string    `gorm:"size:20;not null" json:"phone"`
-	Value       string    `gorm:"size:6;not null" json:"-"`
+	CountryCode string    `gorm:"size:5;not null" json:"country_code"` // Normalized, e.g. +91
+	Phone       string    `gorm:"size:20;not null" json:"phone"`       // National significant number
+	Value       string    `gorm:"size:64;not null" json:"-"`           // Keyed hash of the OTP, never the plaintext
+	Attempts    int       `gorm:"not null;default:0" json:"-"`
+	Provider    string    `gorm:"size:50" json:"provider"` // Provider that delivered the OTP
+	Channel     string    `gorm:"size:20" json:"channel"`  // Channel the OTP was delivered on
 	ExpiresAt   time.Time `json:"expires_at"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
@@ -21,16 +33,20 @@ type PhoneOTP struct {
 // PhoneOTPResponse represents the response after creating a phone OTP (without exposing the value)
 type PhoneOTPResponse struct {
 	ExpiresAt time.Time `json:"expires_at"`
+	Channel   string    `json:"channel"`
 }
 
 // TableName sets the table name to 'phone_otp'
 func (PhoneOTP) TableName() string { return "phone_otp" }
 
 // CreatePhoneOTPRequest payload to create or override a phone OTP
+// Channel defaults to sms; FallbackChannels overrides the app's configured fallback order
 type CreatePhoneOTPRequest struct {
-	AppName     string `json:"app_name" binding:"required"`
-	CountryCode string `json:"country_code" binding:"required"`
-	Phone       string `json:"phone" binding:"required"`
+	AppName          string   `json:"app_name" binding:"required"`
+	CountryCode      string   `json:"country_code" binding:"required"`
+	Phone            string   `json:"phone" binding:"required"`
+	Channel          string   `json:"channel" binding:"omitempty,oneof=sms whatsapp voice"`
+	FallbackChannels []string `json:"fallback_channels" binding:"omitempty,dive,oneof=sms whatsapp voice"`
 }
 
 // VerifyPhoneOTPRequest payload to verify phone OTP
@@ -42,7 +58,11 @@ type VerifyPhoneOTPRequest struct {
 }
 
 // VerifyPhoneOTPResponse indicates verification result
+// Session is only present when the OTP is valid; MFA replaces it when the user has enrolled a second factor
 type VerifyPhoneOTPResponse struct {
-	Valid   bool   `json:"valid"`
-	Message string `json:"message"`
+	Valid             bool                             `json:"valid"`
+	Message           string                           `json:"message"`
+	AttemptsRemaining *int                             `json:"attempts_remaining,omitempty"`
+	Session           *authModels.SessionResponse      `json:"session,omitempty"`
+	MFA               *authModels.MFAChallengeResponse `json:"mfa,omitempty"`
 }
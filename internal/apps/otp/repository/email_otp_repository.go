@@ -5,6 +5,7 @@ import (
 
 	"go-backend/internal/apps/otp/models"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
@@ -14,6 +15,9 @@ type EmailOTPRepository interface {
 	Upsert(appName, email, value string, expiresAt time.Time) error
 	FindByEmail(appName, email string) (*models.EmailOTP, error)
 	Delete(appName, email string) error
+	ReserveAttempt(id uuid.UUID, maxAttempts int) (int, bool, error)
+	Consume(id uuid.UUID, maxAttempts int) (bool, error)
+	DeleteExpired(before time.Time) (int64, error)
 }
 
 // emailOTPRepository implements EmailOTPRepository
@@ -36,7 +40,7 @@ func (r *emailOTPRepository) Upsert(appName, email, value string, expiresAt time
 	}
 	return r.db.Clauses(clause.OnConflict{
 		Columns:   []clause.Column{{Name: "app_name"}, {Name: "email"}},
-		DoUpdates: clause.AssignmentColumns([]string{"value", "expires_at", "updated_at"}),
+		DoUpdates: clause.AssignmentColumns([]string{"value", "attempts", "expires_at", "updated_at"}),
 	}).Create(&otp).Error
 }
 
@@ -53,3 +57,31 @@ func (r *emailOTPRepository) FindByEmail(appName, email string) (*models.EmailOT
 func (r *emailOTPRepository) Delete(appName, email string) error {
 	return r.db.Where("app_name = ? AND email = ?", appName, email).Delete(&models.EmailOTP{}).Error
 }
+
+// ReserveAttempt atomically records a verification attempt on an unexpired OTP with attempts left
+// and returns the new attempt count; returns false when no attempt could be reserved
+func (r *emailOTPRepository) ReserveAttempt(id uuid.UUID, maxAttempts int) (int, bool, error) {
+	var attempts []int
+	err := r.db.Raw("UPDATE email_otp SET attempts = attempts + 1 WHERE id = ? AND attempts < ? AND expires_at >= ? RETURNING attempts",
+		id, maxAttempts, time.Now()).Scan(&attempts).Error
+	if err != nil || len(attempts) == 0 {
+		return 0, false, err
+	}
+	return attempts[0], true, nil
+}
+
+// Consume atomically deletes an unexpired OTP by ID that has not used more than maxAttempts attempts
+// Returns false if the OTP was already used, expired, replaced or exhausted
+func (r *emailOTPRepository) Consume(id uuid.UUID, maxAttempts int) (bool, error) {
+	result := r.db.Where("id = ? AND attempts <= ? AND expires_at >= ?", id, maxAttempts, time.Now()).Delete(&models.EmailOTP{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// DeleteExpired removes OTPs that expired before the given time and returns how many were removed
+func (r *emailOTPRepository) DeleteExpired(before time.Time) (int64, error) {
+	result := r.db.Where("expires_at < ?", before).Delete(&models.EmailOTP{})
+	return result.RowsAffected, result.Error
+}
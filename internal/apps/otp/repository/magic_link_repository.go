@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"time"
+
+	"go-backend/internal/apps/otp/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MagicLinkRepository defines data operations for magic link tokens
+type MagicLinkRepository interface {
+	Create(token *models.MagicLinkToken) error
+	FindByHash(tokenHash string) (*models.MagicLinkToken, error)
+	FindLatest(appName, email string) (*models.MagicLinkToken, error)
+	Consume(id uuid.UUID) (bool, error)
+	DeleteExpired(before time.Time) (int64, error)
+}
+
+// magicLinkRepository implements MagicLinkRepository
+type magicLinkRepository struct {
+	db *gorm.DB
+}
+
+// NewMagicLinkRepository creates an instance of MagicLinkRepository
+func NewMagicLinkRepository(db *gorm.DB) MagicLinkRepository {
+	return &magicLinkRepository{db: db}
+}
+
+// Create stores a new magic link token
+func (r *magicLinkRepository) Create(token *models.MagicLinkToken) error {
+	return r.db.Create(token).Error
+}
+
+// FindByHash retrieves a magic link token by the hash of its value
+func (r *magicLinkRepository) FindByHash(tokenHash string) (*models.MagicLinkToken, error) {
+	var token models.MagicLinkToken
+	if err := r.db.Where("token_hash = ?", tokenHash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// FindLatest retrieves the most recently issued magic link for an app and email
+func (r *magicLinkRepository) FindLatest(appName, email string) (*models.MagicLinkToken, error) {
+	var token models.MagicLinkToken
+	if err := r.db.Where("app_name = ? AND email = ?", appName, email).Order("created_at DESC").First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// Consume atomically marks an unexpired, unused magic link as consumed
+// Returns false if the link was already used or has expired
+func (r *magicLinkRepository) Consume(id uuid.UUID) (bool, error) {
+	now := time.Now()
+	result := r.db.Model(&models.MagicLinkToken{}).
+		Where("id = ? AND consumed_at IS NULL AND expires_at >= ?", id, now).
+		Update("consumed_at", now)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// DeleteExpired removes magic links that expired before the given time and returns how many were removed
+func (r *magicLinkRepository) DeleteExpired(before time.Time) (int64, error) {
+	result := r.db.Where("expires_at < ?", before).Delete(&models.MagicLinkToken{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"errors"
+
+	"go-backend/internal/apps/otp/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OTPPolicyRepository defines data operations for per-app OTP policies
+type OTPPolicyRepository interface {
+	Create(policy *models.OTPPolicy) error
+	FindByID(id uuid.UUID) (*models.OTPPolicy, error)
+	FindByAppName(appName string) (*models.OTPPolicy, error)
+	FindAll() ([]models.OTPPolicy, error)
+	Update(policy *models.OTPPolicy) error
+	Delete(id uuid.UUID) error
+}
+
+// otpPolicyRepository implements OTPPolicyRepository
+type otpPolicyRepository struct {
+	db *gorm.DB
+}
+
+// NewOTPPolicyRepository creates an instance of OTPPolicyRepository
+func NewOTPPolicyRepository(db *gorm.DB) OTPPolicyRepository {
+	return &otpPolicyRepository{db: db}
+}
+
+// Create creates a new OTP policy
+func (r *otpPolicyRepository) Create(policy *models.OTPPolicy) error {
+	return r.db.Create(policy).Error
+}
+
+// FindByID finds an OTP policy by ID
+func (r *otpPolicyRepository) FindByID(id uuid.UUID) (*models.OTPPolicy, error) {
+	var policy models.OTPPolicy
+	if err := r.db.Where("id = ?", id).First(&policy).Error; err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// FindByAppName finds the OTP policy for an app
+func (r *otpPolicyRepository) FindByAppName(appName string) (*models.OTPPolicy, error) {
+	var policy models.OTPPolicy
+	if err := r.db.Where("app_name = ?", appName).First(&policy).Error; err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// FindAll retrieves all OTP policies ordered by app name
+func (r *otpPolicyRepository) FindAll() ([]models.OTPPolicy, error) {
+	var policies []models.OTPPolicy
+	if err := r.db.Order("app_name ASC").Find(&policies).Error; err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+// Update updates an existing OTP policy
+func (r *otpPolicyRepository) Update(policy *models.OTPPolicy) error {
+	result := r.db.Save(policy)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("otp policy not found")
+	}
+	return nil
+}
+
+// Delete soft deletes an OTP policy
+func (r *otpPolicyRepository) Delete(id uuid.UUID) error {
+	result := r.db.Delete(&models.OTPPolicy{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("otp policy not found")
+	}
+	return nil
+}
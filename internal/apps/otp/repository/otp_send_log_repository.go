@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"time"
+
+	"go-backend/internal/apps/otp/models"
+
+	"gorm.io/gorm"
+)
+
+// OTPSendLogRepository defines data operations for OTP send logs
+type OTPSendLogRepository interface {
+	Create(log *models.OTPSendLog) error
+	CountByRecipientSince(appName, channel, recipient string, since time.Time) (int64, error)
+	CountByIPSince(appName, ipAddress string, since time.Time) (int64, error)
+	DeleteOlderThan(before time.Time) (int64, error)
+}
+
+// otpSendLogRepository implements OTPSendLogRepository
+type otpSendLogRepository struct {
+	db *gorm.DB
+}
+
+// NewOTPSendLogRepository creates an instance of OTPSendLogRepository
+func NewOTPSendLogRepository(db *gorm.DB) OTPSendLogRepository {
+	return &otpSendLogRepository{db: db}
+}
+
+// Create records an OTP issuance
+func (r *otpSendLogRepository) Create(log *models.OTPSendLog) error {
+	return r.db.Create(log).Error
+}
+
+// CountByRecipientSince counts OTPs issued to a recipient on a channel since the given time
+func (r *otpSendLogRepository) CountByRecipientSince(appName, channel, recipient string, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.OTPSendLog{}).
+		Where("app_name = ? AND channel = ? AND recipient = ? AND created_at >= ?", appName, channel, recipient, since).
+		Count(&count).Error
+	return count, err
+}
+
+// CountByIPSince counts OTPs requested from an IP address since the given time
+func (r *otpSendLogRepository) CountByIPSince(appName, ipAddress string, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.OTPSendLog{}).
+		Where("app_name = ? AND ip_address = ? AND created_at >= ?", appName, ipAddress, since).
+		Count(&count).Error
+	return count, err
+}
+
+// DeleteOlderThan removes send logs created before the given time and returns how many were removed
+func (r *otpSendLogRepository) DeleteOlderThan(before time.Time) (int64, error) {
+	result := r.db.Where("created_at < ?", before).Delete(&models.OTPSendLog{})
+	return result.RowsAffected, result.Error
+}
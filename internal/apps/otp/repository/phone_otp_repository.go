@@ -5,6 +5,7 @@ import (
 
 	"go-backend/internal/apps/otp/models"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
@@ -14,6 +15,10 @@ type PhoneOTPRepository interface {
 	Upsert(appName, countryCode, phone, value string, expiresAt time.Time) error
 	FindByPhone(appName, countryCode, phone string) (*models.PhoneOTP, error)
 	Delete(appName, countryCode, phone string) error
+	ReserveAttempt(id uuid.UUID, maxAttempts int) (int, bool, error)
+	Consume(id uuid.UUID, maxAttempts int) (bool, error)
+	UpdateDelivery(appName, countryCode, phone, provider, channel string) error
+	DeleteExpired(before time.Time) (int64, error)
 }
 
 // phoneOTPRepository implements PhoneOTPRepository
@@ -37,7 +42,7 @@ func (r *phoneOTPRepository) Upsert(appName, countryCode, phone, value string, e
 	}
 	return r.db.Clauses(clause.OnConflict{
 		Columns:   []clause.Column{{Name: "app_name"}, {Name: "country_code"}, {Name: "phone"}},
-		DoUpdates: clause.AssignmentColumns([]string{"value", "expires_at", "updated_at"}),
+		DoUpdates: clause.AssignmentColumns([]string{"value", "attempts", "provider", "channel", "expires_at", "updated_at"}),
 	}).Create(&otp).Error
 }
 
@@ -54,3 +59,38 @@ func (r *phoneOTPRepository) FindByPhone(appName, countryCode, phone string) (*m
 func (r *phoneOTPRepository) Delete(appName, countryCode, phone string) error {
 	return r.db.Where("app_name = ? AND country_code = ? AND phone = ?", appName, countryCode, phone).Delete(&models.PhoneOTP{}).Error
 }
+
+// ReserveAttempt atomically records a verification attempt on an unexpired OTP with attempts left
+// and returns the new attempt count; returns false when no attempt could be reserved
+func (r *phoneOTPRepository) ReserveAttempt(id uuid.UUID, maxAttempts int) (int, bool, error) {
+	var attempts []int
+	err := r.db.Raw("UPDATE phone_otp SET attempts = attempts + 1 WHERE id = ? AND attempts < ? AND expires_at >= ? RETURNING attempts",
+		id, maxAttempts, time.Now()).Scan(&attempts).Error
+	if err != nil || len(attempts) == 0 {
+		return 0, false, err
+	}
+	return attempts[0], true, nil
+}
+
+// Consume atomically deletes an unexpired OTP by ID that has not used more than maxAttempts attempts
+// Returns false if the OTP was already used, expired, replaced or exhausted
+func (r *phoneOTPRepository) Consume(id uuid.UUID, maxAttempts int) (bool, error) {
+	result := r.db.Where("id = ? AND attempts <= ? AND expires_at >= ?", id, maxAttempts, time.Now()).Delete(&models.PhoneOTP{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// UpdateDelivery records which provider and channel delivered the current OTP for a phone number
+func (r *phoneOTPRepository) UpdateDelivery(appName, countryCode, phone, provider, channel string) error {
+	return r.db.Model(&models.PhoneOTP{}).
+		Where("app_name = ? AND country_code = ? AND phone = ?", appName, countryCode, phone).
+		Updates(map[string]interface{}{"provider": provider, "channel": channel}).Error
+}
+
+// DeleteExpired removes OTPs that expired before the given time and returns how many were removed
+func (r *phoneOTPRepository) DeleteExpired(before time.Time) (int64, error) {
+	result := r.db.Where("expires_at < ?", before).Delete(&models.PhoneOTP{})
+	return result.RowsAffected, result.Error
+}
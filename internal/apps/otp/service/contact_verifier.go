@@ -0,0 +1,23 @@
+package service
+
+// ContactVerifier confirms a user's new phone number or email with the OTP sent to it
+// It consumes the OTP without starting a session
+type ContactVerifier struct {
+	phone PhoneOTPService
+	email EmailOTPService
+}
+
+// NewContactVerifier creates a new instance of ContactVerifier
+func NewContactVerifier(phone PhoneOTPService, email EmailOTPService) *ContactVerifier {
+	return &ContactVerifier{phone: phone, email: email}
+}
+
+// VerifyPhone consumes the OTP sent to a phone number
+func (v *ContactVerifier) VerifyPhone(appName, countryCode, phone, code string) error {
+	return v.phone.ConsumeOTP(appName, countryCode, phone, code)
+}
+
+// VerifyEmail consumes the OTP sent to an email address
+func (v *ContactVerifier) VerifyEmail(appName, email, code string) error {
+	return v.email.ConsumeOTP(appName, email, code)
+}
@@ -5,52 +5,157 @@ import (
 	"fmt"
 	"time"
 
+	authService "go-backend/internal/apps/auth/service"
 	"go-backend/internal/apps/otp/models"
 	"go-backend/internal/apps/otp/repository"
+	"go-backend/pkg/identifier"
 
 	"gorm.io/gorm"
 )
 
 // EmailOTPService defines business logic for Email OTP
 type EmailOTPService interface {
-	CreateOrUpdateOTP(req models.CreateEmailOTPRequest) (*models.EmailOTPResponse, error)
+	CreateOrUpdateOTP(req models.CreateEmailOTPRequest, clientIP string) (*models.EmailOTPResponse, error)
 	VerifyOTP(req models.VerifyEmailOTPRequest) (*models.VerifyEmailOTPResponse, error)
+	ConsumeOTP(appName, email, value string) error
 }
 
 // emailOTPService implements EmailOTPService
 type emailOTPService struct {
-	repo repository.EmailOTPRepository
+	repo        repository.EmailOTPRepository
+	throttle    *otpThrottle
+	policies    PolicyResolver
+	provider    EmailProvider
+	templates   EmailTemplates
+	authService authService.AuthService
 }
 
 // NewEmailOTPService creates a new instance of EmailOTPService
-func NewEmailOTPService(repo repository.EmailOTPRepository) EmailOTPService {
+func NewEmailOTPService(
+	repo repository.EmailOTPRepository,
+	sendLogRepo repository.OTPSendLogRepository,
+	policies PolicyResolver,
+	provider EmailProvider,
+	templates EmailTemplates,
+	authSvc authService.AuthService,
+) EmailOTPService {
 	return &emailOTPService{
-		repo: repo,
+		repo:        repo,
+		throttle:    &otpThrottle{sendLogRepo: sendLogRepo},
+		policies:    policies,
+		provider:    provider,
+		templates:   templates,
+		authService: authSvc,
 	}
 }
 
-// CreateOrUpdateOTP creates or overrides OTP for an email and sets expiry to 10 minutes from now
-func (s *emailOTPService) CreateOrUpdateOTP(req models.CreateEmailOTPRequest) (*models.EmailOTPResponse, error) {
-	// Generate random 4-digit OTP
-	otpValue := generateOTP()
-	expiresAt := time.Now().Add(10 * time.Minute)
+// CreateOrUpdateOTP creates or overrides OTP for an email and sets expiry from the app's OTP policy
+// Requests are throttled per recipient and per client IP according to the app's OTP policy
+func (s *emailOTPService) CreateOrUpdateOTP(req models.CreateEmailOTPRequest, clientIP string) (*models.EmailOTPResponse, error) {
+	policy, err := s.policies.PolicyFor(req.AppName)
+	if err != nil {
+		return nil, err
+	}
+	if !policy.AllowsChannel(models.PolicyChannelEmail) {
+		return nil, ErrOTPChannelNotAllowed
+	}
+
+	// OTPs, attempts and send caps are keyed on the normalized address so every way of writing it shares them
+	email, err := identifier.NormalizeEmail(req.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastSentAt *time.Time
+	existing, err := s.repo.FindByEmail(req.AppName, email)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if existing != nil {
+		lastSentAt = &existing.UpdatedAt
+	}
 
-	if err := s.repo.Upsert(req.AppName, req.Email, otpValue, expiresAt); err != nil {
+	if err := s.throttle.checkSend(policy, req.AppName, models.OTPChannelEmail, email, clientIP, lastSentAt); err != nil {
 		return nil, err
 	}
 
-	// TODO: Send OTP via email provider
-	// When email provider is implemented, fail if sending fails
-	fmt.Printf("[Email OTP Service] OTP created for %s\n", req.Email)
+	otpValue, err := generateOTP(policy.Length)
+	if err != nil {
+		return nil, err
+	}
+	otpHash, err := hashOTP(req.AppName, email, otpValue)
+	if err != nil {
+		return nil, err
+	}
+	expiresAt := time.Now().Add(policy.TTL())
+
+	if err := s.repo.Upsert(req.AppName, email, otpHash, expiresAt); err != nil {
+		return nil, err
+	}
+
+	if err := s.throttle.recordSend(req.AppName, models.OTPChannelEmail, email, clientIP); err != nil {
+		return nil, err
+	}
+
+	msg, err := s.templates.RenderOTPEmail(req.Email, EmailTemplateData{
+		AppName:          req.AppName,
+		SenderName:       policy.SenderNameFor(req.AppName),
+		OTP:              otpValue,
+		ExpiresInMinutes: int(policy.TTL().Minutes()),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Send OTP via email provider - fail if sending fails
+	if err := s.provider.SendEmail(msg); err != nil {
+		return nil, fmt.Errorf("failed to send OTP: %w", err)
+	}
 
 	return &models.EmailOTPResponse{
 		ExpiresAt: expiresAt,
 	}, nil
 }
 
-// VerifyOTP verifies provided OTP value and expiry
+// VerifyOTP verifies an OTP and, when it is valid, starts a session for its user
 func (s *emailOTPService) VerifyOTP(req models.VerifyEmailOTPRequest) (*models.VerifyEmailOTPResponse, error) {
-	otp, err := s.repo.FindByEmail(req.AppName, req.Email)
+	resp, err := s.checkOTP(req)
+	if err != nil || !resp.Valid {
+		return resp, err
+	}
+
+	// Link the verified email to its user and start a session (or an MFA challenge)
+	login, err := s.authService.LoginWithEmail(req.AppName, req.Email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+	resp.Session = login.Session
+	resp.MFA = login.MFA
+
+	return resp, nil
+}
+
+// ConsumeOTP verifies and consumes an OTP without starting a session, e.g. to confirm a user's new email address
+func (s *emailOTPService) ConsumeOTP(appName, email, value string) error {
+	resp, err := s.checkOTP(models.VerifyEmailOTPRequest{AppName: appName, Email: email, Value: value})
+	if err != nil {
+		return err
+	}
+	if !resp.Valid {
+		return ErrOTPInvalid
+	}
+	return nil
+}
+
+// checkOTP verifies provided OTP value and expiry and consumes the OTP on success
+// Each attempt is reserved before the comparison, and the OTP is invalidated once the app's maximum number of attempts is used
+func (s *emailOTPService) checkOTP(req models.VerifyEmailOTPRequest) (*models.VerifyEmailOTPResponse, error) {
+	email, err := identifier.NormalizeEmail(req.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	otp, err := s.repo.FindByEmail(req.AppName, email)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("otp not found")
@@ -58,20 +163,68 @@ func (s *emailOTPService) VerifyOTP(req models.VerifyEmailOTPRequest) (*models.V
 		return nil, err
 	}
 
+	policy, err := s.policies.PolicyFor(req.AppName)
+	if err != nil {
+		return nil, err
+	}
+	if otp.Attempts >= policy.MaxAttempts {
+		if err := s.repo.Delete(req.AppName, email); err != nil {
+			return nil, err
+		}
+		return nil, ErrOTPAttemptsExceeded
+	}
+
 	now := time.Now()
-	valid := (req.Value == otp.Value) && (now.Before(otp.ExpiresAt) || now.Equal(otp.ExpiresAt))
-
-	message := "OTP verified successfully"
-	if !valid {
-		if req.Value != otp.Value {
-			message = "Invalid OTP"
-		} else {
-			message = "OTP expired"
+	if now.After(otp.ExpiresAt) {
+		return &models.VerifyEmailOTPResponse{
+			Valid:   false,
+			Message: "OTP expired",
+		}, nil
+	}
+
+	// Reserve the attempt before comparing so parallel guesses cannot exceed the limit
+	attempts, reserved, err := s.repo.ReserveAttempt(otp.ID, policy.MaxAttempts)
+	if err != nil {
+		return nil, err
+	}
+	if !reserved {
+		if err := s.repo.Delete(req.AppName, email); err != nil {
+			return nil, err
 		}
+		return nil, ErrOTPAttemptsExceeded
+	}
+
+	matches, err := otpMatches(req.AppName, email, req.Value, otp.Value)
+	if err != nil {
+		return nil, err
+	}
+	if !matches {
+		if attempts >= policy.MaxAttempts {
+			if err := s.repo.Delete(req.AppName, email); err != nil {
+				return nil, err
+			}
+			return nil, ErrOTPAttemptsExceeded
+		}
+
+		remaining := policy.MaxAttempts - attempts
+		return &models.VerifyEmailOTPResponse{
+			Valid:             false,
+			Message:           "Invalid OTP",
+			AttemptsRemaining: &remaining,
+		}, nil
+	}
+
+	// Consume the OTP so it cannot be verified twice
+	consumed, err := s.repo.Consume(otp.ID, policy.MaxAttempts)
+	if err != nil {
+		return nil, err
+	}
+	if !consumed {
+		return nil, errors.New("otp not found")
 	}
 
 	return &models.VerifyEmailOTPResponse{
-		Valid:   valid,
-		Message: message,
+		Valid:   true,
+		Message: "OTP verified successfully",
 	}, nil
 }
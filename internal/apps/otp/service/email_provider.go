@@ -0,0 +1,196 @@
+package service
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+// EmailMessage is a rendered email ready to be delivered
+type EmailMessage struct {
+	To       string
+	AppName  string
+	Subject  string
+	HTMLBody string
+	TextBody string
+}
+
+// EmailProvider defines the interface for sending OTP emails
+type EmailProvider interface {
+	SendEmail(msg EmailMessage) error
+}
+
+// noOpEmailProvider skips email sending (for local and dev environments)
+type noOpEmailProvider struct {
+	logPlaintext bool
+}
+
+func (n *noOpEmailProvider) SendEmail(msg EmailMessage) error {
+	if n.logPlaintext {
+		fmt.Printf("[Email NoOp] Skipping email to %s, App: %s, Subject: %s\n%s\n", msg.To, msg.AppName, msg.Subject, msg.TextBody)
+		return nil
+	}
+	fmt.Printf("[Email NoOp] Skipping email to %s, App: %s\n", msg.To, msg.AppName)
+	return nil
+}
+
+// NewNoOpEmailProvider creates a no-op email provider
+// The message body (and so the OTP) is only logged when logPlaintext is set, which should be limited to local development
+func NewNoOpEmailProvider(logPlaintext bool) EmailProvider {
+	return &noOpEmailProvider{logPlaintext: logPlaintext}
+}
+
+// SMTPConfig holds the settings for the SMTP email provider
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string // Optional; authentication is skipped when empty
+	Password string
+	From     string // Sender address, optionally with a display name ("Acme <no-reply@acme.com>")
+	Timeout  time.Duration
+}
+
+// smtpEmailProvider sends OTP emails through an SMTP server
+// STARTTLS is used whenever the server advertises it
+type smtpEmailProvider struct {
+	config SMTPConfig
+	from   *mail.Address
+}
+
+// NewSMTPEmailProvider creates an SMTP email provider
+func NewSMTPEmailProvider(config SMTPConfig) (EmailProvider, error) {
+	if config.Host == "" {
+		return nil, errors.New("smtp host is required")
+	}
+	if config.Port == 0 {
+		config.Port = 587
+	}
+	if config.Timeout == 0 {
+		config.Timeout = 10 * time.Second
+	}
+
+	from, err := mail.ParseAddress(config.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid smtp from address: %w", err)
+	}
+
+	return &smtpEmailProvider{config: config, from: from}, nil
+}
+
+func (p *smtpEmailProvider) SendEmail(msg EmailMessage) error {
+	to, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return fmt.Errorf("invalid recipient address: %w", err)
+	}
+
+	body, err := buildMIMEMessage(p.from, to, msg)
+	if err != nil {
+		return err
+	}
+
+	addr := net.JoinHostPort(p.config.Host, strconv.Itoa(p.config.Port))
+	conn, err := net.DialTimeout("tcp", addr, p.config.Timeout)
+	if err != nil {
+		return fmt.Errorf("failed to connect to smtp server: %w", err)
+	}
+	if err := conn.SetDeadline(time.Now().Add(p.config.Timeout)); err != nil {
+		conn.Close()
+		return err
+	}
+
+	client, err := smtp.NewClient(conn, p.config.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to start smtp session: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: p.config.Host}); err != nil {
+			return fmt.Errorf("smtp starttls failed: %w", err)
+		}
+	}
+
+	if p.config.Username != "" {
+		auth := smtp.PlainAuth("", p.config.Username, p.config.Password, p.config.Host)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("smtp authentication failed: %w", err)
+		}
+	}
+
+	if err := client.Mail(p.from.Address); err != nil {
+		return fmt.Errorf("smtp MAIL FROM failed: %w", err)
+	}
+	if err := client.Rcpt(to.Address); err != nil {
+		return fmt.Errorf("smtp RCPT TO failed: %w", err)
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp DATA failed: %w", err)
+	}
+	if _, err := w.Write(body); err != nil {
+		w.Close()
+		return fmt.Errorf("failed to write email body: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp server rejected message: %w", err)
+	}
+
+	fmt.Printf("[Email SMTP] Sent email to %s, App: %s\n", to.Address, msg.AppName)
+	return client.Quit()
+}
+
+// buildMIMEMessage encodes msg as a multipart/alternative email with text and HTML parts
+func buildMIMEMessage(from, to *mail.Address, msg EmailMessage) ([]byte, error) {
+	boundaryBytes := make([]byte, 12)
+	if _, err := rand.Read(boundaryBytes); err != nil {
+		return nil, err
+	}
+	boundary := "otp-" + hex.EncodeToString(boundaryBytes)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from.String())
+	fmt.Fprintf(&buf, "To: %s\r\n", to.String())
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", boundary)
+
+	parts := []struct {
+		contentType string
+		body        string
+	}{
+		{"text/plain", msg.TextBody},
+		{"text/html", msg.HTMLBody},
+	}
+	for _, part := range parts {
+		if part.body == "" {
+			continue
+		}
+		fmt.Fprintf(&buf, "--%s\r\n", boundary)
+		fmt.Fprintf(&buf, "Content-Type: %s; charset=utf-8\r\n", part.contentType)
+		buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+		qp := quotedprintable.NewWriter(&buf)
+		if _, err := qp.Write([]byte(part.body)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+		buf.WriteString("\r\n")
+	}
+	fmt.Fprintf(&buf, "--%s--\r\n", boundary)
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	texttemplate "text/template"
+)
+
+// Email template kinds; each kind uses <kind>_subject.txt, <kind>_body.txt and <kind>_body.html
+const (
+	emailKindOTP       = "otp"
+	emailKindMagicLink = "magic_link"
+)
+
+var emailKinds = []string{emailKindOTP, emailKindMagicLink}
+
+//go:embed templates/default/*
+var defaultEmailTemplates embed.FS
+
+// EmailTemplateData is the data available to email templates
+type EmailTemplateData struct {
+	AppName          string
+	SenderName       string // Display name from the app's OTP policy, defaults to AppName
+	OTP              string // Set for OTP emails
+	Link             string // Set for magic link emails
+	ExpiresInMinutes int
+}
+
+// EmailTemplates renders login emails with per-app templates
+type EmailTemplates interface {
+	RenderOTPEmail(to string, data EmailTemplateData) (EmailMessage, error)
+	RenderMagicLinkEmail(to string, data EmailTemplateData) (EmailMessage, error)
+}
+
+// emailTemplateSet holds the parsed templates for one kind of email
+type emailTemplateSet struct {
+	subject *texttemplate.Template
+	text    *texttemplate.Template
+	html    *htmltemplate.Template
+}
+
+// emailTemplates implements EmailTemplates
+type emailTemplates struct {
+	defaults map[string]emailTemplateSet            // Keyed by kind
+	apps     map[string]map[string]emailTemplateSet // Keyed by app name, then kind
+}
+
+// NewEmailTemplates loads the built-in templates and, if dir is set, per-app overrides from dir/<app_name>/
+// Each app directory may override any template file; missing files use the defaults
+func NewEmailTemplates(dir string) (EmailTemplates, error) {
+	defaultsFS, err := fs.Sub(defaultEmailTemplates, "templates/default")
+	if err != nil {
+		return nil, err
+	}
+
+	t := &emailTemplates{
+		defaults: make(map[string]emailTemplateSet),
+		apps:     make(map[string]map[string]emailTemplateSet),
+	}
+	for _, kind := range emailKinds {
+		set, err := parseEmailTemplateSet(defaultsFS, "default", kind, emailTemplateSet{})
+		if err != nil {
+			return nil, err
+		}
+		t.defaults[kind] = set
+	}
+	if dir == "" {
+		return t, nil
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read email template directory: %w", err)
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		appName := entry.Name()
+		appFS := os.DirFS(filepath.Join(dir, appName))
+		t.apps[appName] = make(map[string]emailTemplateSet)
+		for _, kind := range emailKinds {
+			set, err := parseEmailTemplateSet(appFS, appName, kind, t.defaults[kind])
+			if err != nil {
+				return nil, err
+			}
+			t.apps[appName][kind] = set
+		}
+	}
+	return t, nil
+}
+
+// parseEmailTemplateSet parses one kind's templates in fsys, falling back to the given set for missing files
+func parseEmailTemplateSet(fsys fs.FS, name, kind string, fallback emailTemplateSet) (emailTemplateSet, error) {
+	set := fallback
+	subjectFile := kind + "_subject.txt"
+	textFile := kind + "_body.txt"
+	htmlFile := kind + "_body.html"
+
+	if content, ok, err := readTemplateFile(fsys, subjectFile); err != nil {
+		return set, err
+	} else if ok {
+		tmpl, err := texttemplate.New(name + "/" + subjectFile).Parse(strings.TrimSpace(content))
+		if err != nil {
+			return set, fmt.Errorf("invalid email template %s/%s: %w", name, subjectFile, err)
+		}
+		set.subject = tmpl
+	}
+
+	if content, ok, err := readTemplateFile(fsys, textFile); err != nil {
+		return set, err
+	} else if ok {
+		tmpl, err := texttemplate.New(name + "/" + textFile).Parse(content)
+		if err != nil {
+			return set, fmt.Errorf("invalid email template %s/%s: %w", name, textFile, err)
+		}
+		set.text = tmpl
+	}
+
+	if content, ok, err := readTemplateFile(fsys, htmlFile); err != nil {
+		return set, err
+	} else if ok {
+		tmpl, err := htmltemplate.New(name + "/" + htmlFile).Parse(content)
+		if err != nil {
+			return set, fmt.Errorf("invalid email template %s/%s: %w", name, htmlFile, err)
+		}
+		set.html = tmpl
+	}
+
+	if set.subject == nil || set.text == nil || set.html == nil {
+		return set, fmt.Errorf("%s email templates for %s are incomplete", kind, name)
+	}
+	return set, nil
+}
+
+// readTemplateFile reads a template file, reporting false if it does not exist
+func readTemplateFile(fsys fs.FS, name string) (string, bool, error) {
+	content, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+	return string(content), true, nil
+}
+
+// RenderOTPEmail renders the OTP email for data.AppName, using the defaults if the app has no templates
+func (t *emailTemplates) RenderOTPEmail(to string, data EmailTemplateData) (EmailMessage, error) {
+	return t.render(emailKindOTP, to, data)
+}
+
+// RenderMagicLinkEmail renders the magic link email for data.AppName, using the defaults if the app has no templates
+func (t *emailTemplates) RenderMagicLinkEmail(to string, data EmailTemplateData) (EmailMessage, error) {
+	return t.render(emailKindMagicLink, to, data)
+}
+
+// render executes the app's templates of the given kind
+func (t *emailTemplates) render(kind, to string, data EmailTemplateData) (EmailMessage, error) {
+	set, ok := t.apps[data.AppName][kind]
+	if !ok {
+		set = t.defaults[kind]
+	}
+
+	var subject, text, html bytes.Buffer
+	if err := set.subject.Execute(&subject, data); err != nil {
+		return EmailMessage{}, fmt.Errorf("failed to render email subject: %w", err)
+	}
+	if err := set.text.Execute(&text, data); err != nil {
+		return EmailMessage{}, fmt.Errorf("failed to render email text body: %w", err)
+	}
+	if err := set.html.Execute(&html, data); err != nil {
+		return EmailMessage{}, fmt.Errorf("failed to render email html body: %w", err)
+	}
+
+	return EmailMessage{
+		To:       to,
+		AppName:  data.AppName,
+		Subject:  subject.String(),
+		HTMLBody: html.String(),
+		TextBody: text.String(),
+	}, nil
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	authService "go-backend/internal/apps/auth/service"
+	"go-backend/internal/apps/otp/models"
+	"go-backend/internal/apps/otp/repository"
+	"go-backend/pkg/identifier"
+	"go-backend/pkg/secure"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// tokenTypeMagicLink distinguishes magic link tokens from other tokens signed with the same secret
+const tokenTypeMagicLink = "magic_link"
+
+// ErrRedirectURLNotAllowed is returned when a magic link targets a URL not whitelisted for the app
+var ErrRedirectURLNotAllowed = errors.New("redirect_url is not allowed for this app")
+
+// magicLinkClaims are the signed claims carried by a magic link token
+type magicLinkClaims struct {
+	ID        string `json:"jti"`
+	AppName   string `json:"app"`
+	Type      string `json:"typ"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// MagicLinkService defines business logic for magic link email login
+type MagicLinkService interface {
+	CreateMagicLink(req models.CreateMagicLinkRequest, clientIP string) (*models.MagicLinkResponse, error)
+	VerifyMagicLink(token string) (*models.VerifyEmailOTPResponse, error)
+}
+
+// magicLinkService implements MagicLinkService
+type magicLinkService struct {
+	repo        repository.MagicLinkRepository
+	throttle    *otpThrottle
+	policies    PolicyResolver
+	provider    EmailProvider
+	templates   EmailTemplates
+	authService authService.AuthService
+}
+
+// NewMagicLinkService creates a new instance of MagicLinkService
+func NewMagicLinkService(
+	repo repository.MagicLinkRepository,
+	sendLogRepo repository.OTPSendLogRepository,
+	policies PolicyResolver,
+	provider EmailProvider,
+	templates EmailTemplates,
+	authSvc authService.AuthService,
+) MagicLinkService {
+	return &magicLinkService{
+		repo:        repo,
+		throttle:    &otpThrottle{sendLogRepo: sendLogRepo},
+		policies:    policies,
+		provider:    provider,
+		templates:   templates,
+		authService: authSvc,
+	}
+}
+
+// CreateMagicLink issues a single-use signed login link and emails it
+// Magic links share the email channel's policy, cooldown and hourly caps with email OTPs
+func (s *magicLinkService) CreateMagicLink(req models.CreateMagicLinkRequest, clientIP string) (*models.MagicLinkResponse, error) {
+	policy, err := s.policies.PolicyFor(req.AppName)
+	if err != nil {
+		return nil, err
+	}
+	if !policy.AllowsChannel(models.PolicyChannelEmail) {
+		return nil, ErrOTPChannelNotAllowed
+	}
+	if !policy.AllowsRedirectURL(req.RedirectURL) {
+		return nil, ErrRedirectURLNotAllowed
+	}
+
+	// Links and send caps are keyed on the normalized address so every way of writing it shares them
+	email, err := identifier.NormalizeEmail(req.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastSentAt *time.Time
+	latest, err := s.repo.FindLatest(req.AppName, email)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if latest != nil {
+		lastSentAt = &latest.CreatedAt
+	}
+
+	if err := s.throttle.checkSend(policy, req.AppName, models.OTPChannelEmail, email, clientIP, lastSentAt); err != nil {
+		return nil, err
+	}
+
+	id := uuid.New()
+	expiresAt := time.Now().Add(policy.TTL())
+	token, err := secure.SignToken(magicLinkClaims{
+		ID:        id.String(),
+		AppName:   req.AppName,
+		Type:      tokenTypeMagicLink,
+		ExpiresAt: expiresAt.Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	link, err := appendToken(req.RedirectURL, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(&models.MagicLinkToken{
+		ID:          id,
+		AppName:     req.AppName,
+		Email:       email,
+		TokenHash:   secure.HashToken(token),
+		RedirectURL: req.RedirectURL,
+		ExpiresAt:   expiresAt,
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := s.throttle.recordSend(req.AppName, models.OTPChannelEmail, email, clientIP); err != nil {
+		return nil, err
+	}
+
+	msg, err := s.templates.RenderMagicLinkEmail(req.Email, EmailTemplateData{
+		AppName:          req.AppName,
+		SenderName:       policy.SenderNameFor(req.AppName),
+		Link:             link,
+		ExpiresInMinutes: int(policy.TTL().Minutes()),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Send magic link via email provider - fail if sending fails
+	if err := s.provider.SendEmail(msg); err != nil {
+		return nil, fmt.Errorf("failed to send magic link: %w", err)
+	}
+
+	return &models.MagicLinkResponse{
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// VerifyMagicLink redeems a magic link token and starts a session for its email
+func (s *magicLinkService) VerifyMagicLink(token string) (*models.VerifyEmailOTPResponse, error) {
+	var claims magicLinkClaims
+	if err := secure.VerifyToken(token, &claims); err != nil || claims.Type != tokenTypeMagicLink {
+		return &models.VerifyEmailOTPResponse{
+			Valid:   false,
+			Message: "Invalid magic link",
+		}, nil
+	}
+
+	link, err := s.repo.FindByHash(secure.HashToken(token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("magic link not found")
+		}
+		return nil, err
+	}
+
+	if time.Now().After(link.ExpiresAt) {
+		return &models.VerifyEmailOTPResponse{
+			Valid:   false,
+			Message: "Magic link expired",
+		}, nil
+	}
+
+	// Consume the link so it cannot be used twice
+	consumed, err := s.repo.Consume(link.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !consumed {
+		return nil, errors.New("magic link not found")
+	}
+
+	resp := &models.VerifyEmailOTPResponse{
+		Valid:       true,
+		Message:     "Magic link verified successfully",
+		RedirectURL: link.RedirectURL,
+	}
+
+	// Link the verified email to its user and start a session (or an MFA challenge)
+	login, err := s.authService.LoginWithEmail(link.AppName, link.Email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+	resp.Session = login.Session
+	resp.MFA = login.MFA
+
+	return resp, nil
+}
+
+// appendToken adds the token to the redirect URL's query string
+func appendToken(redirectURL, token string) (string, error) {
+	u, err := url.Parse(redirectURL)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	query.Set("token", token)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"go-backend/internal/apps/otp/repository"
+)
+
+// sendLogRetention keeps send logs long enough for the hourly throttles (and some debugging headroom)
+const sendLogRetention = 24 * time.Hour
+
+// OTPCleanupService removes expired OTP rows
+type OTPCleanupService interface {
+	PurgeExpired() (int64, error)
+}
+
+// otpCleanupService implements OTPCleanupService
+type otpCleanupService struct {
+	phoneRepo     repository.PhoneOTPRepository
+	emailRepo     repository.EmailOTPRepository
+	sendLogRepo   repository.OTPSendLogRepository
+	magicLinkRepo repository.MagicLinkRepository
+}
+
+// NewOTPCleanupService creates a new instance of OTPCleanupService
+func NewOTPCleanupService(
+	phoneRepo repository.PhoneOTPRepository,
+	emailRepo repository.EmailOTPRepository,
+	sendLogRepo repository.OTPSendLogRepository,
+	magicLinkRepo repository.MagicLinkRepository,
+) OTPCleanupService {
+	return &otpCleanupService{
+		phoneRepo:     phoneRepo,
+		emailRepo:     emailRepo,
+		sendLogRepo:   sendLogRepo,
+		magicLinkRepo: magicLinkRepo,
+	}
+}
+
+// PurgeExpired deletes expired phone/email OTPs and magic links, and send logs past their retention
+// Returns the total number of rows removed
+func (s *otpCleanupService) PurgeExpired() (int64, error) {
+	now := time.Now()
+	var total int64
+
+	phone, err := s.phoneRepo.DeleteExpired(now)
+	if err != nil {
+		return total, fmt.Errorf("failed to purge phone OTPs: %w", err)
+	}
+	total += phone
+
+	email, err := s.emailRepo.DeleteExpired(now)
+	if err != nil {
+		return total, fmt.Errorf("failed to purge email OTPs: %w", err)
+	}
+	total += email
+
+	links, err := s.magicLinkRepo.DeleteExpired(now)
+	if err != nil {
+		return total, fmt.Errorf("failed to purge magic links: %w", err)
+	}
+	total += links
+
+	logs, err := s.sendLogRepo.DeleteOlderThan(now.Add(-sendLogRetention))
+	if err != nil {
+		return total, fmt.Errorf("failed to purge OTP send logs: %w", err)
+	}
+	total += logs
+
+	fmt.Printf("[OTPCleanup] Purged %d phone OTPs, %d email OTPs, %d magic links, %d send logs\n", phone, email, links, logs)
+	return total, nil
+}
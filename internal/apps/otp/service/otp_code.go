@@ -0,0 +1,40 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"go-backend/pkg/secure"
+)
+
+// Supported OTP lengths
+const (
+	MinOTPLength = 4
+	MaxOTPLength = 8
+)
+
+// ErrOTPInvalid is returned when an OTP consumed outside of login is wrong or expired
+var ErrOTPInvalid = errors.New("invalid otp")
+
+// generateOTP generates a random numeric OTP of the given length using a CSPRNG
+func generateOTP(length int) (string, error) {
+	if length < MinOTPLength || length > MaxOTPLength {
+		return "", fmt.Errorf("unsupported otp length %d", length)
+	}
+	return secure.RandomDigits(length)
+}
+
+// hashOTP returns the keyed hash stored in place of the OTP value
+// The hash is bound to the app and recipient so a stored value cannot be replayed for another recipient
+func hashOTP(appName, recipient, value string) (string, error) {
+	return secure.KeyedHash("otp", appName, recipient, value)
+}
+
+// otpMatches compares a submitted OTP against the stored hash in constant time
+func otpMatches(appName, recipient, value, storedHash string) (bool, error) {
+	hash, err := hashOTP(appName, recipient, value)
+	if err != nil {
+		return false, err
+	}
+	return secure.EqualHash(hash, storedHash), nil
+}
@@ -0,0 +1,263 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"go-backend/internal/apps/otp/models"
+	"go-backend/internal/apps/otp/repository"
+
+	"gorm.io/gorm"
+)
+
+// OTPPolicy holds the per-app settings applied when issuing and verifying OTPs
+type OTPPolicy struct {
+	TTLSeconds            int      `json:"ttl_seconds"`             // How long an OTP stays valid
+	Length                int      `json:"length"`                  // Number of digits in generated OTPs (4-8)
+	MaxAttempts           int      `json:"max_attempts"`            // Failed verifications before the OTP is invalidated
+	ResendCooldownSeconds int      `json:"resend_cooldown_seconds"` // Minimum gap between two OTPs for the same recipient
+	HourlyRecipientCap    int      `json:"hourly_recipient_cap"`    // OTPs per recipient per hour
+	HourlyIPCap           int      `json:"hourly_ip_cap"`           // OTPs requested per IP address per hour
+	AllowedChannels       []string `json:"allowed_channels"`        // sms, whatsapp, voice, email; empty allows all
+	AllowedCountryCodes   []string `json:"allowed_country_codes"`   // e.g. "+91"; empty allows all
+	AllowedRedirectURLs   []string `json:"allowed_redirect_urls"`   // Magic link targets; empty disables magic links
+	SenderName            string   `json:"sender_name"`             // Name shown to recipients; defaults to the app name
+	TemplateID            string   `json:"template_id"`             // Provider template override; empty uses the provider default
+}
+
+// TTL returns the OTP lifetime as a duration
+func (p OTPPolicy) TTL() time.Duration {
+	return time.Duration(p.TTLSeconds) * time.Second
+}
+
+// ResendCooldown returns the resend cooldown as a duration
+func (p OTPPolicy) ResendCooldown() time.Duration {
+	return time.Duration(p.ResendCooldownSeconds) * time.Second
+}
+
+// AllowsChannel reports whether OTPs may be delivered on the channel
+func (p OTPPolicy) AllowsChannel(channel string) bool {
+	return len(p.AllowedChannels) == 0 || containsString(p.AllowedChannels, channel)
+}
+
+// AllowsCountryCode reports whether OTPs may be sent to numbers with the country code
+func (p OTPPolicy) AllowsCountryCode(countryCode string) bool {
+	return len(p.AllowedCountryCodes) == 0 || containsString(p.AllowedCountryCodes, countryCode)
+}
+
+// AllowsRedirectURL reports whether rawURL matches a whitelisted redirect URL
+// Scheme, host and path must match exactly; the query string and fragment are ignored
+func (p OTPPolicy) AllowsRedirectURL(rawURL string) bool {
+	target, ok := normalizeRedirectURL(rawURL)
+	if !ok {
+		return false
+	}
+	for _, allowed := range p.AllowedRedirectURLs {
+		if normalized, ok := normalizeRedirectURL(allowed); ok && normalized == target {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeRedirectURL reduces an absolute URL to lower-cased scheme://host/path
+func normalizeRedirectURL(rawURL string) (string, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" || u.User != nil || (u.Scheme != "https" && u.Scheme != "http") {
+		return "", false
+	}
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	return strings.ToLower(u.Scheme) + "://" + strings.ToLower(u.Host) + path, true
+}
+
+// SenderNameFor returns the sender display name, falling back to the app name
+func (p OTPPolicy) SenderNameFor(appName string) string {
+	if p.SenderName != "" {
+		return p.SenderName
+	}
+	return appName
+}
+
+// DefaultOTPPolicy returns the settings used when an app has no explicit policy
+func DefaultOTPPolicy() OTPPolicy {
+	return OTPPolicy{
+		TTLSeconds:            600,
+		Length:                4,
+		MaxAttempts:           5,
+		ResendCooldownSeconds: 60,
+		HourlyRecipientCap:    5,
+		HourlyIPCap:           20,
+	}
+}
+
+// withDefaults fills unset (zero or empty) fields from the given defaults
+func (p OTPPolicy) withDefaults(defaults OTPPolicy) OTPPolicy {
+	if p.TTLSeconds <= 0 {
+		p.TTLSeconds = defaults.TTLSeconds
+	}
+	if p.Length <= 0 {
+		p.Length = defaults.Length
+	}
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = defaults.MaxAttempts
+	}
+	if p.ResendCooldownSeconds <= 0 {
+		p.ResendCooldownSeconds = defaults.ResendCooldownSeconds
+	}
+	if p.HourlyRecipientCap <= 0 {
+		p.HourlyRecipientCap = defaults.HourlyRecipientCap
+	}
+	if p.HourlyIPCap <= 0 {
+		p.HourlyIPCap = defaults.HourlyIPCap
+	}
+	if len(p.AllowedChannels) == 0 {
+		p.AllowedChannels = defaults.AllowedChannels
+	}
+	if len(p.AllowedCountryCodes) == 0 {
+		p.AllowedCountryCodes = defaults.AllowedCountryCodes
+	}
+	if len(p.AllowedRedirectURLs) == 0 {
+		p.AllowedRedirectURLs = defaults.AllowedRedirectURLs
+	}
+	if p.SenderName == "" {
+		p.SenderName = defaults.SenderName
+	}
+	if p.TemplateID == "" {
+		p.TemplateID = defaults.TemplateID
+	}
+	return p
+}
+
+// Validate checks that the policy values are within supported bounds
+func (p OTPPolicy) Validate() error {
+	if p.Length < MinOTPLength || p.Length > MaxOTPLength {
+		return fmt.Errorf("otp length must be between %d and %d digits", MinOTPLength, MaxOTPLength)
+	}
+	if p.TTLSeconds < 30 {
+		return errors.New("otp ttl must be at least 30 seconds")
+	}
+	return nil
+}
+
+// policyFromModel converts a persisted policy to its runtime form (unset fields stay zero)
+func policyFromModel(m *models.OTPPolicy) OTPPolicy {
+	return OTPPolicy{
+		TTLSeconds:            m.TTLSeconds,
+		Length:                m.Length,
+		MaxAttempts:           m.MaxAttempts,
+		ResendCooldownSeconds: m.ResendCooldownSeconds,
+		HourlyRecipientCap:    m.HourlyRecipientCap,
+		HourlyIPCap:           m.HourlyIPCap,
+		AllowedChannels:       m.AllowedChannels,
+		AllowedCountryCodes:   m.AllowedCountryCodes,
+		AllowedRedirectURLs:   m.AllowedRedirectURLs,
+		SenderName:            m.SenderName,
+		TemplateID:            m.TemplateID,
+	}
+}
+
+// containsString reports whether values contains value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// PolicyResolver returns the OTP policy that applies to an app
+type PolicyResolver interface {
+	PolicyFor(appName string) (OTPPolicy, error)
+}
+
+// staticPolicyResolver serves policies from an in-memory configuration
+type staticPolicyResolver struct {
+	defaults  OTPPolicy
+	overrides map[string]OTPPolicy
+}
+
+// NewStaticPolicyResolver creates a PolicyResolver with defaults and optional per-app overrides
+func NewStaticPolicyResolver(defaults OTPPolicy, overrides map[string]OTPPolicy) PolicyResolver {
+	defaults = defaults.withDefaults(DefaultOTPPolicy())
+	resolved := make(map[string]OTPPolicy, len(overrides))
+	for appName, policy := range overrides {
+		resolved[appName] = policy.withDefaults(defaults)
+	}
+	return &staticPolicyResolver{
+		defaults:  defaults,
+		overrides: resolved,
+	}
+}
+
+// PolicyFor returns the app's override if present, otherwise the defaults
+func (r *staticPolicyResolver) PolicyFor(appName string) (OTPPolicy, error) {
+	if policy, ok := r.overrides[appName]; ok {
+		return policy, nil
+	}
+	return r.defaults, nil
+}
+
+// ParseOTPPolicies builds a static PolicyResolver from JSON of the form
+// {"default": {...}, "apps": {"my-app": {...}}}; an empty string yields the built-in defaults
+func ParseOTPPolicies(raw string) (PolicyResolver, error) {
+	var config struct {
+		Default OTPPolicy            `json:"default"`
+		Apps    map[string]OTPPolicy `json:"apps"`
+	}
+	if raw != "" {
+		if err := json.Unmarshal([]byte(raw), &config); err != nil {
+			return nil, fmt.Errorf("invalid OTP policy configuration: %w", err)
+		}
+	}
+
+	resolver := NewStaticPolicyResolver(config.Default, config.Apps)
+	defaults, _ := resolver.PolicyFor("")
+	if err := defaults.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid default OTP policy: %w", err)
+	}
+	for appName := range config.Apps {
+		policy, _ := resolver.PolicyFor(appName)
+		if err := policy.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid OTP policy for %s: %w", appName, err)
+		}
+	}
+	return resolver, nil
+}
+
+// dbPolicyResolver serves persisted per-app policies, layered over a fallback resolver
+type dbPolicyResolver struct {
+	repo     repository.OTPPolicyRepository
+	fallback PolicyResolver
+}
+
+// NewDBPolicyResolver creates a PolicyResolver that reads the app's policy from the database on every call
+// Fields the stored policy leaves unset, and apps without a stored policy, use the fallback resolver
+func NewDBPolicyResolver(repo repository.OTPPolicyRepository, fallback PolicyResolver) PolicyResolver {
+	return &dbPolicyResolver{repo: repo, fallback: fallback}
+}
+
+// PolicyFor returns the stored policy for the app merged over the fallback policy
+func (r *dbPolicyResolver) PolicyFor(appName string) (OTPPolicy, error) {
+	fallback, err := r.fallback.PolicyFor(appName)
+	if err != nil {
+		return OTPPolicy{}, err
+	}
+
+	stored, err := r.repo.FindByAppName(appName)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fallback, nil
+		}
+		return OTPPolicy{}, fmt.Errorf("failed to load otp policy: %w", err)
+	}
+
+	return policyFromModel(stored).withDefaults(fallback), nil
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"go-backend/internal/apps/otp/models"
+	"go-backend/internal/apps/otp/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OTPPolicyService defines business logic for managing per-app OTP policies
+type OTPPolicyService interface {
+	CreatePolicy(req models.CreateOTPPolicyRequest) (*models.OTPPolicy, error)
+	GetPolicy(id uuid.UUID) (*models.OTPPolicy, error)
+	ListPolicies() ([]models.OTPPolicy, error)
+	UpdatePolicy(id uuid.UUID, req models.UpdateOTPPolicyRequest) (*models.OTPPolicy, error)
+	DeletePolicy(id uuid.UUID) error
+}
+
+// otpPolicyService implements OTPPolicyService
+type otpPolicyService struct {
+	repo     repository.OTPPolicyRepository
+	fallback PolicyResolver
+}
+
+// NewOTPPolicyService creates a new instance of OTPPolicyService
+// fallback supplies the defaults a stored policy is validated against
+func NewOTPPolicyService(repo repository.OTPPolicyRepository, fallback PolicyResolver) OTPPolicyService {
+	return &otpPolicyService{repo: repo, fallback: fallback}
+}
+
+// CreatePolicy creates the OTP policy for an app
+func (s *otpPolicyService) CreatePolicy(req models.CreateOTPPolicyRequest) (*models.OTPPolicy, error) {
+	if existing, err := s.repo.FindByAppName(req.AppName); err == nil && existing != nil {
+		return nil, errors.New("otp policy already exists for app_name")
+	}
+
+	policy := &models.OTPPolicy{
+		AppName:               req.AppName,
+		TTLSeconds:            req.TTLSeconds,
+		Length:                req.Length,
+		ResendCooldownSeconds: req.ResendCooldownSeconds,
+		MaxAttempts:           req.MaxAttempts,
+		HourlyRecipientCap:    req.HourlyRecipientCap,
+		HourlyIPCap:           req.HourlyIPCap,
+		AllowedChannels:       req.AllowedChannels,
+		AllowedCountryCodes:   req.AllowedCountryCodes,
+		AllowedRedirectURLs:   req.AllowedRedirectURLs,
+		SenderName:            req.SenderName,
+		TemplateID:            req.TemplateID,
+	}
+	if err := s.validate(policy); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// GetPolicy retrieves an OTP policy by ID
+func (s *otpPolicyService) GetPolicy(id uuid.UUID) (*models.OTPPolicy, error) {
+	policy, err := s.repo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("otp policy not found")
+		}
+		return nil, err
+	}
+	return policy, nil
+}
+
+// ListPolicies retrieves every stored OTP policy
+func (s *otpPolicyService) ListPolicies() ([]models.OTPPolicy, error) {
+	return s.repo.FindAll()
+}
+
+// UpdatePolicy updates the provided fields of an OTP policy
+func (s *otpPolicyService) UpdatePolicy(id uuid.UUID, req models.UpdateOTPPolicyRequest) (*models.OTPPolicy, error) {
+	policy, err := s.GetPolicy(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.TTLSeconds != nil {
+		policy.TTLSeconds = *req.TTLSeconds
+	}
+	if req.Length != nil {
+		policy.Length = *req.Length
+	}
+	if req.ResendCooldownSeconds != nil {
+		policy.ResendCooldownSeconds = *req.ResendCooldownSeconds
+	}
+	if req.MaxAttempts != nil {
+		policy.MaxAttempts = *req.MaxAttempts
+	}
+	if req.HourlyRecipientCap != nil {
+		policy.HourlyRecipientCap = *req.HourlyRecipientCap
+	}
+	if req.HourlyIPCap != nil {
+		policy.HourlyIPCap = *req.HourlyIPCap
+	}
+	if req.AllowedChannels != nil {
+		policy.AllowedChannels = req.AllowedChannels
+	}
+	if req.AllowedCountryCodes != nil {
+		policy.AllowedCountryCodes = req.AllowedCountryCodes
+	}
+	if req.AllowedRedirectURLs != nil {
+		policy.AllowedRedirectURLs = req.AllowedRedirectURLs
+	}
+	if req.SenderName != nil {
+		policy.SenderName = *req.SenderName
+	}
+	if req.TemplateID != nil {
+		policy.TemplateID = *req.TemplateID
+	}
+	if err := s.validate(policy); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// DeletePolicy soft deletes an OTP policy; the app falls back to the server defaults
+func (s *otpPolicyService) DeletePolicy(id uuid.UUID) error {
+	return s.repo.Delete(id)
+}
+
+// validate checks the policy as it will be applied, i.e. merged over the fallback defaults
+func (s *otpPolicyService) validate(policy *models.OTPPolicy) error {
+	fallback, err := s.fallback.PolicyFor(policy.AppName)
+	if err != nil {
+		return err
+	}
+	if err := policyFromModel(policy).withDefaults(fallback).Validate(); err != nil {
+		return fmt.Errorf("invalid otp policy: %w", err)
+	}
+	return nil
+}
@@ -1,60 +1,154 @@
 package service
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"html"
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
+	"time"
+
+	"go-backend/internal/apps/otp/models"
+)
+
+// Provider names used in SMS routing configuration
+const (
+	ProviderNoOp     = "noop"
+	ProviderAuthKey  = "authkey"
+	ProviderTwilio   = "twilio"
+	ProviderWhatsApp = "whatsapp"
 )
 
-// OTPProvider defines the interface for sending OTP via SMS
+// providerHTTPTimeout bounds every outbound call to an SMS provider
+const providerHTTPTimeout = 10 * time.Second
+
+// OTPMessage is an OTP to deliver to a phone number
+type OTPMessage struct {
+	CountryCode      string
+	Phone            string
+	AppName          string
+	OTP              string
+	Channel          string   // sms, whatsapp or voice
+	FallbackChannels []string // Channels to try in order if Channel fails; nil uses the app's configured order
+	AllowedChannels  []string // Channels the app's policy permits; empty allows all
+	SenderName       string   // Display name shown to the recipient
+	TemplateID       string   // Provider template override; empty uses the provider default
+	Body             string   // Free-text SMS sent instead of the OTP wording (e.g. invites); OTP is ignored when set
+}
+
+// senderName returns the display name for the message, falling back to the app name
+func (m OTPMessage) senderName() string {
+	if m.SenderName != "" {
+		return m.SenderName
+	}
+	return m.AppName
+}
+
+// OTPDelivery describes how an OTP was delivered
+type OTPDelivery struct {
+	Provider  string
+	Channel   string
+	MessageID string
+}
+
+// OTPProvider defines the interface for sending OTP to a phone number
+// SendOTP is only called with one of the channels returned by Channels
 type OTPProvider interface {
-	SendOTP(countryCode, phone, appName, otpValue string) error
+	Name() string
+	Channels() []string
+	SendOTP(msg OTPMessage) (*OTPDelivery, error)
 }
 
-// noOpProvider skips OTP sending (for local environment)
-type noOpProvider struct{}
+// supportsChannel reports whether the provider can deliver on the channel
+func supportsChannel(provider OTPProvider, channel string) bool {
+	for _, c := range provider.Channels() {
+		if c == channel {
+			return true
+		}
+	}
+	return false
+}
+
+// noOpProvider skips OTP sending (for local and dev environments)
+type noOpProvider struct {
+	logPlaintext bool
+}
+
+func (n *noOpProvider) Name() string { return ProviderNoOp }
 
-func (n *noOpProvider) SendOTP(countryCode, phone, appName, otpValue string) error {
-	fmt.Printf("[OTP NoOp] Skipping SMS for %s%s, OTP: %s, App: %s\n", countryCode, phone, otpValue, appName)
-	return nil
+func (n *noOpProvider) Channels() []string {
+	return []string{models.PhoneChannelSMS, models.PhoneChannelWhatsApp, models.PhoneChannelVoice}
+}
+
+func (n *noOpProvider) SendOTP(msg OTPMessage) (*OTPDelivery, error) {
+	if msg.Body != "" {
+		fmt.Printf("[OTP NoOp] Skipping %s message for %s%s, App: %s\n", msg.Channel, msg.CountryCode, msg.Phone, msg.AppName)
+		return &OTPDelivery{Provider: ProviderNoOp, Channel: msg.Channel}, nil
+	}
+	if n.logPlaintext {
+		fmt.Printf("[OTP NoOp] Skipping %s for %s%s, OTP: %s, App: %s\n", msg.Channel, msg.CountryCode, msg.Phone, msg.OTP, msg.AppName)
+	} else {
+		fmt.Printf("[OTP NoOp] Skipping %s for %s%s, App: %s\n", msg.Channel, msg.CountryCode, msg.Phone, msg.AppName)
+	}
+	return &OTPDelivery{Provider: ProviderNoOp, Channel: msg.Channel}, nil
 }
 
 // NewNoOpProvider creates a no-op OTP provider
-func NewNoOpProvider() OTPProvider {
-	return &noOpProvider{}
+// The OTP value is only logged when logPlaintext is set, which should be limited to local development
+func NewNoOpProvider(logPlaintext bool) OTPProvider {
+	return &noOpProvider{logPlaintext: logPlaintext}
 }
 
 // authKeyProvider sends OTP via AuthKey.io API
 type authKeyProvider struct {
 	authKey    string
 	templateID string
+	client     *http.Client
 }
 
-func (a *authKeyProvider) SendOTP(countryCode, phone, appName, otpValue string) error {
+func (a *authKeyProvider) Name() string { return ProviderAuthKey }
+
+func (a *authKeyProvider) Channels() []string { return []string{models.PhoneChannelSMS} }
+
+func (a *authKeyProvider) SendOTP(msg OTPMessage) (*OTPDelivery, error) {
 	baseURL := "https://api.authkey.io/request"
 	params := url.Values{}
 	params.Add("authkey", a.authKey)
-	params.Add("mobile", phone)
-	params.Add("country_code", countryCode)
-	params.Add("sid", a.templateID)
-	params.Add("company", appName)
-	params.Add("otp", otpValue)
+	params.Add("mobile", msg.Phone)
+	params.Add("country_code", msg.CountryCode)
+	templateID := a.templateID
+	if msg.TemplateID != "" {
+		templateID = msg.TemplateID
+	}
+	params.Add("company", msg.senderName())
+	if msg.Body != "" {
+		// Free-text messages go through AuthKey's sms parameter; a template is only sent when explicitly set
+		params.Add("sms", msg.Body)
+		if msg.TemplateID != "" {
+			params.Add("sid", msg.TemplateID)
+		}
+	} else {
+		params.Add("sid", templateID)
+		params.Add("otp", msg.OTP)
+	}
 
 	reqURL := fmt.Sprintf("%s?%s", baseURL, params.Encode())
-	resp, err := http.Get(reqURL)
+	resp, err := a.client.Get(reqURL)
 	if err != nil {
-		return fmt.Errorf("failed to send OTP via AuthKey: %w", err)
+		return nil, fmt.Errorf("failed to send OTP via AuthKey: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("AuthKey API returned status %d: %s", resp.StatusCode, string(body))
+		return nil, fmt.Errorf("AuthKey API returned status %d: %s", resp.StatusCode, string(body))
 	}
 
-	fmt.Printf("[OTP AuthKey] Sent OTP to %s%s\n", countryCode, phone)
-	return nil
+	fmt.Printf("[OTP AuthKey] Sent message to %s%s\n", msg.CountryCode, msg.Phone)
+	return &OTPDelivery{Provider: ProviderAuthKey, Channel: models.PhoneChannelSMS}, nil
 }
 
 // NewAuthKeyProvider creates an AuthKey.io OTP provider
@@ -62,5 +156,177 @@ func NewAuthKeyProvider(authKey, templateID string) OTPProvider {
 	return &authKeyProvider{
 		authKey:    authKey,
 		templateID: templateID,
+		client:     &http.Client{Timeout: providerHTTPTimeout},
+	}
+}
+
+// TwilioConfig holds the settings for a Twilio-compatible messaging API
+type TwilioConfig struct {
+	AccountSID string
+	AuthToken  string
+	From       string // Sender number or messaging service SID
+	BaseURL    string // Defaults to https://api.twilio.com; override for compatible gateways
+}
+
+// twilioProvider sends OTP via a Twilio-style API: SMS through Messages and voice through Calls
+type twilioProvider struct {
+	config TwilioConfig
+	client *http.Client
+}
+
+func (t *twilioProvider) Name() string { return ProviderTwilio }
+
+func (t *twilioProvider) Channels() []string {
+	return []string{models.PhoneChannelSMS, models.PhoneChannelVoice}
+}
+
+func (t *twilioProvider) SendOTP(msg OTPMessage) (*OTPDelivery, error) {
+	form := url.Values{}
+	form.Set("To", msg.CountryCode+msg.Phone)
+	form.Set("From", t.config.From)
+
+	resource := "Messages.json"
+	if msg.Channel == models.PhoneChannelVoice {
+		// Read the code digit by digit, twice
+		spoken := strings.Join(strings.Split(msg.OTP, ""), ", ")
+		say := html.EscapeString(fmt.Sprintf("Your %s verification code is %s. Again, your code is %s.", msg.senderName(), spoken, spoken))
+		form.Set("Twiml", "<Response><Say>"+say+"</Say></Response>")
+		resource = "Calls.json"
+	} else if msg.Body != "" {
+		form.Set("Body", msg.Body)
+	} else {
+		form.Set("Body", fmt.Sprintf("Your %s verification code is %s", msg.senderName(), msg.OTP))
+	}
+
+	reqURL := fmt.Sprintf("%s/2010-04-01/Accounts/%s/%s", t.config.BaseURL, url.PathEscape(t.config.AccountSID), resource)
+	req, err := http.NewRequest(http.MethodPost, reqURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(t.config.AccountSID, t.config.AuthToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send OTP via Twilio: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("Twilio API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var result struct {
+		SID string `json:"sid"`
+	}
+	_ = json.Unmarshal(body, &result)
+
+	fmt.Printf("[OTP Twilio] Sent %s OTP to %s%s\n", msg.Channel, msg.CountryCode, msg.Phone)
+	return &OTPDelivery{Provider: ProviderTwilio, Channel: msg.Channel, MessageID: result.SID}, nil
+}
+
+// NewTwilioProvider creates a Twilio-style OTP provider
+func NewTwilioProvider(config TwilioConfig) OTPProvider {
+	if config.BaseURL == "" {
+		config.BaseURL = "https://api.twilio.com"
+	}
+	config.BaseURL = strings.TrimRight(config.BaseURL, "/")
+	return &twilioProvider{
+		config: config,
+		client: &http.Client{Timeout: providerHTTPTimeout},
+	}
+}
+
+// WhatsAppConfig holds the settings for the WhatsApp Cloud API provider
+type WhatsAppConfig struct {
+	AccessToken   string
+	PhoneNumberID string
+	TemplateName  string // Approved authentication template with a one-time password button
+	LanguageCode  string // Template language, defaults to "en"
+	BaseURL       string // Defaults to https://graph.facebook.com/v19.0; override for a local stand-in
+}
+
+// whatsAppProvider sends OTP through an authentication template on the WhatsApp Cloud API
+type whatsAppProvider struct {
+	config WhatsAppConfig
+	client *http.Client
+}
+
+func (w *whatsAppProvider) Name() string { return ProviderWhatsApp }
+
+func (w *whatsAppProvider) Channels() []string { return []string{models.PhoneChannelWhatsApp} }
+
+func (w *whatsAppProvider) SendOTP(msg OTPMessage) (*OTPDelivery, error) {
+	templateName := w.config.TemplateName
+	if msg.TemplateID != "" {
+		templateName = msg.TemplateID
+	}
+	codeParam := []map[string]string{{"type": "text", "text": msg.OTP}}
+	payload := map[string]interface{}{
+		"messaging_product": "whatsapp",
+		"to":                strings.TrimPrefix(msg.CountryCode, "+") + msg.Phone,
+		"type":              "template",
+		"template": map[string]interface{}{
+			"name":     templateName,
+			"language": map[string]string{"code": w.config.LanguageCode},
+			"components": []map[string]interface{}{
+				{"type": "body", "parameters": codeParam},
+				{"type": "button", "sub_type": "url", "index": "0", "parameters": codeParam},
+			},
+		},
+	}
+	reqBody, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	reqURL := fmt.Sprintf("%s/%s/messages", w.config.BaseURL, url.PathEscape(w.config.PhoneNumberID))
+	req, err := http.NewRequest(http.MethodPost, reqURL, bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+w.config.AccessToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send OTP via WhatsApp: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("WhatsApp API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var result struct {
+		Messages []struct {
+			ID string `json:"id"`
+		} `json:"messages"`
+	}
+	_ = json.Unmarshal(body, &result)
+
+	delivery := &OTPDelivery{Provider: ProviderWhatsApp, Channel: models.PhoneChannelWhatsApp}
+	if len(result.Messages) > 0 {
+		delivery.MessageID = result.Messages[0].ID
+	}
+
+	fmt.Printf("[OTP WhatsApp] Sent OTP to %s%s\n", msg.CountryCode, msg.Phone)
+	return delivery, nil
+}
+
+// NewWhatsAppProvider creates a WhatsApp Cloud API OTP provider
+func NewWhatsAppProvider(config WhatsAppConfig) OTPProvider {
+	if config.BaseURL == "" {
+		config.BaseURL = "https://graph.facebook.com/v19.0"
+	}
+	if config.LanguageCode == "" {
+		config.LanguageCode = "en"
+	}
+	config.BaseURL = strings.TrimRight(config.BaseURL, "/")
+	return &whatsAppProvider{
+		config: config,
+		client: &http.Client{Timeout: providerHTTPTimeout},
 	}
 }
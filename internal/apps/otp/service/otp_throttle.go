@@ -0,0 +1,70 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go-backend/internal/apps/otp/models"
+	"go-backend/internal/apps/otp/repository"
+)
+
+// Throttling errors; handlers map these to 429 Too Many Requests
+var (
+	ErrOTPAttemptsExceeded = errors.New("too many failed attempts, please request a new otp")
+	ErrOTPResendCooldown   = errors.New("an otp was sent recently, please wait before requesting another")
+	ErrOTPRecipientLimit   = errors.New("hourly otp limit reached for this recipient")
+	ErrOTPIPLimit          = errors.New("hourly otp limit reached for this ip address")
+)
+
+// Policy errors; handlers map these to 400 Bad Request
+var (
+	ErrOTPChannelNotAllowed = errors.New("otp channel is not enabled for this app")
+	ErrOTPCountryNotAllowed = errors.New("otp delivery is not enabled for this country code")
+)
+
+// otpThrottle enforces resend cooldowns and hourly caps shared by the phone and email OTP services
+type otpThrottle struct {
+	sendLogRepo repository.OTPSendLogRepository
+}
+
+// checkSend returns a throttling error if a new OTP may not be issued yet
+// lastSentAt is the time the recipient's current OTP was issued, if any
+func (t *otpThrottle) checkSend(policy OTPPolicy, appName, channel, recipient, ipAddress string, lastSentAt *time.Time) error {
+	now := time.Now()
+
+	if lastSentAt != nil && now.Sub(*lastSentAt) < policy.ResendCooldown() {
+		return ErrOTPResendCooldown
+	}
+
+	since := now.Add(-time.Hour)
+	recipientCount, err := t.sendLogRepo.CountByRecipientSince(appName, channel, recipient, since)
+	if err != nil {
+		return fmt.Errorf("failed to check otp limits: %w", err)
+	}
+	if recipientCount >= int64(policy.HourlyRecipientCap) {
+		return ErrOTPRecipientLimit
+	}
+
+	if ipAddress != "" {
+		ipCount, err := t.sendLogRepo.CountByIPSince(appName, ipAddress, since)
+		if err != nil {
+			return fmt.Errorf("failed to check otp limits: %w", err)
+		}
+		if ipCount >= int64(policy.HourlyIPCap) {
+			return ErrOTPIPLimit
+		}
+	}
+
+	return nil
+}
+
+// recordSend logs an OTP issuance so it counts towards the hourly caps
+func (t *otpThrottle) recordSend(appName, channel, recipient, ipAddress string) error {
+	return t.sendLogRepo.Create(&models.OTPSendLog{
+		AppName:   appName,
+		Channel:   channel,
+		Recipient: recipient,
+		IPAddress: ipAddress,
+	})
+}
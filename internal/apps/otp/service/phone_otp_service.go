@@ -3,63 +3,173 @@ package service
 import (
 	"errors"
 	"fmt"
-	"math/rand"
 	"time"
 
+	authService "go-backend/internal/apps/auth/service"
 	"go-backend/internal/apps/otp/models"
 	"go-backend/internal/apps/otp/repository"
+	"go-backend/pkg/identifier"
 
 	"gorm.io/gorm"
 )
 
 // PhoneOTPService defines business logic for Phone OTP
 type PhoneOTPService interface {
-	CreateOrUpdateOTP(req models.CreatePhoneOTPRequest) (*models.PhoneOTPResponse, error)
+	CreateOrUpdateOTP(req models.CreatePhoneOTPRequest, clientIP string) (*models.PhoneOTPResponse, error)
 	VerifyOTP(req models.VerifyPhoneOTPRequest) (*models.VerifyPhoneOTPResponse, error)
+	ConsumeOTP(appName, countryCode, phone, value string) error
 }
 
 // phoneOTPService implements PhoneOTPService
 type phoneOTPService struct {
 	repo        repository.PhoneOTPRepository
+	throttle    *otpThrottle
+	policies    PolicyResolver
 	otpProvider OTPProvider
+	authService authService.AuthService
 }
 
 // NewPhoneOTPService creates a new instance of PhoneOTPService
-func NewPhoneOTPService(repo repository.PhoneOTPRepository, provider OTPProvider) PhoneOTPService {
+func NewPhoneOTPService(
+	repo repository.PhoneOTPRepository,
+	sendLogRepo repository.OTPSendLogRepository,
+	policies PolicyResolver,
+	provider OTPProvider,
+	authSvc authService.AuthService,
+) PhoneOTPService {
 	return &phoneOTPService{
 		repo:        repo,
+		throttle:    &otpThrottle{sendLogRepo: sendLogRepo},
+		policies:    policies,
 		otpProvider: provider,
+		authService: authSvc,
 	}
 }
 
-// generateOTP generates a random 4-digit OTP
-func generateOTP() string {
-	return fmt.Sprintf("%04d", rand.Intn(10000))
-}
+// CreateOrUpdateOTP creates or overrides OTP for a phone number and sets expiry from the app's OTP policy
+// Requests are throttled per recipient and per client IP according to the app's OTP policy
+func (s *phoneOTPService) CreateOrUpdateOTP(req models.CreatePhoneOTPRequest, clientIP string) (*models.PhoneOTPResponse, error) {
+	policy, err := s.policies.PolicyFor(req.AppName)
+	if err != nil {
+		return nil, err
+	}
+
+	channel := req.Channel
+	if channel == "" {
+		channel = models.PhoneChannelSMS
+	}
+	if !policy.AllowsChannel(channel) {
+		return nil, ErrOTPChannelNotAllowed
+	}
+	// OTPs, attempts and send caps are keyed on the normalized number so every way of writing it shares them
+	phone, err := identifier.ParsePhone(req.CountryCode, req.Phone)
+	if err != nil {
+		return nil, err
+	}
+	if !policy.AllowsCountryCode(phone.CountryCode) {
+		return nil, ErrOTPCountryNotAllowed
+	}
 
-// CreateOrUpdateOTP creates or overrides OTP for a phone number and sets expiry to 10 minutes from now
-func (s *phoneOTPService) CreateOrUpdateOTP(req models.CreatePhoneOTPRequest) (*models.PhoneOTPResponse, error) {
-	// Generate random 4-digit OTP
-	otpValue := generateOTP()
-	expiresAt := time.Now().Add(10 * time.Minute)
+	recipient := phone.E164()
 
-	if err := s.repo.Upsert(req.AppName, req.CountryCode, req.Phone, otpValue, expiresAt); err != nil {
+	var lastSentAt *time.Time
+	existing, err := s.repo.FindByPhone(req.AppName, phone.CountryCode, phone.Number)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if existing != nil {
+		lastSentAt = &existing.UpdatedAt
+	}
+
+	if err := s.throttle.checkSend(policy, req.AppName, models.OTPChannelPhone, recipient, clientIP, lastSentAt); err != nil {
+		return nil, err
+	}
+
+	otpValue, err := generateOTP(policy.Length)
+	if err != nil {
+		return nil, err
+	}
+	otpHash, err := hashOTP(req.AppName, recipient, otpValue)
+	if err != nil {
+		return nil, err
+	}
+	expiresAt := time.Now().Add(policy.TTL())
+
+	if err := s.repo.Upsert(req.AppName, phone.CountryCode, phone.Number, otpHash, expiresAt); err != nil {
+		return nil, err
+	}
+
+	// Count the issuance before sending so failed sends still consume the allowance
+	if err := s.throttle.recordSend(req.AppName, models.OTPChannelPhone, recipient, clientIP); err != nil {
 		return nil, err
 	}
 
 	// Send OTP via provider - fail if sending fails
-	if err := s.otpProvider.SendOTP(req.CountryCode, req.Phone, req.AppName, otpValue); err != nil {
+	delivery, err := s.otpProvider.SendOTP(OTPMessage{
+		CountryCode:      req.CountryCode,
+		Phone:            req.Phone,
+		AppName:          req.AppName,
+		OTP:              otpValue,
+		Channel:          channel,
+		FallbackChannels: req.FallbackChannels,
+		AllowedChannels:  policy.AllowedChannels,
+		SenderName:       policy.SenderNameFor(req.AppName),
+		TemplateID:       policy.TemplateID,
+	})
+	if err != nil {
 		return nil, fmt.Errorf("failed to send OTP: %w", err)
 	}
 
+	// The OTP is already delivered, so a failure to record the delivery is only logged
+	if err := s.repo.UpdateDelivery(req.AppName, phone.CountryCode, phone.Number, delivery.Provider, delivery.Channel); err != nil {
+		fmt.Printf("[Phone OTP Service] Failed to record delivery for %s: %v\n", recipient, err)
+	}
+
 	return &models.PhoneOTPResponse{
 		ExpiresAt: expiresAt,
+		Channel:   delivery.Channel,
 	}, nil
 }
 
-// VerifyOTP verifies provided OTP value and expiry
+// VerifyOTP verifies an OTP and, when it is valid, starts a session for its user
 func (s *phoneOTPService) VerifyOTP(req models.VerifyPhoneOTPRequest) (*models.VerifyPhoneOTPResponse, error) {
-	otp, err := s.repo.FindByPhone(req.AppName, req.CountryCode, req.Phone)
+	resp, err := s.checkOTP(req)
+	if err != nil || !resp.Valid {
+		return resp, err
+	}
+
+	// Link the verified phone to its user and start a session (or an MFA challenge)
+	login, err := s.authService.LoginWithPhone(req.AppName, req.CountryCode, req.Phone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+	resp.Session = login.Session
+	resp.MFA = login.MFA
+
+	return resp, nil
+}
+
+// ConsumeOTP verifies and consumes an OTP without starting a session, e.g. to confirm a user's new phone number
+func (s *phoneOTPService) ConsumeOTP(appName, countryCode, phone, value string) error {
+	resp, err := s.checkOTP(models.VerifyPhoneOTPRequest{AppName: appName, CountryCode: countryCode, Phone: phone, Value: value})
+	if err != nil {
+		return err
+	}
+	if !resp.Valid {
+		return ErrOTPInvalid
+	}
+	return nil
+}
+
+// checkOTP verifies provided OTP value and expiry and consumes the OTP on success
+// Each attempt is reserved before the comparison, and the OTP is invalidated once the app's maximum number of attempts is used
+func (s *phoneOTPService) checkOTP(req models.VerifyPhoneOTPRequest) (*models.VerifyPhoneOTPResponse, error) {
+	phone, err := identifier.ParsePhone(req.CountryCode, req.Phone)
+	if err != nil {
+		return nil, err
+	}
+
+	otp, err := s.repo.FindByPhone(req.AppName, phone.CountryCode, phone.Number)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("otp not found")
@@ -67,20 +177,68 @@ func (s *phoneOTPService) VerifyOTP(req models.VerifyPhoneOTPRequest) (*models.V
 		return nil, err
 	}
 
+	policy, err := s.policies.PolicyFor(req.AppName)
+	if err != nil {
+		return nil, err
+	}
+	if otp.Attempts >= policy.MaxAttempts {
+		if err := s.repo.Delete(req.AppName, phone.CountryCode, phone.Number); err != nil {
+			return nil, err
+		}
+		return nil, ErrOTPAttemptsExceeded
+	}
+
 	now := time.Now()
-	valid := (req.Value == otp.Value) && (now.Before(otp.ExpiresAt) || now.Equal(otp.ExpiresAt))
-
-	message := "OTP verified successfully"
-	if !valid {
-		if req.Value != otp.Value {
-			message = "Invalid OTP"
-		} else {
-			message = "OTP expired"
+	if now.After(otp.ExpiresAt) {
+		return &models.VerifyPhoneOTPResponse{
+			Valid:   false,
+			Message: "OTP expired",
+		}, nil
+	}
+
+	// Reserve the attempt before comparing so parallel guesses cannot exceed the limit
+	attempts, reserved, err := s.repo.ReserveAttempt(otp.ID, policy.MaxAttempts)
+	if err != nil {
+		return nil, err
+	}
+	if !reserved {
+		if err := s.repo.Delete(req.AppName, phone.CountryCode, phone.Number); err != nil {
+			return nil, err
 		}
+		return nil, ErrOTPAttemptsExceeded
+	}
+
+	matches, err := otpMatches(req.AppName, phone.E164(), req.Value, otp.Value)
+	if err != nil {
+		return nil, err
+	}
+	if !matches {
+		if attempts >= policy.MaxAttempts {
+			if err := s.repo.Delete(req.AppName, phone.CountryCode, phone.Number); err != nil {
+				return nil, err
+			}
+			return nil, ErrOTPAttemptsExceeded
+		}
+
+		remaining := policy.MaxAttempts - attempts
+		return &models.VerifyPhoneOTPResponse{
+			Valid:             false,
+			Message:           "Invalid OTP",
+			AttemptsRemaining: &remaining,
+		}, nil
+	}
+
+	// Consume the OTP so it cannot be verified twice
+	consumed, err := s.repo.Consume(otp.ID, policy.MaxAttempts)
+	if err != nil {
+		return nil, err
+	}
+	if !consumed {
+		return nil, errors.New("otp not found")
 	}
 
 	return &models.VerifyPhoneOTPResponse{
-		Valid:   valid,
-		Message: message,
+		Valid:   true,
+		Message: "OTP verified successfully",
 	}, nil
 }
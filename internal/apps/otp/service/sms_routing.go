@@ -0,0 +1,234 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-backend/internal/apps/otp/models"
+)
+
+// SMSRoute is an ordered list of provider names, tried in turn until one succeeds
+type SMSRoute []string
+
+// SMSRouteSet selects a route by country code, falling back to Default
+// Only providers on the route that support the requested channel are used
+type SMSRouteSet struct {
+	Default          SMSRoute            `json:"default"`
+	Countries        map[string]SMSRoute `json:"countries"`         // Keyed by country code, e.g. "+91"
+	ChannelFallbacks map[string][]string `json:"channel_fallbacks"` // Channels to try when a channel fails, e.g. {"whatsapp": ["sms"]}
+}
+
+// SMSRouting configures provider selection and failover for phone OTPs on every channel
+type SMSRouting struct {
+	SMSRouteSet
+	Apps       map[string]SMSRouteSet `json:"apps"`        // Per-app overrides
+	MaxRetries int                    `json:"max_retries"` // Retries per provider before failing over
+	BackoffMS  int                    `json:"backoff_ms"`  // Initial backoff between retries, doubled each retry
+	TimeoutMS  int                    `json:"timeout_ms"`  // Per-attempt timeout
+}
+
+// ParseSMSRouting parses SMS routing JSON; an empty string routes everything through fallback
+func ParseSMSRouting(raw string, fallback SMSRoute) (SMSRouting, error) {
+	routing := SMSRouting{MaxRetries: 1, BackoffMS: 250, TimeoutMS: 8000}
+	if raw != "" {
+		if err := json.Unmarshal([]byte(raw), &routing); err != nil {
+			return routing, fmt.Errorf("invalid SMS routing configuration: %w", err)
+		}
+	}
+	if len(routing.Default) == 0 {
+		routing.Default = fallback
+	}
+	if len(routing.Default) == 0 {
+		return routing, errors.New("SMS routing has no default providers")
+	}
+	if routing.MaxRetries < 0 || routing.BackoffMS < 0 || routing.TimeoutMS <= 0 {
+		return routing, errors.New("SMS routing retry settings must be positive")
+	}
+
+	fallbackSets := []map[string][]string{routing.ChannelFallbacks}
+	for _, app := range routing.Apps {
+		fallbackSets = append(fallbackSets, app.ChannelFallbacks)
+	}
+	for _, fallbacks := range fallbackSets {
+		for channel, order := range fallbacks {
+			for _, c := range append([]string{channel}, order...) {
+				if !isPhoneChannel(c) {
+					return routing, fmt.Errorf("SMS routing references unknown channel %q", c)
+				}
+			}
+		}
+	}
+	return routing, nil
+}
+
+// isPhoneChannel reports whether channel is a supported phone OTP channel
+func isPhoneChannel(channel string) bool {
+	switch channel {
+	case models.PhoneChannelSMS, models.PhoneChannelWhatsApp, models.PhoneChannelVoice:
+		return true
+	}
+	return false
+}
+
+// channelsFor returns the channel followed by its fallbacks, app config taking precedence over global config
+func (r SMSRouting) channelsFor(appName, channel string) []string {
+	fallbacks, ok := r.Apps[appName].ChannelFallbacks[channel]
+	if !ok {
+		fallbacks = r.ChannelFallbacks[channel]
+	}
+	return dedupeChannels(append([]string{channel}, fallbacks...))
+}
+
+// dedupeChannels drops repeated channels while keeping order
+func dedupeChannels(channels []string) []string {
+	seen := make(map[string]bool, len(channels))
+	result := make([]string, 0, len(channels))
+	for _, c := range channels {
+		if !seen[c] {
+			seen[c] = true
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// routeFor resolves the route for an app and country:
+// app+country, then app default, then global country, then global default
+func (r SMSRouting) routeFor(appName, countryCode string) SMSRoute {
+	if app, ok := r.Apps[appName]; ok {
+		if route, ok := app.Countries[countryCode]; ok && len(route) > 0 {
+			return route
+		}
+		if len(app.Default) > 0 {
+			return app.Default
+		}
+	}
+	if route, ok := r.Countries[countryCode]; ok && len(route) > 0 {
+		return route
+	}
+	return r.Default
+}
+
+// routes returns every configured route, used for validation
+func (r SMSRouting) routes() []SMSRoute {
+	routes := []SMSRoute{r.Default}
+	for _, route := range r.Countries {
+		routes = append(routes, route)
+	}
+	for _, app := range r.Apps {
+		routes = append(routes, app.Default)
+		for _, route := range app.Countries {
+			routes = append(routes, route)
+		}
+	}
+	return routes
+}
+
+// routingProvider is a composite OTPProvider that routes by app and country and fails over between providers
+type routingProvider struct {
+	providers map[string]OTPProvider
+	routing   SMSRouting
+}
+
+// NewRoutingProvider creates a composite OTPProvider over the named providers
+// Every provider referenced by the routing configuration must be present in providers
+func NewRoutingProvider(providers map[string]OTPProvider, routing SMSRouting) (OTPProvider, error) {
+	for _, route := range routing.routes() {
+		for _, name := range route {
+			if _, ok := providers[name]; !ok {
+				return nil, fmt.Errorf("SMS routing references unconfigured provider %q", name)
+			}
+		}
+	}
+	return &routingProvider{providers: providers, routing: routing}, nil
+}
+
+func (r *routingProvider) Name() string { return "router" }
+
+func (r *routingProvider) Channels() []string {
+	return []string{models.PhoneChannelSMS, models.PhoneChannelWhatsApp, models.PhoneChannelVoice}
+}
+
+// SendOTP tries each channel in fallback order and, per channel, each provider on the route that supports it,
+// retrying with exponential backoff before failing over
+func (r *routingProvider) SendOTP(msg OTPMessage) (*OTPDelivery, error) {
+	if msg.Channel == "" {
+		msg.Channel = models.PhoneChannelSMS
+	}
+	channels := r.routing.channelsFor(msg.AppName, msg.Channel)
+	if msg.FallbackChannels != nil {
+		channels = dedupeChannels(append([]string{msg.Channel}, msg.FallbackChannels...))
+	}
+	if len(msg.AllowedChannels) > 0 {
+		allowed := channels[:0:0]
+		for _, channel := range channels {
+			if containsString(msg.AllowedChannels, channel) {
+				allowed = append(allowed, channel)
+			}
+		}
+		channels = allowed
+	}
+
+	route := r.routing.routeFor(msg.AppName, msg.CountryCode)
+	timeout := time.Duration(r.routing.TimeoutMS) * time.Millisecond
+
+	var errs []error
+	for _, channel := range channels {
+		channelMsg := msg
+		channelMsg.Channel = channel
+
+		attempted := false
+		for _, name := range route {
+			provider := r.providers[name]
+			if !supportsChannel(provider, channel) {
+				continue
+			}
+			attempted = true
+
+			backoff := time.Duration(r.routing.BackoffMS) * time.Millisecond
+			for attempt := 0; attempt <= r.routing.MaxRetries; attempt++ {
+				if attempt > 0 {
+					time.Sleep(backoff)
+					backoff *= 2
+				}
+
+				delivery, err := sendWithTimeout(provider, channelMsg, timeout)
+				if err == nil {
+					if delivery.Channel == "" {
+						delivery.Channel = channel
+					}
+					return delivery, nil
+				}
+				fmt.Printf("[OTP Router] Provider %s (%s) attempt %d failed for %s%s: %v\n", name, channel, attempt+1, msg.CountryCode, msg.Phone, err)
+				errs = append(errs, fmt.Errorf("%s/%s: %w", name, channel, err))
+			}
+		}
+		if !attempted {
+			errs = append(errs, fmt.Errorf("no provider configured for channel %s", channel))
+		}
+	}
+
+	return nil, fmt.Errorf("all OTP providers failed: %w", errors.Join(errs...))
+}
+
+// sendWithTimeout bounds a single provider call; a timed out call is treated as a failure
+func sendWithTimeout(provider OTPProvider, msg OTPMessage, timeout time.Duration) (*OTPDelivery, error) {
+	type result struct {
+		delivery *OTPDelivery
+		err      error
+	}
+	done := make(chan result, 1)
+	go func() {
+		delivery, err := provider.SendOTP(msg)
+		done <- result{delivery, err}
+	}()
+
+	select {
+	case res := <-done:
+		return res.delivery, res.err
+	case <-time.After(timeout):
+		return nil, fmt.Errorf("timed out after %s", timeout)
+	}
+}
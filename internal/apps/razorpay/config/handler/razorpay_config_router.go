@@ -1,17 +1,22 @@
 package handler
 
-import "github.com/gin-gonic/gin"
+import (
+	"go-backend/internal/common/middleware"
+
+	"github.com/gin-gonic/gin"
+)
 
 // RegisterRazorpayConfigRoutes registers all razorpay config-related routes
 // Note: POST route is registered separately in main.go (exempt from CORS)
-func RegisterRazorpayConfigRoutes(router *gin.RouterGroup, handler *RazorpayConfigHandler) {
+// Reads are open to every admin role; writes require the admin role
+func RegisterRazorpayConfigRoutes(router *gin.RouterGroup, handler *RazorpayConfigHandler, adminGuard middleware.AdminGuard) {
 	configs := router.Group("/razorpay-configs")
 	{
 		// POST route is registered in main.go before CORS middleware
-		configs.GET("", handler.GetAllRazorpayConfigs)
-		configs.GET("/by-app", handler.GetRazorpayConfigByAppNameAndEnv)
-		configs.GET("/:id", handler.GetRazorpayConfigByID)
-		configs.PUT("/:id", handler.UpdateRazorpayConfig)
-		configs.DELETE("/:id", handler.DeleteRazorpayConfig)
+		configs.GET("", adminGuard(), handler.GetAllRazorpayConfigs)
+		configs.GET("/by-app", adminGuard(), handler.GetRazorpayConfigByAppNameAndEnv)
+		configs.GET("/:id", adminGuard(), handler.GetRazorpayConfigByID)
+		configs.PUT("/:id", adminGuard(middleware.RoleAdmin), handler.UpdateRazorpayConfig)
+		configs.DELETE("/:id", adminGuard(middleware.RoleAdmin), handler.DeleteRazorpayConfig)
 	}
 }
@@ -1,17 +1,22 @@
 package handler
 
 import (
+	"errors"
 	"io"
 	"net/http"
 	"strings"
 
 	"go-backend/internal/apps/razorpay/subscription/models"
 	"go-backend/internal/apps/razorpay/subscription/service"
+	"go-backend/internal/common/middleware"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
+// maxWebhookBodySize caps Razorpay webhook bodies; real events are a few kilobytes
+const maxWebhookBodySize = 1 << 20
+
 // SubscriptionHandler handles HTTP requests for subscription operations
 type SubscriptionHandler struct {
 	service service.SubscriptionService
@@ -22,15 +27,47 @@ func NewSubscriptionHandler(service service.SubscriptionService) *SubscriptionHa
 	return &SubscriptionHandler{service: service}
 }
 
+// currentPrincipal returns the authenticated principal or responds with 401
+func currentPrincipal(c *gin.Context) (*middleware.Principal, bool) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+		return nil, false
+	}
+	return principal, true
+}
+
+// ownsSubscription reports whether the subscription belongs to the principal
+// Subscriptions owned by someone else are reported as not found
+func ownsSubscription(c *gin.Context, principal *middleware.Principal, subscription *models.SubscriptionResponse) bool {
+	if subscription.UserID != principal.UserID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "subscription not found"})
+		return false
+	}
+	return true
+}
+
 // CreateCheckoutURL handles POST /api/v1/subscriptions/checkout
 // Creates a subscription and returns the checkout URL
 func (h *SubscriptionHandler) CreateCheckoutURL(c *gin.Context) {
+	principal, ok := currentPrincipal(c)
+	if !ok {
+		return
+	}
+
 	var req models.CreateSubscriptionRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	// Subscriptions are always created for the authenticated user within their app
+	if (req.UserID != uuid.Nil && req.UserID != principal.UserID) || req.AppName != principal.AppName {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	req.UserID = principal.UserID
+
 	response, err := h.service.CreateCheckoutURL(req)
 	if err != nil {
 		// Extract more specific error message if possible
@@ -52,12 +89,30 @@ func (h *SubscriptionHandler) CreateCheckoutURL(c *gin.Context) {
 // VerifyPayment handles POST /api/v1/subscriptions/verify
 // Verifies payment signature after successful payment
 func (h *SubscriptionHandler) VerifyPayment(c *gin.Context) {
+	principal, ok := currentPrincipal(c)
+	if !ok {
+		return
+	}
+
 	var req models.VerifyPaymentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	subscription, err := h.service.GetSubscriptionByRazorpayID(req.RazorpaySubscriptionID)
+	if err != nil {
+		if err.Error() == "subscription not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !ownsSubscription(c, principal, subscription) {
+		return
+	}
+
 	response, err := h.service.VerifyPayment(req)
 	if err != nil {
 		if err.Error() == "invalid signature" {
@@ -79,11 +134,16 @@ func (h *SubscriptionHandler) VerifyPayment(c *gin.Context) {
 }
 
 // HandleWebhook handles POST /api/v1/subscriptions/webhook
-// Receives and processes Razorpay webhook events
+// Receives Razorpay webhook events, stores them and processes each event once
 func (h *SubscriptionHandler) HandleWebhook(c *gin.Context) {
-	// Read raw body
-	body, err := io.ReadAll(c.Request.Body)
+	// Read raw body; the endpoint is unauthenticated, so the body size is capped
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxWebhookBodySize))
 	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "request body too large"})
+			return
+		}
 		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read request body"})
 		return
 	}
@@ -95,23 +155,36 @@ func (h *SubscriptionHandler) HandleWebhook(c *gin.Context) {
 		return
 	}
 
-	// Process webhook
-	if err := h.service.HandleWebhook(body, signature); err != nil {
-		if err.Error() == "invalid webhook signature" {
+	// Process webhook; Razorpay identifies each event, including its retries, by the event ID header
+	duplicate, err := h.service.HandleWebhook(body, signature, c.GetHeader("X-Razorpay-Event-Id"))
+	if err != nil {
+		switch err.Error() {
+		case "invalid webhook signature":
 			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
-			return
+		case "invalid webhook payload":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Respond with success
+	// Respond with success; duplicates are acknowledged so Razorpay stops retrying
+	if duplicate {
+		c.JSON(http.StatusOK, gin.H{"message": "webhook already processed"})
+		return
+	}
 	c.JSON(http.StatusOK, gin.H{"message": "webhook processed successfully"})
 }
 
 // GetSubscription handles GET /api/v1/subscriptions/:id
 // Retrieves subscription details by ID
 func (h *SubscriptionHandler) GetSubscription(c *gin.Context) {
+	principal, ok := currentPrincipal(c)
+	if !ok {
+		return
+	}
+
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid subscription id"})
@@ -128,12 +201,21 @@ func (h *SubscriptionHandler) GetSubscription(c *gin.Context) {
 		return
 	}
 
+	if !ownsSubscription(c, principal, subscription) {
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{"data": subscription})
 }
 
 // GetSubscriptionByRazorpayID handles GET /api/v1/subscriptions/razorpay/:razorpay_id
 // Retrieves subscription details by Razorpay subscription ID
 func (h *SubscriptionHandler) GetSubscriptionByRazorpayID(c *gin.Context) {
+	principal, ok := currentPrincipal(c)
+	if !ok {
+		return
+	}
+
 	razorpayID := c.Param("razorpay_id")
 	if razorpayID == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "razorpay subscription id required"})
@@ -150,12 +232,21 @@ func (h *SubscriptionHandler) GetSubscriptionByRazorpayID(c *gin.Context) {
 		return
 	}
 
+	if !ownsSubscription(c, principal, subscription) {
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{"data": subscription})
 }
 
 // GetLatestSubscriptionByPhoneAndApp handles GET /api/v1/subscriptions/latest
 // Retrieves the latest subscription for a user by phone number and app name
 func (h *SubscriptionHandler) GetLatestSubscriptionByPhoneAndApp(c *gin.Context) {
+	principal, ok := currentPrincipal(c)
+	if !ok {
+		return
+	}
+
 	phone := c.Query("phone")
 	appName := c.Query("app_name")
 
@@ -179,18 +270,40 @@ func (h *SubscriptionHandler) GetLatestSubscriptionByPhoneAndApp(c *gin.Context)
 		return
 	}
 
+	if !ownsSubscription(c, principal, subscription) {
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{"data": subscription})
 }
 
 // CancelSubscription handles POST /api/v1/subscriptions/:id/cancel
 // Cancels an active subscription
 func (h *SubscriptionHandler) CancelSubscription(c *gin.Context) {
+	principal, ok := currentPrincipal(c)
+	if !ok {
+		return
+	}
+
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid subscription id"})
 		return
 	}
 
+	subscription, err := h.service.GetSubscriptionByID(id)
+	if err != nil {
+		if err.Error() == "subscription not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !ownsSubscription(c, principal, subscription) {
+		return
+	}
+
 	err = h.service.CancelSubscription(id)
 	if err != nil {
 		if err.Error() == "subscription not found" {
@@ -205,8 +318,13 @@ func (h *SubscriptionHandler) CancelSubscription(c *gin.Context) {
 }
 
 // CheckAuthenticationStatus handles GET /api/v1/subscriptions/check-authentication
-// Checks if a phone number has ever had an authenticated subscription
+// Checks if the authenticated user's phone number has ever had an authenticated subscription
 func (h *SubscriptionHandler) CheckAuthenticationStatus(c *gin.Context) {
+	principal, ok := currentPrincipal(c)
+	if !ok {
+		return
+	}
+
 	phone := c.Query("phone")
 	if phone == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "phone number is required"})
@@ -214,8 +332,12 @@ func (h *SubscriptionHandler) CheckAuthenticationStatus(c *gin.Context) {
 	}
 
 	appName := c.Query("app_name")
+	if appName != "" && appName != principal.AppName {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
 
-	response, err := h.service.CheckAuthenticationStatus(phone, appName)
+	response, err := h.service.CheckAuthenticationStatus(principal.UserID, phone, principal.AppName)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -1,33 +1,45 @@
 package handler
 
-import "github.com/gin-gonic/gin"
+import (
+	"go-backend/internal/common/middleware"
+
+	"github.com/gin-gonic/gin"
+)
 
 // RegisterSubscriptionRoutes registers all subscription-related routes
-func RegisterSubscriptionRoutes(router *gin.RouterGroup, handler *SubscriptionHandler) {
+// requireAuth guards routes that act on behalf of the authenticated user,
+// adminGuard guards the webhook event log; replaying events needs an admin or support key
+func RegisterSubscriptionRoutes(router *gin.RouterGroup, handler *SubscriptionHandler, requireAuth gin.HandlerFunc, adminGuard middleware.AdminGuard) {
 	subscriptions := router.Group("/subscriptions")
 	{
 		// Create checkout URL for UPI Autopay subscription
-		subscriptions.POST("/checkout", handler.CreateCheckoutURL)
+		subscriptions.POST("/checkout", requireAuth, handler.CreateCheckoutURL)
 
 		// Verify payment after successful checkout
-		subscriptions.POST("/verify", handler.VerifyPayment)
+		subscriptions.POST("/verify", requireAuth, handler.VerifyPayment)
 
 		// Webhook endpoint for Razorpay events
 		subscriptions.POST("/webhook", handler.HandleWebhook)
 
+		// Inspect and replay stored webhook events
+		subscriptions.GET("/webhook-events", adminGuard(), handler.ListWebhookEvents)
+		subscriptions.GET("/webhook-events/:id", adminGuard(), handler.GetWebhookEvent)
+		subscriptions.POST("/webhook-events/replay", adminGuard(middleware.RoleAdmin, middleware.RoleSupport), handler.ReplayWebhookEvents)
+		subscriptions.POST("/webhook-events/:id/replay", adminGuard(middleware.RoleAdmin, middleware.RoleSupport), handler.ReplayWebhookEvent)
+
 		// Get latest subscription by phone number and app name
-		subscriptions.GET("/latest", handler.GetLatestSubscriptionByPhoneAndApp)
+		subscriptions.GET("/latest", requireAuth, handler.GetLatestSubscriptionByPhoneAndApp)
 
 		// Check if phone number has ever had an authenticated subscription
-		subscriptions.GET("/check-authentication", handler.CheckAuthenticationStatus)
+		subscriptions.GET("/check-authentication", requireAuth, handler.CheckAuthenticationStatus)
 
 		// Get subscription by internal ID
-		subscriptions.GET("/:id", handler.GetSubscription)
+		subscriptions.GET("/:id", requireAuth, handler.GetSubscription)
 
 		// Get subscription by Razorpay subscription ID
-		subscriptions.GET("/razorpay/:razorpay_id", handler.GetSubscriptionByRazorpayID)
+		subscriptions.GET("/razorpay/:razorpay_id", requireAuth, handler.GetSubscriptionByRazorpayID)
 
 		// Cancel subscription
-		subscriptions.POST("/:id/cancel", handler.CancelSubscription)
+		subscriptions.POST("/:id/cancel", requireAuth, handler.CancelSubscription)
 	}
 }
@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"go-backend/internal/apps/razorpay/subscription/models"
+	"go-backend/internal/common/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// webhookEventErrorStatus maps webhook event log errors to HTTP status codes
+func webhookEventErrorStatus(err error) int {
+	switch err.Error() {
+	case "webhook event not found":
+		return http.StatusNotFound
+	case "webhook event already processed":
+		return http.StatusConflict
+	case "from must be before to":
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// ListWebhookEvents handles GET /api/v1/subscriptions/webhook-events
+// Supports status (default failed) and razorpay_subscription_id filters and page/page_size pagination
+func (h *SubscriptionHandler) ListWebhookEvents(c *gin.Context) {
+	// Default pagination values
+	page := 1
+	pageSize := 10
+
+	// Parse page parameter
+	if pageStr := c.Query("page"); pageStr != "" {
+		if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
+			page = p
+		}
+	}
+
+	// Parse page_size parameter
+	if pageSizeStr := c.Query("page_size"); pageSizeStr != "" {
+		if ps, err := strconv.Atoi(pageSizeStr); err == nil && ps > 0 {
+			pageSize = ps
+		}
+	}
+
+	status := c.Query("status")
+	switch models.WebhookEventStatus(status) {
+	case "", models.WebhookEventStatusReceived, models.WebhookEventStatusProcessed, models.WebhookEventStatusStale,
+		models.WebhookEventStatusIgnored, models.WebhookEventStatusFailed:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid status"})
+		return
+	}
+
+	resp, err := h.service.ListWebhookEvents(status, c.Query("razorpay_subscription_id"), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetWebhookEvent handles GET /api/v1/subscriptions/webhook-events/:id
+// Returns the event with its raw payload
+func (h *SubscriptionHandler) GetWebhookEvent(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid webhook event id"})
+		return
+	}
+
+	resp, err := h.service.GetWebhookEvent(id)
+	if err != nil {
+		c.JSON(webhookEventErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
+
+// ReplayWebhookEvent handles POST /api/v1/subscriptions/webhook-events/:id/replay
+// Processes a failed event again; with dry_run it only reports the changes it would make
+func (h *SubscriptionHandler) ReplayWebhookEvent(c *gin.Context) {
+	admin, ok := middleware.GetAdminPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "admin api key required"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid webhook event id"})
+		return
+	}
+
+	// dry_run is optional, so an empty body is accepted
+	var req models.ReplayWebhookEventRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.service.ReplayWebhookEvent(id, req.DryRun, admin)
+	if err != nil {
+		c.JSON(webhookEventErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
+
+// ReplayWebhookEvents handles POST /api/v1/subscriptions/webhook-events/replay
+// Processes the failed events of a time range again, in Razorpay order; with dry_run it only reports the changes
+func (h *SubscriptionHandler) ReplayWebhookEvents(c *gin.Context) {
+	admin, ok := middleware.GetAdminPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "admin api key required"})
+		return
+	}
+
+	var req models.ReplayWebhookEventsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.service.ReplayWebhookEvents(req, admin)
+	if err != nil {
+		c.JSON(webhookEventErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
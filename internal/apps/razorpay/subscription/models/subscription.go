@@ -41,7 +41,9 @@ type Subscription struct {
 	EndAt                  *time.Time         `json:"end_at"`
 	NextChargeAt           *time.Time         `json:"next_charge_at"`
 	ShortURL               string             `gorm:"size:500" json:"short_url"`
+	ExpireBy               *time.Time         `json:"expire_by,omitempty"`        // Checkout link expiry while the subscription is still created
 	Metadata               string             `gorm:"type:jsonb" json:"metadata"` // Additional metadata as JSON
+	LastWebhookEventAt     *time.Time         `json:"-"`                          // Razorpay created_at of the newest webhook event applied
 	CreatedAt              time.Time          `json:"created_at"`
 	UpdatedAt              time.Time          `json:"updated_at"`
 	DeletedAt              gorm.DeletedAt     `gorm:"index" json:"deleted_at,omitempty"`
@@ -56,8 +58,9 @@ func (s *Subscription) BeforeCreate(tx *gorm.DB) error {
 }
 
 // CreateSubscriptionRequest represents the request body for creating a subscription
+// UserID is taken from the authenticated principal
 type CreateSubscriptionRequest struct {
-	UserID               uuid.UUID              `json:"user_id" binding:"required"`
+	UserID               uuid.UUID              `json:"user_id,omitempty"`
 	AppName              string                 `json:"app_name" binding:"required,min=1,max=100"`
 	Phone                string                 `json:"phone" binding:"required,min=10,max=15"`
 	Email                string                 `json:"email" binding:"required,email"`
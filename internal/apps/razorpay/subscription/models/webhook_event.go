@@ -0,0 +1,157 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// WebhookEventStatus represents how far a Razorpay webhook event was processed
+type WebhookEventStatus string
+
+const (
+	WebhookEventStatusReceived  WebhookEventStatus = "received"  // Stored, not processed yet
+	WebhookEventStatusProcessed WebhookEventStatus = "processed" // Applied to its subscription
+	WebhookEventStatusStale     WebhookEventStatus = "stale"     // Older than an event already applied; status and schedule left as they were
+	WebhookEventStatusIgnored   WebhookEventStatus = "ignored"   // Event type this service does not handle
+	WebhookEventStatusFailed    WebhookEventStatus = "failed"    // Verified but failed to apply; a redelivery is processed again
+)
+
+// IsDone reports whether the event needs no further processing, so redeliveries are only acknowledged
+func (s WebhookEventStatus) IsDone() bool {
+	return s == WebhookEventStatusProcessed || s == WebhookEventStatusStale || s == WebhookEventStatusIgnored
+}
+
+// WebhookEvent is a Razorpay webhook delivery, stored once per Razorpay event ID
+type WebhookEvent struct {
+	ID                     uuid.UUID          `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	EventID                string             `gorm:"not null;size:100;uniqueIndex" json:"event_id"` // Razorpay's X-Razorpay-Event-Id
+	EventType              string             `gorm:"size:100" json:"event_type"`
+	RazorpaySubscriptionID string             `gorm:"size:100;index" json:"razorpay_subscription_id,omitempty"`
+	Payload                string             `gorm:"type:text;not null" json:"payload"` // Raw body as delivered
+	Signature              string             `gorm:"size:128" json:"-"`                 // X-Razorpay-Signature, kept so replays are verified again
+	SignatureValid         bool               `gorm:"not null;default:false" json:"signature_valid"`
+	Status                 WebhookEventStatus `gorm:"type:varchar(20);not null;default:'received'" json:"status"`
+	Error                  *string            `gorm:"type:text" json:"error,omitempty"`
+	EventCreatedAt         time.Time          `gorm:"not null" json:"event_created_at"` // Razorpay's created_at; events are applied in this order
+	ProcessedAt            *time.Time         `json:"processed_at,omitempty"`
+	CreatedAt              time.Time          `json:"created_at"`
+	UpdatedAt              time.Time          `json:"updated_at"`
+}
+
+// TableName sets the table name to 'razorpay_webhook_events'
+func (WebhookEvent) TableName() string { return "razorpay_webhook_events" }
+
+// BeforeCreate hook to generate UUID before creating record
+func (e *WebhookEvent) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
+
+// WebhookEventResponse represents a stored webhook event without its payload
+type WebhookEventResponse struct {
+	ID                     uuid.UUID          `json:"id"`
+	EventID                string             `json:"event_id"`
+	EventType              string             `json:"event_type"`
+	RazorpaySubscriptionID string             `json:"razorpay_subscription_id,omitempty"`
+	SignatureValid         bool               `json:"signature_valid"`
+	Status                 WebhookEventStatus `json:"status"`
+	Error                  *string            `json:"error,omitempty"`
+	EventCreatedAt         time.Time          `json:"event_created_at"`
+	ProcessedAt            *time.Time         `json:"processed_at,omitempty"`
+	CreatedAt              time.Time          `json:"created_at"`
+	UpdatedAt              time.Time          `json:"updated_at"`
+}
+
+// ToResponse converts WebhookEvent model to WebhookEventResponse
+func (e *WebhookEvent) ToResponse() WebhookEventResponse {
+	return WebhookEventResponse{
+		ID:                     e.ID,
+		EventID:                e.EventID,
+		EventType:              e.EventType,
+		RazorpaySubscriptionID: e.RazorpaySubscriptionID,
+		SignatureValid:         e.SignatureValid,
+		Status:                 e.Status,
+		Error:                  e.Error,
+		EventCreatedAt:         e.EventCreatedAt,
+		ProcessedAt:            e.ProcessedAt,
+		CreatedAt:              e.CreatedAt,
+		UpdatedAt:              e.UpdatedAt,
+	}
+}
+
+// WebhookEventDetailResponse represents a stored webhook event with its payload
+// The payload is returned as JSON when it parses and as a string otherwise
+type WebhookEventDetailResponse struct {
+	WebhookEventResponse
+	Payload json.RawMessage `json:"payload"`
+}
+
+// ToDetailResponse converts WebhookEvent model to WebhookEventDetailResponse
+func (e *WebhookEvent) ToDetailResponse() WebhookEventDetailResponse {
+	payload := json.RawMessage(e.Payload)
+	if !json.Valid(payload) {
+		payload, _ = json.Marshal(e.Payload)
+	}
+	return WebhookEventDetailResponse{
+		WebhookEventResponse: e.ToResponse(),
+		Payload:              payload,
+	}
+}
+
+// PaginatedWebhookEventsResponse represents paginated webhook events response
+type PaginatedWebhookEventsResponse struct {
+	Data       []WebhookEventResponse `json:"data"`
+	Page       int                    `json:"page"`
+	PageSize   int                    `json:"page_size"`
+	Total      int64                  `json:"total"`
+	TotalPages int                    `json:"total_pages"`
+	NextPage   *int                   `json:"next_page"`
+	PrevPage   *int                   `json:"prev_page"`
+}
+
+// ReplayWebhookEventRequest represents the request body for replaying a single webhook event
+type ReplayWebhookEventRequest struct {
+	DryRun bool `json:"dry_run"`
+}
+
+// ReplayWebhookEventsRequest represents the request body for replaying the failed webhook events of a time range
+// The range [From, To) applies to Razorpay's created_at, and events are replayed in that order
+type ReplayWebhookEventsRequest struct {
+	From   time.Time `json:"from" binding:"required"`
+	To     time.Time `json:"to" binding:"required"`
+	Limit  int       `json:"limit,omitempty"` // Default 100, max 500
+	DryRun bool      `json:"dry_run"`
+}
+
+// SubscriptionFieldChange is a subscription field a webhook event changed, or would change in a dry run
+type SubscriptionFieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// WebhookReplayResult represents the outcome of replaying a webhook event
+// Outcome is the status the event ended with, or would end with in a dry run
+type WebhookReplayResult struct {
+	ID                     uuid.UUID                 `json:"id"`
+	EventID                string                    `json:"event_id"`
+	EventType              string                    `json:"event_type"`
+	RazorpaySubscriptionID string                    `json:"razorpay_subscription_id,omitempty"`
+	DryRun                 bool                      `json:"dry_run"`
+	Outcome                WebhookEventStatus        `json:"outcome"`
+	Error                  *string                   `json:"error,omitempty"`
+	Changes                []SubscriptionFieldChange `json:"changes"`
+}
+
+// ReplayWebhookEventsResponse represents the outcome of replaying a time range of webhook events
+type ReplayWebhookEventsResponse struct {
+	DryRun   bool                  `json:"dry_run"`
+	Replayed int                   `json:"replayed"`
+	Failed   int                   `json:"failed"`
+	Results  []WebhookReplayResult `json:"results"`
+}
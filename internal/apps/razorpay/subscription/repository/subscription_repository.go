@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"go-backend/internal/apps/razorpay/subscription/models"
 
 	"github.com/google/uuid"
@@ -19,7 +21,8 @@ type SubscriptionRepository interface {
 	UpdateStatus(id uuid.UUID, status models.SubscriptionStatus) error
 	FindAll(limit, offset int) ([]models.Subscription, int64, error)
 	FindByAppName(appName string, limit, offset int) ([]models.Subscription, int64, error)
-	HasAuthenticatedSubscriptionByPhone(phone string, appName string) (bool, error)
+	HasAuthenticatedSubscriptionByPhone(userID uuid.UUID, phone string, appName string) (bool, error)
+	ExpireAbandoned(now time.Time, defaultTTL time.Duration) (int64, error)
 }
 
 // subscriptionRepository implements SubscriptionRepository interface
@@ -148,11 +151,11 @@ func (r *subscriptionRepository) FindByAppName(appName string, limit, offset int
 	return subscriptions, total, nil
 }
 
-// HasAuthenticatedSubscriptionByPhone checks if a phone number has ever had an authenticated subscription
-func (r *subscriptionRepository) HasAuthenticatedSubscriptionByPhone(phone string, appName string) (bool, error) {
+// HasAuthenticatedSubscriptionByPhone checks if a user's phone number has ever had an authenticated subscription
+func (r *subscriptionRepository) HasAuthenticatedSubscriptionByPhone(userID uuid.UUID, phone string, appName string) (bool, error) {
 	var count int64
 	query := r.db.Model(&models.Subscription{}).
-		Where("phone = ? AND metadata::jsonb @> '{\"authenticated\": true}'", phone)
+		Where("user_id = ? AND phone = ? AND metadata::jsonb @> '{\"authenticated\": true}'", userID, phone)
 
 	if appName != "" {
 		query = query.Where("app_name = ?", appName)
@@ -164,3 +167,13 @@ func (r *subscriptionRepository) HasAuthenticatedSubscriptionByPhone(phone strin
 	}
 	return count > 0, nil
 }
+
+// ExpireAbandoned marks subscriptions still in created after their checkout expiry as expired
+// Rows created before expire_by was recorded fall back to created_at + defaultTTL
+func (r *subscriptionRepository) ExpireAbandoned(now time.Time, defaultTTL time.Duration) (int64, error) {
+	result := r.db.Model(&models.Subscription{}).
+		Where("status = ?", models.SubscriptionStatusCreated).
+		Where("COALESCE(expire_by, created_at + make_interval(secs => ?)) < ?", defaultTTL.Seconds(), now).
+		Update("status", models.SubscriptionStatusExpired)
+	return result.RowsAffected, result.Error
+}
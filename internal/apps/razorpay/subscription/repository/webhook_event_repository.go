@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"hash/fnv"
+	"time"
+
+	"go-backend/internal/apps/razorpay/subscription/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// WebhookEventRepository defines data operations for the Razorpay webhook event log
+type WebhookEventRepository interface {
+	CreateIfAbsent(event *models.WebhookEvent) (bool, error)
+	FindByID(id uuid.UUID) (*models.WebhookEvent, error)
+	FindByEventID(eventID string) (*models.WebhookEvent, error)
+	FindPaginated(status, razorpaySubID string, page, pageSize int) ([]models.WebhookEvent, int64, error)
+	FindReplayable(from, to time.Time, limit int) ([]models.WebhookEvent, error)
+	Update(event *models.WebhookEvent) error
+	LockSubscription(razorpaySubID string) error
+	Transaction(fn func(events WebhookEventRepository, subscriptions SubscriptionRepository) error) error
+}
+
+// webhookEventRepository implements WebhookEventRepository
+type webhookEventRepository struct {
+	db *gorm.DB
+}
+
+// NewWebhookEventRepository creates a new instance of WebhookEventRepository
+func NewWebhookEventRepository(db *gorm.DB) WebhookEventRepository {
+	return &webhookEventRepository{db: db}
+}
+
+// CreateIfAbsent stores an event unless one with the same event ID exists
+// Returns false when the event was already stored
+func (r *webhookEventRepository) CreateIfAbsent(event *models.WebhookEvent) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "event_id"}},
+		DoNothing: true,
+	}).Create(event)
+	return result.RowsAffected > 0, result.Error
+}
+
+// FindByID retrieves an event by its ID
+func (r *webhookEventRepository) FindByID(id uuid.UUID) (*models.WebhookEvent, error) {
+	var event models.WebhookEvent
+	if err := r.db.First(&event, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// FindByEventID retrieves an event by Razorpay's event ID
+func (r *webhookEventRepository) FindByEventID(eventID string) (*models.WebhookEvent, error) {
+	var event models.WebhookEvent
+	if err := r.db.Where("event_id = ?", eventID).First(&event).Error; err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// FindPaginated retrieves events with pagination, newest first, with optional status and subscription filters
+func (r *webhookEventRepository) FindPaginated(status, razorpaySubID string, page, pageSize int) ([]models.WebhookEvent, int64, error) {
+	var events []models.WebhookEvent
+	var total int64
+
+	query := r.db.Model(&models.WebhookEvent{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if razorpaySubID != "" {
+		query = query.Where("razorpay_subscription_id = ?", razorpaySubID)
+	}
+
+	// Get total count
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Calculate offset
+	offset := (page - 1) * pageSize
+
+	// Get paginated results
+	if err := query.Order("created_at DESC").Offset(offset).Limit(pageSize).Find(&events).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return events, total, nil
+}
+
+// FindReplayable retrieves up to limit failed or unfinished events whose Razorpay created_at is in [from, to),
+// in the order Razorpay created them
+func (r *webhookEventRepository) FindReplayable(from, to time.Time, limit int) ([]models.WebhookEvent, error) {
+	var events []models.WebhookEvent
+	err := r.db.Where("status IN ?", []models.WebhookEventStatus{models.WebhookEventStatusFailed, models.WebhookEventStatusReceived}).
+		Where("event_created_at >= ? AND event_created_at < ?", from, to).
+		Order("event_created_at ASC, created_at ASC").
+		Limit(limit).
+		Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// Update updates an existing event
+func (r *webhookEventRepository) Update(event *models.WebhookEvent) error {
+	return r.db.Save(event).Error
+}
+
+// LockSubscription takes a transaction-scoped advisory lock for a Razorpay subscription
+// Webhook events of the same subscription are applied one at a time
+func (r *webhookEventRepository) LockSubscription(razorpaySubID string) error {
+	h := fnv.New64a()
+	h.Write([]byte("razorpay_webhook:" + razorpaySubID))
+	return r.db.Exec("SELECT pg_advisory_xact_lock(?)", int64(h.Sum64())).Error
+}
+
+// Transaction runs fn with event and subscription repositories bound to a single database transaction
+func (r *webhookEventRepository) Transaction(fn func(events WebhookEventRepository, subscriptions SubscriptionRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&webhookEventRepository{db: tx}, &subscriptionRepository{db: tx})
+	})
+}
@@ -15,6 +15,7 @@ import (
 	"go-backend/internal/apps/razorpay/config/repository"
 	"go-backend/internal/apps/razorpay/subscription/models"
 	razorpayRepository "go-backend/internal/apps/razorpay/subscription/repository"
+	"go-backend/internal/common/middleware"
 	"go-backend/pkg/utils"
 
 	"github.com/google/uuid"
@@ -26,18 +27,27 @@ import (
 type SubscriptionService interface {
 	CreateCheckoutURL(req models.CreateSubscriptionRequest) (*models.CheckoutURLResponse, error)
 	VerifyPayment(req models.VerifyPaymentRequest) (*models.SubscriptionResponse, error)
-	HandleWebhook(payload []byte, signature string) error
+	HandleWebhook(payload []byte, signature, eventID string) (bool, error)
 	GetSubscriptionByID(id uuid.UUID) (*models.SubscriptionResponse, error)
 	GetSubscriptionByRazorpayID(razorpaySubID string) (*models.SubscriptionResponse, error)
 	GetLatestSubscriptionByPhoneAndApp(phone string, appName string) (*models.SubscriptionResponse, error)
 	CancelSubscription(id uuid.UUID) error
-	CheckAuthenticationStatus(phone string, appName string) (*models.CheckAuthenticationStatusResponse, error)
+	CheckAuthenticationStatus(userID uuid.UUID, phone string, appName string) (*models.CheckAuthenticationStatusResponse, error)
+	ExpireAbandonedCheckouts() (int64, error)
+	ListWebhookEvents(status, razorpaySubID string, page, pageSize int) (*models.PaginatedWebhookEventsResponse, error)
+	GetWebhookEvent(id uuid.UUID) (*models.WebhookEventDetailResponse, error)
+	ReplayWebhookEvent(id uuid.UUID, dryRun bool, admin *middleware.AdminPrincipal) (*models.WebhookReplayResult, error)
+	ReplayWebhookEvents(req models.ReplayWebhookEventsRequest, admin *middleware.AdminPrincipal) (*models.ReplayWebhookEventsResponse, error)
 }
 
+// checkoutLinkTTL is how long a checkout link stays valid (Razorpay's expire_by)
+const checkoutLinkTTL = 7 * 24 * time.Hour
+
 // subscriptionService implements SubscriptionService interface
 type subscriptionService struct {
 	repo        razorpayRepository.SubscriptionRepository
 	configRepo  repository.RazorpayConfigRepository
+	eventRepo   razorpayRepository.WebhookEventRepository
 	clientCache map[string]*razorpay.Client // Cache Razorpay clients by app_name:environment
 	cacheMutex  sync.RWMutex                // Protect concurrent access to cache
 }
@@ -46,10 +56,12 @@ type subscriptionService struct {
 func NewSubscriptionService(
 	repo razorpayRepository.SubscriptionRepository,
 	configRepo repository.RazorpayConfigRepository,
+	eventRepo razorpayRepository.WebhookEventRepository,
 ) SubscriptionService {
 	return &subscriptionService{
 		repo:        repo,
 		configRepo:  configRepo,
+		eventRepo:   eventRepo,
 		clientCache: make(map[string]*razorpay.Client),
 	}
 }
@@ -205,8 +217,8 @@ func (s *subscriptionService) CreateCheckoutURL(req models.CreateSubscriptionReq
 	}
 
 	// Set expire_by to 7 days from now for the checkout link
-	expireBy := time.Now().Add(7 * 24 * time.Hour).Unix()
-	subscriptionData["expire_by"] = expireBy
+	expireBy := time.Now().Add(checkoutLinkTTL)
+	subscriptionData["expire_by"] = expireBy.Unix()
 
 	// Set start_at based on firstChargeDelayDays
 	// If delay is 0, we want immediate first charge - but Razorpay requires start_at in future
@@ -298,6 +310,7 @@ func (s *subscriptionService) CreateCheckoutURL(req models.CreateSubscriptionReq
 		Frequency:              frequency,
 		TotalCount:             req.TotalCount,
 		ShortURL:               shortURL,
+		ExpireBy:               &expireBy,
 		Metadata:               metadataJSON,
 	}
 
@@ -367,84 +380,328 @@ func (s *subscriptionService) VerifyPayment(req models.VerifyPaymentRequest) (*m
 	return &response, nil
 }
 
-// HandleWebhook handles Razorpay webhook events
-func (s *subscriptionService) HandleWebhook(payload []byte, signature string) error {
-	// Parse webhook payload first to extract subscription info
-	var event map[string]interface{}
-	if err := json.Unmarshal(payload, &event); err != nil {
-		return fmt.Errorf("failed to parse webhook payload: %w", err)
+// webhookEnvelope is the part of a Razorpay webhook payload used to route, order and apply it
+type webhookEnvelope struct {
+	Event     string `json:"event"`
+	CreatedAt int64  `json:"created_at"`
+	Payload   struct {
+		Subscription *webhookEntity `json:"subscription"`
+		Payment      *webhookEntity `json:"payment"`
+	} `json:"payload"`
+}
+
+// webhookEntity wraps an entity in a Razorpay webhook payload
+type webhookEntity struct {
+	Entity map[string]interface{} `json:"entity"`
+}
+
+// subscriptionEntity returns the subscription entity of the payload, if any
+func (e *webhookEnvelope) subscriptionEntity() map[string]interface{} {
+	if e.Payload.Subscription == nil {
+		return nil
+	}
+	return e.Payload.Subscription.Entity
+}
+
+// HandleWebhook verifies a Razorpay webhook event, stores it and applies it to its subscription
+// Deliveries whose signature cannot be verified are rejected without being stored, so they cannot fill
+// the event log or claim the event ID of a genuine delivery
+// Events are stored once per event ID: redeliveries of a handled event are only acknowledged
+// (duplicate is true) while failed ones are processed again
+// Events of a subscription are applied one at a time in Razorpay's created_at order;
+// an event older than one already applied does not change the subscription's status or schedule
+func (s *subscriptionService) HandleWebhook(payload []byte, signature, eventID string) (bool, error) {
+	var envelope webhookEnvelope
+	if err := json.Unmarshal(payload, &envelope); err != nil {
+		return false, errors.New("invalid webhook payload")
 	}
 
-	eventType := event["event"].(string)
-	payloadData := event["payload"].(map[string]interface{})
-	fmt.Printf("Webhook event received: %s\n", eventType)
+	// Deliveries without an event ID are identified by their body
+	if eventID == "" {
+		sum := sha256.Sum256(payload)
+		eventID = "body:" + hex.EncodeToString(sum[:])
+	}
 
-	// Extract razorpay_subscription_id to fetch client configuration
-	var razorpaySubID string
-	if subWrap, ok := payloadData["subscription"].(map[string]interface{}); ok {
-		if entity, ok := subWrap["entity"].(map[string]interface{}); ok {
-			if id, ok := entity["id"].(string); ok {
-				razorpaySubID = id
-				status, _ := entity["status"].(string)
-				fmt.Printf("Subscription entity: id=%s status=%s\n", id, status)
-			}
+	eventAt := time.Now()
+	if envelope.CreatedAt > 0 {
+		eventAt = time.Unix(envelope.CreatedAt, 0)
+	}
+	razorpaySubID, _ := envelope.subscriptionEntity()["id"].(string)
+	fmt.Printf("Webhook event received: %s (%s)\n", envelope.Event, eventID)
+
+	event := &models.WebhookEvent{
+		EventID:                eventID,
+		EventType:              envelope.Event,
+		RazorpaySubscriptionID: razorpaySubID,
+		Payload:                string(payload),
+		Signature:              signature,
+		Status:                 models.WebhookEventStatusReceived,
+		EventCreatedAt:         eventAt,
+	}
+	if err := s.verifyWebhookEvent(event, &envelope); err != nil {
+		return false, err
+	}
+
+	created, err := s.eventRepo.CreateIfAbsent(event)
+	if err != nil {
+		return false, err
+	}
+	if !created {
+		existing, err := s.eventRepo.FindByEventID(eventID)
+		if err != nil {
+			return false, err
+		}
+		if existing.Status.IsDone() {
+			fmt.Printf("Webhook event %s already %s, acknowledging duplicate\n", eventID, existing.Status)
+			return true, nil
 		}
+
+		// A failed or interrupted event is processed again from this delivery
+		existing.EventType = event.EventType
+		existing.RazorpaySubscriptionID = event.RazorpaySubscriptionID
+		existing.Payload = event.Payload
+		existing.Signature = event.Signature
+		existing.SignatureValid = event.SignatureValid
+		existing.EventCreatedAt = event.EventCreatedAt
+		event = existing
 	}
 
-	if razorpaySubID == "" {
-		return errors.New("subscription ID not found in webhook payload")
+	application, err := s.commitWebhookEvent(event, envelope.subscriptionEntity())
+	if err != nil {
+		return false, s.failWebhookEvent(event, err)
 	}
+	return application.duplicate, nil
+}
 
-	// Fetch subscription to get razorpay_config_id
-	subscription, err := s.repo.FindByRazorpaySubscriptionID(razorpaySubID)
+// webhookApplication is the outcome of applying a webhook event to its subscription
+type webhookApplication struct {
+	outcome   models.WebhookEventStatus
+	before    models.Subscription
+	after     *models.Subscription // Nil if the event was not applied by this call
+	duplicate bool                 // Another delivery of the event was handled first
+}
+
+// processWebhookEvent verifies a stored event and applies it to its subscription
+// A failure is recorded on the event, except in a dry run, which applies the event to an unsaved copy;
+// previews carries those copies between the events of a dry run
+func (s *subscriptionService) processWebhookEvent(event *models.WebhookEvent, dryRun bool, previews map[string]*models.Subscription) (*webhookApplication, error) {
+	fail := func(err error) error {
+		if dryRun {
+			return err
+		}
+		return s.failWebhookEvent(event, err)
+	}
+
+	var envelope webhookEnvelope
+	if err := json.Unmarshal([]byte(event.Payload), &envelope); err != nil {
+		return nil, fail(errors.New("invalid webhook payload"))
+	}
+	if err := s.verifyWebhookEvent(event, &envelope); err != nil {
+		return nil, fail(err)
+	}
+
+	if dryRun {
+		return s.previewWebhookEvent(event, envelope.subscriptionEntity(), previews)
+	}
+	application, err := s.commitWebhookEvent(event, envelope.subscriptionEntity())
 	if err != nil {
-		return fmt.Errorf("failed to find subscription: %w", err)
+		return nil, fail(err)
 	}
+	return application, nil
+}
 
-	// Get razorpay config
-	config, err := s.configRepo.FindByID(subscription.RazorpayConfigID)
+// verifyWebhookEvent checks an event's signature against the webhook secret of its subscription's config,
+// or against every active config's secret if the subscription has not been created yet
+func (s *subscriptionService) verifyWebhookEvent(event *models.WebhookEvent, envelope *webhookEnvelope) error {
+	razorpaySubID := event.RazorpaySubscriptionID
+	if razorpaySubID == "" {
+		return errors.New("subscription ID not found in webhook payload")
+	}
+	status, _ := envelope.subscriptionEntity()["status"].(string)
+	fmt.Printf("Subscription entity: id=%s status=%s\n", razorpaySubID, status)
+
+	secrets, err := s.webhookSecrets(razorpaySubID)
 	if err != nil {
-		return fmt.Errorf("failed to find razorpay config: %w", err)
+		return err
 	}
 
-	// Verify webhook signature using config's webhook secret
-	if !s.verifyWebhookSignature(payload, signature, config.RazorpayWebhookSecret) {
-		fmt.Printf("Webhook signature verification failed. signature=%s\n", signature)
+	event.SignatureValid = false
+	for _, secret := range secrets {
+		if s.verifyWebhookSignature([]byte(event.Payload), event.Signature, secret) {
+			event.SignatureValid = true
+			break
+		}
+	}
+	if !event.SignatureValid {
+		fmt.Printf("Webhook signature verification failed for subscription %s\n", razorpaySubID)
 		return errors.New("invalid webhook signature")
 	}
 
 	// Log payment info if present
-	if payWrap, ok := payloadData["payment"].(map[string]interface{}); ok {
-		if entity, ok := payWrap["entity"].(map[string]interface{}); ok {
-			pid, _ := entity["id"].(string)
-			pstatus, _ := entity["status"].(string)
-			fmt.Printf("Payment entity: id=%s status=%s\n", pid, pstatus)
+	if envelope.Payload.Payment != nil {
+		pid, _ := envelope.Payload.Payment.Entity["id"].(string)
+		pstatus, _ := envelope.Payload.Payment.Entity["status"].(string)
+		fmt.Printf("Payment entity: id=%s status=%s\n", pid, pstatus)
+	}
+	return nil
+}
+
+// webhookSecretsPageSize is the number of configs loaded per page when no subscription row exists yet
+const webhookSecretsPageSize = 100
+
+// webhookSecrets returns the webhook secrets an event of the given subscription may be signed with
+// An event can arrive before checkout has stored the subscription, so every active config is a candidate then
+func (s *subscriptionService) webhookSecrets(razorpaySubID string) ([]string, error) {
+	subscription, err := s.repo.FindByRazorpaySubscriptionID(razorpaySubID)
+	if err == nil {
+		config, err := s.configRepo.FindByID(subscription.RazorpayConfigID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find razorpay config: %w", err)
+		}
+		return []string{config.RazorpayWebhookSecret}, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to find subscription: %w", err)
+	}
+
+	var secrets []string
+	for page := 1; ; page++ {
+		configs, total, err := s.configRepo.FindAll(page, webhookSecretsPageSize, true)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list razorpay configs: %w", err)
+		}
+		for _, config := range configs {
+			if config.RazorpayWebhookSecret != "" {
+				secrets = append(secrets, config.RazorpayWebhookSecret)
+			}
+		}
+		if len(configs) == 0 || int64(page*webhookSecretsPageSize) >= total {
+			return secrets, nil
+		}
+	}
+}
+
+// commitWebhookEvent applies a verified event under its subscription's lock and marks it handled
+func (s *subscriptionService) commitWebhookEvent(event *models.WebhookEvent, entity map[string]interface{}) (*webhookApplication, error) {
+	razorpaySubID := event.RazorpaySubscriptionID
+	application := &webhookApplication{}
+	err := s.eventRepo.Transaction(func(events razorpayRepository.WebhookEventRepository, subscriptions razorpayRepository.SubscriptionRepository) error {
+		if err := events.LockSubscription(razorpaySubID); err != nil {
+			return err
+		}
+
+		// Another delivery of the event may have been applied while waiting for the lock
+		current, err := events.FindByEventID(event.EventID)
+		if err != nil {
+			return err
+		}
+		if current.Status.IsDone() {
+			application.outcome = current.Status
+			application.duplicate = true
+			return nil
+		}
+
+		subscription, err := subscriptions.FindByRazorpaySubscriptionID(razorpaySubID)
+		if err != nil {
+			return fmt.Errorf("failed to find subscription: %w", err)
 		}
+
+		application.before = *subscription
+		application.outcome = s.applyWebhookEvent(subscription, event.EventType, entity, event.EventCreatedAt)
+		application.after = subscription
+		if application.outcome != models.WebhookEventStatusIgnored {
+			if err := subscriptions.Update(subscription); err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		event.Status = application.outcome
+		event.Error = nil
+		event.ProcessedAt = &now
+		return events.Update(event)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if application.outcome == models.WebhookEventStatusStale && !application.duplicate {
+		fmt.Printf("Webhook event %s (%s) is older than the last applied event of subscription %s\n", event.EventID, event.EventType, razorpaySubID)
+	}
+	return application, nil
+}
+
+// previewWebhookEvent applies a verified event to a copy of its subscription without writing anything
+// previews holds the copies by Razorpay subscription ID so later events build on earlier ones
+func (s *subscriptionService) previewWebhookEvent(event *models.WebhookEvent, entity map[string]interface{}, previews map[string]*models.Subscription) (*webhookApplication, error) {
+	subscription, ok := previews[event.RazorpaySubscriptionID]
+	if !ok {
+		loaded, err := s.repo.FindByRazorpaySubscriptionID(event.RazorpaySubscriptionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find subscription: %w", err)
+		}
+		subscription = loaded
+		previews[event.RazorpaySubscriptionID] = subscription
+	}
+
+	application := &webhookApplication{before: *subscription}
+	application.outcome = s.applyWebhookEvent(subscription, event.EventType, entity, event.EventCreatedAt)
+	application.after = subscription
+	return application, nil
+}
+
+// failWebhookEvent records why an event could not be processed and returns err
+func (s *subscriptionService) failWebhookEvent(event *models.WebhookEvent, err error) error {
+	message := err.Error()
+	event.Status = models.WebhookEventStatusFailed
+	event.Error = &message
+	event.ProcessedAt = nil
+	if updateErr := s.eventRepo.Update(event); updateErr != nil {
+		fmt.Printf("Failed to record webhook event %s failure: %v\n", event.EventID, updateErr)
+	}
+	return err
+}
+
+// applyWebhookEvent applies an event to a subscription and returns the event's resulting status
+// Events older than the last applied one are stale: they only record authentication details
+func (s *subscriptionService) applyWebhookEvent(subscription *models.Subscription, eventType string, entity map[string]interface{}, eventAt time.Time) models.WebhookEventStatus {
+	last := subscription.LastWebhookEventAt
+	if last != nil && eventAt.Before(*last) {
+		if eventType == "subscription.authenticated" {
+			recordAuthentication(subscription, entity)
+		}
+		return models.WebhookEventStatusStale
 	}
+	// Razorpay timestamps are in seconds, so events can share one with the last applied event
+	tied := last != nil && eventAt.Equal(*last)
+
 	// Handle different event types
 	switch eventType {
 	case "subscription.authenticated":
-		return s.handleSubscriptionAuthenticated(payloadData)
+		s.handleSubscriptionAuthenticated(subscription, entity, tied)
 	case "subscription.activated":
-		return s.handleSubscriptionActivated(payloadData)
+		s.handleSubscriptionActivated(subscription, entity)
 	case "subscription.charged":
-		return s.handleSubscriptionCharged(payloadData)
+		s.handleSubscriptionCharged(subscription, entity, tied)
 	case "subscription.pending":
-		return s.handleSubscriptionPending(payloadData)
+		s.handleSubscriptionPending(subscription, tied)
 	case "subscription.halted":
-		return s.handleSubscriptionHalted(payloadData)
+		s.handleSubscriptionHalted(subscription, tied)
 	case "subscription.cancelled":
-		return s.handleSubscriptionCancelled(payloadData)
+		s.handleSubscriptionCancelled(subscription, entity, tied)
 	case "subscription.completed":
-		return s.handleSubscriptionCompleted(payloadData)
+		s.handleSubscriptionCompleted(subscription, entity, tied)
 	case "subscription.paused":
-		return s.handleSubscriptionPaused(payloadData)
+		s.handleSubscriptionPaused(subscription, tied)
 	case "subscription.resumed":
-		return s.handleSubscriptionResumed(payloadData)
+		s.handleSubscriptionResumed(subscription, tied)
 	default:
-		// Log unknown event type but don't error
-		return nil
+		// Unknown event types are stored but not applied
+		return models.WebhookEventStatusIgnored
 	}
+
+	subscription.LastWebhookEventAt = &eventAt
+	return models.WebhookEventStatusProcessed
 }
 
 // GetSubscriptionByID retrieves a subscription by its ID
@@ -538,35 +795,35 @@ func (s *subscriptionService) verifyWebhookSignature(payload []byte, signature,
 	return hmac.Equal([]byte(signature), []byte(expectedMAC))
 }
 
-// handleSubscriptionAuthenticated handles subscription.authenticated event
-func (s *subscriptionService) handleSubscriptionAuthenticated(payload map[string]interface{}) error {
-	subscriptionEntity := payload["subscription"].(map[string]interface{})["entity"].(map[string]interface{})
-	razorpaySubID := subscriptionEntity["id"].(string)
-
-	subscription, err := s.repo.FindByRazorpaySubscriptionID(razorpaySubID)
-	if err != nil {
-		return err
+// subscriptionStatusRank orders statuses along the subscription lifecycle
+func subscriptionStatusRank(status models.SubscriptionStatus) int {
+	switch status {
+	case models.SubscriptionStatusAuthenticated:
+		return 1
+	case models.SubscriptionStatusActive, models.SubscriptionStatusPaused:
+		return 2
+	case models.SubscriptionStatusCancelled, models.SubscriptionStatusCompleted, models.SubscriptionStatusExpired:
+		return 3
+	default:
+		return 0
 	}
+}
 
-	// Ignore authentication event if subscription is already cancelled
-	if subscription.Status == models.SubscriptionStatusCancelled {
-		fmt.Printf("[handleSubscriptionAuthenticated] Ignoring authentication event for cancelled subscription: %s\n", razorpaySubID)
-		return nil
+// setSubscriptionStatus moves a subscription to status
+// An event tied with the last applied one may only move it forward, as their real order is unknown
+func setSubscriptionStatus(subscription *models.Subscription, status models.SubscriptionStatus, tied bool) {
+	if tied && subscriptionStatusRank(status) < subscriptionStatusRank(subscription.Status) {
+		fmt.Printf("Keeping subscription %s %s instead of %s from a simultaneous event\n", subscription.RazorpaySubscriptionID, subscription.Status, status)
+		return
 	}
+	subscription.Status = status
+}
 
-	// Persist customer_id if present, and any timing hints
+// recordAuthentication persists the customer ID and the authentication marker in metadata (idempotent)
+func recordAuthentication(subscription *models.Subscription, subscriptionEntity map[string]interface{}) {
 	if custID, ok := subscriptionEntity["customer_id"].(string); ok {
 		subscription.RazorpayCustomerID = custID
 	}
-	if startAt, ok := subscriptionEntity["start_at"].(float64); ok {
-		t := time.Unix(int64(startAt), 0)
-		subscription.StartAt = &t
-	}
-	if chargeAt, ok := subscriptionEntity["charge_at"].(float64); ok {
-		t := time.Unix(int64(chargeAt), 0)
-		subscription.NextChargeAt = &t
-	}
-	// Record authentication marker in metadata (idempotent)
 	meta := map[string]interface{}{}
 	_ = json.Unmarshal([]byte(subscription.Metadata), &meta)
 	if auth, ok := meta["authenticated"].(bool); !ok || !auth {
@@ -577,44 +834,44 @@ func (s *subscriptionService) handleSubscriptionAuthenticated(payload map[string
 		b, _ := json.Marshal(meta)
 		subscription.Metadata = string(b)
 	}
+}
+
+// handleSubscriptionAuthenticated handles subscription.authenticated event
+func (s *subscriptionService) handleSubscriptionAuthenticated(subscription *models.Subscription, subscriptionEntity map[string]interface{}, tied bool) {
+	// Ignore authentication event if subscription is already cancelled
+	if subscription.Status == models.SubscriptionStatusCancelled {
+		fmt.Printf("[handleSubscriptionAuthenticated] Ignoring authentication event for cancelled subscription: %s\n", subscription.RazorpaySubscriptionID)
+		return
+	}
+
+	// Persist customer_id and the authentication marker, and any timing hints
+	recordAuthentication(subscription, subscriptionEntity)
+	if startAt, ok := subscriptionEntity["start_at"].(float64); ok {
+		t := time.Unix(int64(startAt), 0)
+		subscription.StartAt = &t
+	}
+	if chargeAt, ok := subscriptionEntity["charge_at"].(float64); ok {
+		t := time.Unix(int64(chargeAt), 0)
+		subscription.NextChargeAt = &t
+	}
 
 	// Update status to authenticated from Razorpay webhook
 	if rzpStatus, ok := subscriptionEntity["status"].(string); ok {
-		subscription.Status = models.SubscriptionStatus(rzpStatus)
+		setSubscriptionStatus(subscription, models.SubscriptionStatus(rzpStatus), tied)
 	}
-
-	return s.repo.Update(subscription)
 }
 
 // handleSubscriptionActivated handles subscription.activated event
-func (s *subscriptionService) handleSubscriptionActivated(payload map[string]interface{}) error {
-	subscriptionEntity := payload["subscription"].(map[string]interface{})["entity"].(map[string]interface{})
-	razorpaySubID := subscriptionEntity["id"].(string)
-
-	subscription, err := s.repo.FindByRazorpaySubscriptionID(razorpaySubID)
-	if err != nil {
-		return err
-	}
-
+func (s *subscriptionService) handleSubscriptionActivated(subscription *models.Subscription, subscriptionEntity map[string]interface{}) {
 	// Do not mark active here. Only set start_at to track schedule.
 	if startAt, ok := subscriptionEntity["start_at"].(float64); ok {
 		t := time.Unix(int64(startAt), 0)
 		subscription.StartAt = &t
 	}
-
-	return s.repo.Update(subscription)
 }
 
 // handleSubscriptionCharged handles subscription.charged event
-func (s *subscriptionService) handleSubscriptionCharged(payload map[string]interface{}) error {
-	subscriptionEntity := payload["subscription"].(map[string]interface{})["entity"].(map[string]interface{})
-	razorpaySubID := subscriptionEntity["id"].(string)
-
-	subscription, err := s.repo.FindByRazorpaySubscriptionID(razorpaySubID)
-	if err != nil {
-		return err
-	}
-
+func (s *subscriptionService) handleSubscriptionCharged(subscription *models.Subscription, subscriptionEntity map[string]interface{}, tied bool) {
 	// Update next charge date if available
 	if chargeAt, ok := subscriptionEntity["charge_at"].(float64); ok {
 		t := time.Unix(int64(chargeAt), 0)
@@ -622,103 +879,51 @@ func (s *subscriptionService) handleSubscriptionCharged(payload map[string]inter
 	}
 	// Mark active on first successful charge
 	if subscription.Status == models.SubscriptionStatusCreated || subscription.Status == models.SubscriptionStatusAuthenticated {
-		subscription.Status = models.SubscriptionStatusActive
+		setSubscriptionStatus(subscription, models.SubscriptionStatusActive, tied)
 	}
-
-	return s.repo.Update(subscription)
 }
 
 // handleSubscriptionPending handles subscription.pending event
-func (s *subscriptionService) handleSubscriptionPending(payload map[string]interface{}) error {
-	subscriptionEntity := payload["subscription"].(map[string]interface{})["entity"].(map[string]interface{})
-	razorpaySubID := subscriptionEntity["id"].(string)
-
-	return s.repo.UpdateStatus(uuid.MustParse(razorpaySubID), models.SubscriptionStatusCreated)
+func (s *subscriptionService) handleSubscriptionPending(subscription *models.Subscription, tied bool) {
+	setSubscriptionStatus(subscription, models.SubscriptionStatusCreated, tied)
 }
 
 // handleSubscriptionHalted handles subscription.halted event
-func (s *subscriptionService) handleSubscriptionHalted(payload map[string]interface{}) error {
-	subscriptionEntity := payload["subscription"].(map[string]interface{})["entity"].(map[string]interface{})
-	razorpaySubID := subscriptionEntity["id"].(string)
-
-	subscription, err := s.repo.FindByRazorpaySubscriptionID(razorpaySubID)
-	if err != nil {
-		return err
-	}
-
-	subscription.Status = models.SubscriptionStatusExpired
-	return s.repo.Update(subscription)
+func (s *subscriptionService) handleSubscriptionHalted(subscription *models.Subscription, tied bool) {
+	setSubscriptionStatus(subscription, models.SubscriptionStatusExpired, tied)
 }
 
 // handleSubscriptionCancelled handles subscription.cancelled event
-func (s *subscriptionService) handleSubscriptionCancelled(payload map[string]interface{}) error {
-	subscriptionEntity := payload["subscription"].(map[string]interface{})["entity"].(map[string]interface{})
-	razorpaySubID := subscriptionEntity["id"].(string)
-
-	subscription, err := s.repo.FindByRazorpaySubscriptionID(razorpaySubID)
-	if err != nil {
-		return err
-	}
-
-	subscription.Status = models.SubscriptionStatusCancelled
+func (s *subscriptionService) handleSubscriptionCancelled(subscription *models.Subscription, subscriptionEntity map[string]interface{}, tied bool) {
+	setSubscriptionStatus(subscription, models.SubscriptionStatusCancelled, tied)
 	if endAt, ok := subscriptionEntity["end_at"].(float64); ok {
 		t := time.Unix(int64(endAt), 0)
 		subscription.EndAt = &t
 	}
-
-	return s.repo.Update(subscription)
 }
 
 // handleSubscriptionCompleted handles subscription.completed event
-func (s *subscriptionService) handleSubscriptionCompleted(payload map[string]interface{}) error {
-	subscriptionEntity := payload["subscription"].(map[string]interface{})["entity"].(map[string]interface{})
-	razorpaySubID := subscriptionEntity["id"].(string)
-
-	subscription, err := s.repo.FindByRazorpaySubscriptionID(razorpaySubID)
-	if err != nil {
-		return err
-	}
-
-	subscription.Status = models.SubscriptionStatusCompleted
+func (s *subscriptionService) handleSubscriptionCompleted(subscription *models.Subscription, subscriptionEntity map[string]interface{}, tied bool) {
+	setSubscriptionStatus(subscription, models.SubscriptionStatusCompleted, tied)
 	if endAt, ok := subscriptionEntity["ended_at"].(float64); ok {
 		t := time.Unix(int64(endAt), 0)
 		subscription.EndAt = &t
 	}
-
-	return s.repo.Update(subscription)
 }
 
 // handleSubscriptionPaused handles subscription.paused event
-func (s *subscriptionService) handleSubscriptionPaused(payload map[string]interface{}) error {
-	subscriptionEntity := payload["subscription"].(map[string]interface{})["entity"].(map[string]interface{})
-	razorpaySubID := subscriptionEntity["id"].(string)
-
-	subscription, err := s.repo.FindByRazorpaySubscriptionID(razorpaySubID)
-	if err != nil {
-		return err
-	}
-
-	subscription.Status = models.SubscriptionStatusPaused
-	return s.repo.Update(subscription)
+func (s *subscriptionService) handleSubscriptionPaused(subscription *models.Subscription, tied bool) {
+	setSubscriptionStatus(subscription, models.SubscriptionStatusPaused, tied)
 }
 
 // handleSubscriptionResumed handles subscription.resumed event
-func (s *subscriptionService) handleSubscriptionResumed(payload map[string]interface{}) error {
-	subscriptionEntity := payload["subscription"].(map[string]interface{})["entity"].(map[string]interface{})
-	razorpaySubID := subscriptionEntity["id"].(string)
-
-	subscription, err := s.repo.FindByRazorpaySubscriptionID(razorpaySubID)
-	if err != nil {
-		return err
-	}
-
-	subscription.Status = models.SubscriptionStatusActive
-	return s.repo.Update(subscription)
+func (s *subscriptionService) handleSubscriptionResumed(subscription *models.Subscription, tied bool) {
+	setSubscriptionStatus(subscription, models.SubscriptionStatusActive, tied)
 }
 
-// CheckAuthenticationStatus checks if a phone number has ever had an authenticated subscription
-func (s *subscriptionService) CheckAuthenticationStatus(phone string, appName string) (*models.CheckAuthenticationStatusResponse, error) {
-	hasAuthenticated, err := s.repo.HasAuthenticatedSubscriptionByPhone(phone, appName)
+// CheckAuthenticationStatus checks if a user's phone number has ever had an authenticated subscription
+func (s *subscriptionService) CheckAuthenticationStatus(userID uuid.UUID, phone string, appName string) (*models.CheckAuthenticationStatusResponse, error) {
+	hasAuthenticated, err := s.repo.HasAuthenticatedSubscriptionByPhone(userID, phone, appName)
 	if err != nil {
 		return nil, err
 	}
@@ -728,3 +933,15 @@ func (s *subscriptionService) CheckAuthenticationStatus(phone string, appName st
 		Phone:            phone,
 	}, nil
 }
+
+// ExpireAbandonedCheckouts marks subscriptions whose checkout link expired before authorization as expired
+func (s *subscriptionService) ExpireAbandonedCheckouts() (int64, error) {
+	expired, err := s.repo.ExpireAbandoned(time.Now(), checkoutLinkTTL)
+	if err != nil {
+		return 0, err
+	}
+	if expired > 0 {
+		fmt.Printf("[ExpireAbandonedCheckouts] Marked %d abandoned checkouts as expired\n", expired)
+	}
+	return expired, nil
+}
@@ -0,0 +1,210 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go-backend/internal/apps/razorpay/subscription/models"
+	"go-backend/internal/common/middleware"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ListWebhookEvents retrieves stored webhook events with pagination, newest first; status defaults to failed
+func (s *subscriptionService) ListWebhookEvents(status, razorpaySubID string, page, pageSize int) (*models.PaginatedWebhookEventsResponse, error) {
+	// Validate page and pageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10 // default page size
+	}
+	if pageSize > 100 {
+		pageSize = 100 // max page size
+	}
+	if status == "" {
+		status = string(models.WebhookEventStatusFailed)
+	}
+
+	events, total, err := s.eventRepo.FindPaginated(status, razorpaySubID, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.WebhookEventResponse, len(events))
+	for i := range events {
+		responses[i] = events[i].ToResponse()
+	}
+
+	// Calculate total pages
+	totalPages := int(total) / pageSize
+	if int(total)%pageSize > 0 {
+		totalPages++
+	}
+
+	// Calculate next and previous pages
+	var nextPage, prevPage *int
+	if page > 1 {
+		prev := page - 1
+		prevPage = &prev
+	}
+	if page < totalPages {
+		next := page + 1
+		nextPage = &next
+	}
+
+	return &models.PaginatedWebhookEventsResponse{
+		Data:       responses,
+		Page:       page,
+		PageSize:   pageSize,
+		Total:      total,
+		TotalPages: totalPages,
+		NextPage:   nextPage,
+		PrevPage:   prevPage,
+	}, nil
+}
+
+// GetWebhookEvent retrieves a stored webhook event with its payload
+func (s *subscriptionService) GetWebhookEvent(id uuid.UUID) (*models.WebhookEventDetailResponse, error) {
+	event, err := s.findWebhookEvent(id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := event.ToDetailResponse()
+	return &response, nil
+}
+
+// ReplayWebhookEvent processes a failed or unfinished webhook event again from its stored payload and signature
+// A dry run reports the changes the event would make without writing anything
+func (s *subscriptionService) ReplayWebhookEvent(id uuid.UUID, dryRun bool, admin *middleware.AdminPrincipal) (*models.WebhookReplayResult, error) {
+	event, err := s.findWebhookEvent(id)
+	if err != nil {
+		return nil, err
+	}
+	if event.Status.IsDone() {
+		return nil, errors.New("webhook event already processed")
+	}
+
+	result := s.replayWebhookEvent(event, dryRun, make(map[string]*models.Subscription))
+	fmt.Printf("[WebhookReplay] %s replayed event %s (dry_run=%t): %s\n", admin.Name, event.EventID, dryRun, result.Outcome)
+	return &result, nil
+}
+
+// ReplayWebhookEvents replays the failed and unfinished webhook events created by Razorpay in [from, to),
+// oldest first so each subscription's events keep their order
+func (s *subscriptionService) ReplayWebhookEvents(req models.ReplayWebhookEventsRequest, admin *middleware.AdminPrincipal) (*models.ReplayWebhookEventsResponse, error) {
+	if !req.From.Before(req.To) {
+		return nil, errors.New("from must be before to")
+	}
+	limit := req.Limit
+	if limit < 1 {
+		limit = 100 // default replay batch
+	}
+	if limit > 500 {
+		limit = 500 // max replay batch
+	}
+
+	events, err := s.eventRepo.FindReplayable(req.From, req.To, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.ReplayWebhookEventsResponse{
+		DryRun:  req.DryRun,
+		Results: make([]models.WebhookReplayResult, 0, len(events)),
+	}
+	previews := make(map[string]*models.Subscription)
+	for i := range events {
+		result := s.replayWebhookEvent(&events[i], req.DryRun, previews)
+		if result.Outcome == models.WebhookEventStatusFailed {
+			response.Failed++
+		} else {
+			response.Replayed++
+		}
+		response.Results = append(response.Results, result)
+	}
+
+	fmt.Printf("[WebhookReplay] %s replayed %d events from %s to %s (dry_run=%t): %d failed\n",
+		admin.Name, len(events), req.From.Format(time.RFC3339), req.To.Format(time.RFC3339), req.DryRun, response.Failed)
+	return response, nil
+}
+
+// replayWebhookEvent processes a stored event again, reporting a failure in the result
+func (s *subscriptionService) replayWebhookEvent(event *models.WebhookEvent, dryRun bool, previews map[string]*models.Subscription) models.WebhookReplayResult {
+	result := models.WebhookReplayResult{
+		ID:                     event.ID,
+		EventID:                event.EventID,
+		EventType:              event.EventType,
+		RazorpaySubscriptionID: event.RazorpaySubscriptionID,
+		DryRun:                 dryRun,
+		Changes:                []models.SubscriptionFieldChange{},
+	}
+
+	application, err := s.processWebhookEvent(event, dryRun, previews)
+	if err != nil {
+		message := err.Error()
+		result.Outcome = models.WebhookEventStatusFailed
+		result.Error = &message
+		return result
+	}
+
+	result.Outcome = application.outcome
+	if application.after != nil {
+		result.Changes = subscriptionChanges(application.before, *application.after)
+	}
+	return result
+}
+
+// subscriptionChanges lists the fields webhook events can change that differ between two versions of a subscription
+func subscriptionChanges(before, after models.Subscription) []models.SubscriptionFieldChange {
+	changes := []models.SubscriptionFieldChange{}
+	add := func(field string, from, to interface{}) {
+		changes = append(changes, models.SubscriptionFieldChange{Field: field, From: from, To: to})
+	}
+
+	if before.Status != after.Status {
+		add("status", before.Status, after.Status)
+	}
+	if before.RazorpayCustomerID != after.RazorpayCustomerID {
+		add("razorpay_customer_id", before.RazorpayCustomerID, after.RazorpayCustomerID)
+	}
+	if !sameTime(before.StartAt, after.StartAt) {
+		add("start_at", before.StartAt, after.StartAt)
+	}
+	if !sameTime(before.NextChargeAt, after.NextChargeAt) {
+		add("next_charge_at", before.NextChargeAt, after.NextChargeAt)
+	}
+	if !sameTime(before.EndAt, after.EndAt) {
+		add("end_at", before.EndAt, after.EndAt)
+	}
+	if before.Metadata != after.Metadata {
+		add("metadata", before.Metadata, after.Metadata)
+	}
+	if !sameTime(before.LastWebhookEventAt, after.LastWebhookEventAt) {
+		add("last_webhook_event_at", before.LastWebhookEventAt, after.LastWebhookEventAt)
+	}
+	return changes
+}
+
+// sameTime reports whether two optional times are both unset or equal
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// findWebhookEvent loads a webhook event, mapping a missing record to "webhook event not found"
+func (s *subscriptionService) findWebhookEvent(id uuid.UUID) (*models.WebhookEvent, error) {
+	event, err := s.eventRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("webhook event not found")
+		}
+		return nil, err
+	}
+	return event, nil
+}
@@ -0,0 +1,215 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"go-backend/internal/apps/user/models"
+	"go-backend/internal/apps/user/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// IdentityHandler handles HTTP requests for users' social identities
+type IdentityHandler struct {
+	service service.IdentityService
+}
+
+// NewIdentityHandler creates a new instance of IdentityHandler
+func NewIdentityHandler(service service.IdentityService) *IdentityHandler {
+	return &IdentityHandler{service: service}
+}
+
+// identityErrorStatus maps identity service errors to HTTP status codes
+func identityErrorStatus(err error) int {
+	switch err.Error() {
+	case "user not found", "identity not found":
+		return http.StatusNotFound
+	case "identity already claimed", "identity of this type already exists", "identity already verified":
+		return http.StatusConflict
+	case "user is suspended":
+		return http.StatusForbidden
+	case "invalid social handle":
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// parseIdentityParams parses the user and identity IDs from the path
+func parseIdentityParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	identityID, err := uuid.Parse(c.Param("identity_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid identity id"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, identityID, true
+}
+
+// ListIdentities handles GET /api/v1/users/:id/identities
+func (h *IdentityHandler) ListIdentities(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+
+	if !authorizeUser(c, userID) {
+		return
+	}
+
+	resp, err := h.service.ListIdentities(userID)
+	if err != nil {
+		c.JSON(identityErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
+
+// AddIdentity handles POST /api/v1/users/:id/identities
+func (h *IdentityHandler) AddIdentity(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+
+	if !authorizeUser(c, userID) {
+		return
+	}
+
+	var req models.CreateIdentityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.service.AddIdentity(userID, req)
+	if err != nil {
+		c.JSON(identityErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": resp})
+}
+
+// UpdateIdentity handles PUT /api/v1/users/:id/identities/:identity_id
+func (h *IdentityHandler) UpdateIdentity(c *gin.Context) {
+	userID, identityID, ok := parseIdentityParams(c)
+	if !ok {
+		return
+	}
+
+	if !authorizeUser(c, userID) {
+		return
+	}
+
+	var req models.UpdateIdentityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.service.UpdateIdentity(userID, identityID, req)
+	if err != nil {
+		c.JSON(identityErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
+
+// DeleteIdentity handles DELETE /api/v1/users/:id/identities/:identity_id
+func (h *IdentityHandler) DeleteIdentity(c *gin.Context) {
+	userID, identityID, ok := parseIdentityParams(c)
+	if !ok {
+		return
+	}
+
+	if !authorizeUser(c, userID) {
+		return
+	}
+
+	if err := h.service.DeleteIdentity(userID, identityID); err != nil {
+		c.JSON(identityErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "identity deleted successfully"})
+}
+
+// RequestVerification handles POST /api/v1/users/:id/identities/:identity_id/verification
+// Returns the identity with a code the owner adds to the account's bio for support to check
+func (h *IdentityHandler) RequestVerification(c *gin.Context) {
+	userID, identityID, ok := parseIdentityParams(c)
+	if !ok {
+		return
+	}
+
+	if !authorizeUser(c, userID) {
+		return
+	}
+
+	resp, err := h.service.RequestVerification(userID, identityID)
+	if err != nil {
+		c.JSON(identityErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
+
+// ListPendingVerifications handles GET /api/v1/users/identities/pending
+// Lists the identities whose codes support still has to check, oldest request first
+func (h *IdentityHandler) ListPendingVerifications(c *gin.Context) {
+	page := 1
+	pageSize := 10
+	if pageStr := c.Query("page"); pageStr != "" {
+		if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
+			page = p
+		}
+	}
+	if pageSizeStr := c.Query("page_size"); pageSizeStr != "" {
+		if ps, err := strconv.Atoi(pageSizeStr); err == nil && ps > 0 {
+			pageSize = ps
+		}
+	}
+
+	resp, err := h.service.ListPendingVerifications(c.Query("app_name"), page, pageSize)
+	if err != nil {
+		c.JSON(identityErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// VerifyIdentity handles POST /api/v1/users/:id/identities/:identity_id/verify
+// Lets support mark an identity as verified (or reject or revoke it) after checking the code in the account's bio
+func (h *IdentityHandler) VerifyIdentity(c *gin.Context) {
+	userID, identityID, ok := parseIdentityParams(c)
+	if !ok {
+		return
+	}
+
+	var req models.VerifyIdentityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.service.SetVerified(userID, identityID, *req.Verified)
+	if err != nil {
+		c.JSON(identityErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
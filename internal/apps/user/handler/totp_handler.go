@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"net/http"
+
+	"go-backend/internal/apps/user/models"
+	"go-backend/internal/apps/user/service"
+	"go-backend/internal/common/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TOTPHandler handles HTTP requests for authenticator-app second factors
+type TOTPHandler struct {
+	service service.TOTPService
+}
+
+// NewTOTPHandler creates a new instance of TOTPHandler
+func NewTOTPHandler(service service.TOTPService) *TOTPHandler {
+	return &TOTPHandler{service: service}
+}
+
+// totpErrorStatus maps TOTP service errors to HTTP status codes
+func totpErrorStatus(err error) int {
+	switch err.Error() {
+	case "user not found", "totp not enabled":
+		return http.StatusNotFound
+	case "totp already enabled":
+		return http.StatusConflict
+	case "invalid totp code":
+		return http.StatusBadRequest
+	case "too many failed attempts, try again later":
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// GetStatus handles GET /api/v1/users/me/totp
+func (h *TOTPHandler) GetStatus(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+		return
+	}
+
+	resp, err := h.service.Status(principal.UserID)
+	if err != nil {
+		c.JSON(totpErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
+
+// Enroll handles POST /api/v1/users/me/totp
+func (h *TOTPHandler) Enroll(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+		return
+	}
+
+	resp, err := h.service.Enroll(principal.UserID)
+	if err != nil {
+		c.JSON(totpErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": resp})
+}
+
+// Confirm handles POST /api/v1/users/me/totp/confirm
+func (h *TOTPHandler) Confirm(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+		return
+	}
+
+	var req models.TOTPCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.service.Confirm(principal.UserID, req.Code)
+	if err != nil {
+		c.JSON(totpErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
+
+// RegenerateRecoveryCodes handles POST /api/v1/users/me/totp/recovery-codes
+func (h *TOTPHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+		return
+	}
+
+	var req models.TOTPCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.service.RegenerateRecoveryCodes(principal.UserID, req.Code)
+	if err != nil {
+		c.JSON(totpErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
+
+// Disable handles DELETE /api/v1/users/me/totp
+func (h *TOTPHandler) Disable(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+		return
+	}
+
+	var req models.TOTPCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.Disable(principal.UserID, req.Code); err != nil {
+		c.JSON(totpErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "totp disabled successfully"})
+}
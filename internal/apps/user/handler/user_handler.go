@@ -1,11 +1,13 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"go-backend/internal/apps/user/models"
 	"go-backend/internal/apps/user/service"
+	"go-backend/internal/common/middleware"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -21,7 +23,22 @@ func NewUserHandler(service service.UserService) *UserHandler {
 	return &UserHandler{service: service}
 }
 
+// authorizeUser ensures the authenticated principal is the given user
+func authorizeUser(c *gin.Context, userID uuid.UUID) bool {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+		return false
+	}
+	if principal.UserID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return false
+	}
+	return true
+}
+
 // CreateUser handles POST /api/v1/users
+// Admin only; users are otherwise created when they first sign in with a verified phone or email
 func (h *UserHandler) CreateUser(c *gin.Context) {
 	var req models.CreateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -39,7 +56,14 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 }
 
 // GetUserByAppAndPhone handles GET /api/v1/users/by-phone
+// Another user's record is reported as not found so the lookup cannot reveal which phones are registered
 func (h *UserHandler) GetUserByAppAndPhone(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+		return
+	}
+
 	appName := c.Query("app_name")
 	countryCode := c.Query("country_code")
 	phone := c.Query("phone")
@@ -49,6 +73,9 @@ func (h *UserHandler) GetUserByAppAndPhone(c *gin.Context) {
 	}
 
 	resp, err := h.service.GetUserByAppAndContact(appName, countryCode, phone)
+	if err == nil && resp.ID != principal.UserID {
+		err = errors.New("user not found")
+	}
 	if err != nil {
 		status := http.StatusInternalServerError
 		if err.Error() == "user not found" {
@@ -61,7 +88,14 @@ func (h *UserHandler) GetUserByAppAndPhone(c *gin.Context) {
 }
 
 // GetUserByAppAndEmail handles GET /api/v1/users/by-email
+// Another user's record is reported as not found so the lookup cannot reveal which emails are registered
 func (h *UserHandler) GetUserByAppAndEmail(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+		return
+	}
+
 	appName := c.Query("app_name")
 	email := c.Query("email")
 	if appName == "" || email == "" {
@@ -70,6 +104,29 @@ func (h *UserHandler) GetUserByAppAndEmail(c *gin.Context) {
 	}
 
 	resp, err := h.service.GetUserByAppAndEmail(appName, email)
+	if err == nil && resp.ID != principal.UserID {
+		err = errors.New("user not found")
+	}
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "user not found" {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
+
+// GetCurrentUser handles GET /api/v1/users/me
+func (h *UserHandler) GetCurrentUser(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+		return
+	}
+
+	resp, err := h.service.GetUserByID(principal.UserID)
 	if err != nil {
 		status := http.StatusInternalServerError
 		if err.Error() == "user not found" {
@@ -78,6 +135,7 @@ func (h *UserHandler) GetUserByAppAndEmail(c *gin.Context) {
 		c.JSON(status, gin.H{"error": err.Error()})
 		return
 	}
+
 	c.JSON(http.StatusOK, gin.H{"data": resp})
 }
 
@@ -89,6 +147,10 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 		return
 	}
 
+	if !authorizeUser(c, id) {
+		return
+	}
+
 	resp, err := h.service.GetUserByID(id)
 	if err != nil {
 		status := http.StatusInternalServerError
@@ -110,6 +172,10 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 		return
 	}
 
+	if !authorizeUser(c, id) {
+		return
+	}
+
 	var req models.UpdateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -119,8 +185,11 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 	resp, err := h.service.UpdateUser(id, req)
 	if err != nil {
 		status := http.StatusBadRequest
-		if err.Error() == "user not found" {
+		switch err.Error() {
+		case "user not found":
 			status = http.StatusNotFound
+		case "user is suspended":
+			status = http.StatusForbidden
 		}
 		c.JSON(status, gin.H{"error": err.Error()})
 		return
@@ -130,6 +199,8 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 }
 
 // ListAllUsers handles GET /api/v1/users/all
+// Supports app_name, min_crushes and max_crushes filters, sort (created_at, updated_at, name,
+// app_name or crushes_count) with order (asc or desc), and page/page_size pagination
 func (h *UserHandler) ListAllUsers(c *gin.Context) {
 	// Default pagination values
 	page := 1
@@ -152,11 +223,56 @@ func (h *UserHandler) ListAllUsers(c *gin.Context) {
 		}
 	}
 
-	resp, err := h.service.ListAllUsersPaginated(appName, page, pageSize)
+	filter := models.UserListFilter{
+		AppName:    appName,
+		SortBy:     c.Query("sort"),
+		Descending: true,
+	}
+
+	// Parse order parameter; listings are newest first by default
+	switch c.DefaultQuery("order", "desc") {
+	case "asc":
+		filter.Descending = false
+	case "desc":
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "order must be asc or desc"})
+		return
+	}
+
+	// Parse crush count bounds
+	var ok bool
+	if filter.MinCrushes, ok = crushCountParam(c, "min_crushes"); !ok {
+		return
+	}
+	if filter.MaxCrushes, ok = crushCountParam(c, "max_crushes"); !ok {
+		return
+	}
+
+	resp, err := h.service.ListAllUsersPaginated(filter, page, pageSize)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		status := http.StatusInternalServerError
+		switch err.Error() {
+		case "invalid sort", "min_crushes cannot exceed max_crushes":
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, resp)
 }
+
+// crushCountParam parses an optional non-negative crush count query parameter
+func crushCountParam(c *gin.Context, name string) (*int64, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, true
+	}
+
+	count, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || count < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + name})
+		return nil, false
+	}
+	return &count, true
+}
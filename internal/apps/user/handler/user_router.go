@@ -1,16 +1,35 @@
 package handler
 
-import "github.com/gin-gonic/gin"
+import (
+	"go-backend/internal/common/middleware"
+
+	"github.com/gin-gonic/gin"
+)
 
 // RegisterUserRoutes registers all user-related routes
-func RegisterUserRoutes(router *gin.RouterGroup, handler *UserHandler) {
+// requireAuth guards routes that act on behalf of the authenticated user,
+// adminGuard guards user creation and management listings
+func RegisterUserRoutes(router *gin.RouterGroup, handler *UserHandler, totpHandler *TOTPHandler, identityHandler *IdentityHandler, requireAuth gin.HandlerFunc, adminGuard middleware.AdminGuard) {
 	users := router.Group("/users")
 	{
-		users.POST("", handler.CreateUser)
-		users.GET("/all", handler.ListAllUsers)
-		users.GET("/:id", handler.GetUser)
-		users.PUT("/:id", handler.UpdateUser)
-		users.GET("/by-phone", handler.GetUserByAppAndPhone)
-		users.GET("/by-email", handler.GetUserByAppAndEmail)
+		users.POST("", adminGuard(middleware.RoleAdmin, middleware.RoleSupport), handler.CreateUser)
+		users.GET("/all", adminGuard(), handler.ListAllUsers)
+		users.GET("/identities/pending", adminGuard(middleware.RoleAdmin, middleware.RoleSupport), identityHandler.ListPendingVerifications)
+		users.GET("/me", requireAuth, handler.GetCurrentUser)
+		users.GET("/me/totp", requireAuth, totpHandler.GetStatus)
+		users.POST("/me/totp", requireAuth, totpHandler.Enroll)
+		users.DELETE("/me/totp", requireAuth, totpHandler.Disable)
+		users.POST("/me/totp/confirm", requireAuth, totpHandler.Confirm)
+		users.POST("/me/totp/recovery-codes", requireAuth, totpHandler.RegenerateRecoveryCodes)
+		users.GET("/:id", requireAuth, handler.GetUser)
+		users.PUT("/:id", requireAuth, handler.UpdateUser)
+		users.GET("/:id/identities", requireAuth, identityHandler.ListIdentities)
+		users.POST("/:id/identities", requireAuth, identityHandler.AddIdentity)
+		users.PUT("/:id/identities/:identity_id", requireAuth, identityHandler.UpdateIdentity)
+		users.DELETE("/:id/identities/:identity_id", requireAuth, identityHandler.DeleteIdentity)
+		users.POST("/:id/identities/:identity_id/verification", requireAuth, identityHandler.RequestVerification)
+		users.POST("/:id/identities/:identity_id/verify", adminGuard(middleware.RoleAdmin, middleware.RoleSupport), identityHandler.VerifyIdentity)
+		users.GET("/by-phone", requireAuth, handler.GetUserByAppAndPhone)
+		users.GET("/by-email", requireAuth, handler.GetUserByAppAndEmail)
 	}
 }
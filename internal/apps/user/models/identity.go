@@ -0,0 +1,84 @@
+package models
+
+import (
+	"time"
+
+	"go-backend/pkg/identifier"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Identity types
+const (
+	IdentityTypeInstagram = "instagram"
+	IdentityTypeSnapchat  = "snapchat"
+)
+
+// UserIdentity is a social account a user claims, used for crush matching
+// Value holds the normalized handle; several users may claim a handle, but only one per app can have it verified
+// Only verified identities are used for matching. To verify one, the owner requests a code, adds it to the
+// account's bio and support confirms it there; handles migrated from users.metadata start unverified too
+type UserIdentity struct {
+	ID                      uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID                  uuid.UUID  `gorm:"type:uuid;not null" json:"user_id"`
+	AppName                 string     `gorm:"not null;size:100" json:"app_name"`
+	Type                    string     `gorm:"not null;size:20" json:"type"`
+	Value                   string     `gorm:"not null;size:255" json:"value"`
+	ValueHash               *string    `gorm:"size:64" json:"-"` // Blind index of the handle, joinable with crush targets in SQL
+	Verified                bool       `gorm:"not null;default:false" json:"verified"`
+	VerifiedAt              *time.Time `json:"verified_at,omitempty"`
+	VerificationCode        *string    `gorm:"size:20" json:"verification_code,omitempty"` // Code the owner adds to the account's bio
+	VerificationRequestedAt *time.Time `json:"verification_requested_at,omitempty"`
+	CreatedAt               time.Time  `json:"created_at"`
+	UpdatedAt               time.Time  `json:"updated_at"`
+}
+
+// TableName sets the table name to 'user_identities'
+func (UserIdentity) TableName() string { return "user_identities" }
+
+// BeforeCreate hook to generate UUID before creating record
+func (i *UserIdentity) BeforeCreate(tx *gorm.DB) error {
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
+	return nil
+}
+
+// BeforeSave hook to keep the handle's blind index in sync; identity types double as identifier kinds
+func (i *UserIdentity) BeforeSave(tx *gorm.DB) error {
+	hash, err := identifier.BlindIndex(i.Type, i.Value)
+	if err != nil {
+		return err
+	}
+	i.ValueHash = &hash
+	return nil
+}
+
+// CreateIdentityRequest represents the request body for adding an identity
+type CreateIdentityRequest struct {
+	Type  string `json:"type" binding:"required,oneof=instagram snapchat"`
+	Value string `json:"value" binding:"required,max=255"`
+}
+
+// UpdateIdentityRequest represents the request body for changing an identity's handle
+// Changing the handle clears its verification and any pending verification request
+type UpdateIdentityRequest struct {
+	Value string `json:"value" binding:"required,max=255"`
+}
+
+// VerifyIdentityRequest represents the request body for setting an identity's verification
+type VerifyIdentityRequest struct {
+	Verified *bool `json:"verified" binding:"required"`
+}
+
+// PaginatedIdentitiesResponse represents a paginated list of identities
+type PaginatedIdentitiesResponse struct {
+	Data       []UserIdentity `json:"data"`
+	Page       int            `json:"page"`
+	PageSize   int            `json:"page_size"`
+	Total      int64          `json:"total"`
+	TotalPages int            `json:"total_pages"`
+	NextPage   *int           `json:"next_page"`
+	PrevPage   *int           `json:"prev_page"`
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserTOTP holds a user's authenticator-app (TOTP) second factor
+// The secret is encrypted at rest; the factor is only enforced once ConfirmedAt is set
+type UserTOTP struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID         uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex" json:"user_id"`
+	Secret         string     `gorm:"not null" json:"-"`
+	ConfirmedAt    *time.Time `json:"confirmed_at,omitempty"`
+	LastUsedStep   int64      `gorm:"not null;default:0" json:"-"` // Time step of the last accepted code, blocks replays
+	FailedAttempts int        `gorm:"not null;default:0" json:"-"`
+	LockedUntil    *time.Time `json:"-"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// TableName sets the table name to 'user_totp'
+func (UserTOTP) TableName() string { return "user_totp" }
+
+// UserRecoveryCode is a single-use backup code for a user's second factor; only its keyed hash is stored
+type UserRecoveryCode struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	CodeHash  string     `gorm:"not null;size:64" json:"-"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// TableName sets the table name to 'user_recovery_codes'
+func (UserRecoveryCode) TableName() string { return "user_recovery_codes" }
+
+// TOTPCodeRequest carries an authenticator or recovery code
+type TOTPCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// TOTPEnrollmentResponse is returned when a user starts TOTP enrolment
+// ProvisioningURI is the otpauth:// URI to render as a QR code
+type TOTPEnrollmentResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// TOTPRecoveryCodesResponse returns freshly issued recovery codes; they are only shown once
+type TOTPRecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// TOTPStatusResponse describes a user's second factor state
+type TOTPStatusResponse struct {
+	Enabled                bool       `json:"enabled"`
+	Pending                bool       `json:"pending"` // Enrolment started but not confirmed
+	ConfirmedAt            *time.Time `json:"confirmed_at,omitempty"`
+	RecoveryCodesRemaining int64      `json:"recovery_codes_remaining"`
+}
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"time"
 
+	"go-backend/pkg/identifier"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
@@ -45,6 +47,22 @@ type User struct {
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+
+	// Set by moderation; suspended users cannot sign in, edit their profile or use Crush Connect
+	SuspendedAt      *time.Time `json:"suspended_at,omitempty"`
+	SuspensionReason *string    `gorm:"size:600" json:"suspension_reason,omitempty"`
+
+	// Canonical identifiers used for lookups and crush matching, derived on save
+	PhoneNormalized *string `gorm:"size:20" json:"-"`
+	EmailNormalized *string `gorm:"size:255" json:"-"`
+
+	// Blind index of the normalized phone, joinable with crush targets in SQL
+	PhoneHash *string `gorm:"size:64" json:"-"`
+}
+
+// IsSuspended reports whether the user has been suspended by moderation
+func (u *User) IsSuspended() bool {
+	return u.SuspendedAt != nil
 }
 
 // BeforeCreate hook to generate UUID before creating record
@@ -55,6 +73,31 @@ func (u *User) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// BeforeSave hook to keep the normalized identifiers and the phone's blind index in sync
+func (u *User) BeforeSave(tx *gorm.DB) error {
+	u.NormalizeIdentifiers()
+
+	var err error
+	u.PhoneHash, err = identifier.OptionalBlindIndex(identifier.KindPhone, u.PhoneNormalized)
+	return err
+}
+
+// NormalizeIdentifiers derives the normalized phone and email
+// Identifiers that cannot be normalized are left empty so they never match
+func (u *User) NormalizeIdentifiers() {
+	u.PhoneNormalized, u.EmailNormalized = nil, nil
+	if u.CountryCode != nil && u.Phone != nil {
+		if phone, err := identifier.NormalizePhone(*u.CountryCode, *u.Phone); err == nil {
+			u.PhoneNormalized = &phone
+		}
+	}
+	if u.Email != nil {
+		if email, err := identifier.NormalizeEmail(*u.Email); err == nil {
+			u.EmailNormalized = &email
+		}
+	}
+}
+
 // CreateUserRequest represents the request body for creating a user
 type CreateUserRequest struct {
 	Name        *string  `json:"name,omitempty"`
@@ -67,26 +110,29 @@ type CreateUserRequest struct {
 
 // UpdateUserRequest represents the request body for updating a user
 // At least one of (email) or (country_code + phone) must be present after update
+// A new phone or email must be confirmed with an OTP sent to it (phone_otp / email_otp)
 type UpdateUserRequest struct {
 	Name        *string  `json:"name,omitempty"`
 	CountryCode *string  `json:"country_code,omitempty"`
 	Phone       *string  `json:"phone,omitempty"`
 	Email       *string  `json:"email,omitempty" binding:"omitempty,email"`
-	AppName     *string  `json:"app_name,omitempty"`
+	PhoneOTP    *string  `json:"phone_otp,omitempty"`
+	EmailOTP    *string  `json:"email_otp,omitempty"`
 	Metadata    Metadata `json:"metadata,omitempty"`
 }
 
 // UserResponse represents the response payload for user operations
 type UserResponse struct {
-	ID          uuid.UUID `json:"id"`
-	Name        *string   `json:"name,omitempty"`
-	CountryCode *string   `json:"country_code,omitempty"`
-	Phone       *string   `json:"phone,omitempty"`
-	Email       *string   `json:"email,omitempty"`
-	AppName     string    `json:"app_name"`
-	Metadata    Metadata  `json:"metadata"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          uuid.UUID  `json:"id"`
+	Name        *string    `json:"name,omitempty"`
+	CountryCode *string    `json:"country_code,omitempty"`
+	Phone       *string    `json:"phone,omitempty"`
+	Email       *string    `json:"email,omitempty"`
+	AppName     string     `json:"app_name"`
+	Metadata    Metadata   `json:"metadata"`
+	SuspendedAt *time.Time `json:"suspended_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
 }
 
 // ToResponse converts User model to UserResponse
@@ -99,6 +145,7 @@ func (u *User) ToResponse() UserResponse {
 		Email:       u.Email,
 		AppName:     u.AppName,
 		Metadata:    u.Metadata,
+		SuspendedAt: u.SuspendedAt,
 		CreatedAt:   u.CreatedAt,
 		UpdatedAt:   u.UpdatedAt,
 	}
@@ -117,16 +164,74 @@ type PaginatedUsersResponse struct {
 
 // UserWithCountResponse represents user response with crushes count
 type UserWithCountResponse struct {
-	ID           uuid.UUID `json:"id"`
-	Name         *string   `json:"name,omitempty"`
-	CountryCode  *string   `json:"country_code,omitempty"`
-	Phone        *string   `json:"phone,omitempty"`
-	Email        *string   `json:"email,omitempty"`
-	AppName      string    `json:"app_name"`
-	Metadata     Metadata  `json:"metadata"`
-	CrushesCount int64     `json:"crushes_count"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID           uuid.UUID  `json:"id"`
+	Name         *string    `json:"name,omitempty"`
+	CountryCode  *string    `json:"country_code,omitempty"`
+	Phone        *string    `json:"phone,omitempty"`
+	Email        *string    `json:"email,omitempty"`
+	AppName      string     `json:"app_name"`
+	Metadata     Metadata   `json:"metadata"`
+	CrushesCount int64      `json:"crushes_count"`
+	SuspendedAt  *time.Time `json:"suspended_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// ToCountResponse converts User model to UserWithCountResponse
+func (u *User) ToCountResponse(crushesCount int64) UserWithCountResponse {
+	return UserWithCountResponse{
+		ID:           u.ID,
+		Name:         u.Name,
+		CountryCode:  u.CountryCode,
+		Phone:        u.Phone,
+		Email:        u.Email,
+		AppName:      u.AppName,
+		Metadata:     u.Metadata,
+		CrushesCount: crushesCount,
+		SuspendedAt:  u.SuspendedAt,
+		CreatedAt:    u.CreatedAt,
+		UpdatedAt:    u.UpdatedAt,
+	}
+}
+
+// UserWithCrushCount is a user loaded together with their crushes count
+type UserWithCrushCount struct {
+	User
+	CrushesCount int64
+}
+
+// Columns the admin user listing can be sorted by
+const (
+	UserSortCreatedAt    = "created_at"
+	UserSortUpdatedAt    = "updated_at"
+	UserSortName         = "name"
+	UserSortAppName      = "app_name"
+	UserSortCrushesCount = "crushes_count"
+)
+
+// IsValidUserSort reports whether the admin user listing can be sorted by column
+func IsValidUserSort(column string) bool {
+	switch column {
+	case UserSortCreatedAt, UserSortUpdatedAt, UserSortName, UserSortAppName, UserSortCrushesCount:
+		return true
+	}
+	return false
+}
+
+// UserListFilter holds the filters and sort order of the admin user listing
+// Crush count bounds are inclusive; nil bounds are not applied
+type UserListFilter struct {
+	AppName    string
+	SortBy     string
+	Descending bool
+	MinCrushes *int64
+	MaxCrushes *int64
+}
+
+// NeedsCrushCounts reports whether the listing sorts or filters on crushes count
+// so users have to be loaded joined with their counts
+func (f UserListFilter) NeedsCrushCounts() bool {
+	return f.SortBy == UserSortCrushesCount || f.MinCrushes != nil || f.MaxCrushes != nil
 }
 
 // PaginatedUsersWithCountResponse represents paginated users response with crushes count
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"go-backend/internal/apps/user/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// IdentityRepository defines data operations for user social identities
+type IdentityRepository interface {
+	Create(identity *models.UserIdentity) error
+	FindByID(id uuid.UUID) (*models.UserIdentity, error)
+	FindByUserID(userID uuid.UUID) ([]models.UserIdentity, error)
+	FindByUserIDs(userIDs []uuid.UUID) ([]models.UserIdentity, error)
+	FindVerifiedByValue(appName, identityType, value string) (*models.UserIdentity, error)
+	FindPendingVerification(appName string, page, pageSize int) ([]models.UserIdentity, int64, error)
+	Update(identity *models.UserIdentity) error
+	Delete(identity *models.UserIdentity) error
+	IndexValueBatch(limit int) (int, error)
+}
+
+// identityRepository implements IdentityRepository
+type identityRepository struct {
+	db *gorm.DB
+}
+
+// NewIdentityRepository creates a new instance of IdentityRepository
+func NewIdentityRepository(db *gorm.DB) IdentityRepository {
+	return &identityRepository{db: db}
+}
+
+// Create creates a new identity
+func (r *identityRepository) Create(identity *models.UserIdentity) error {
+	return r.db.Create(identity).Error
+}
+
+// FindByID retrieves an identity by its ID
+func (r *identityRepository) FindByID(id uuid.UUID) (*models.UserIdentity, error) {
+	var identity models.UserIdentity
+	if err := r.db.First(&identity, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+// FindByUserID retrieves all identities of a user
+func (r *identityRepository) FindByUserID(userID uuid.UUID) ([]models.UserIdentity, error) {
+	var identities []models.UserIdentity
+	if err := r.db.Where("user_id = ?", userID).Order("created_at ASC").Find(&identities).Error; err != nil {
+		return nil, err
+	}
+	return identities, nil
+}
+
+// FindByUserIDs retrieves the identities of several users in a single query
+func (r *identityRepository) FindByUserIDs(userIDs []uuid.UUID) ([]models.UserIdentity, error) {
+	var identities []models.UserIdentity
+	if len(userIDs) == 0 {
+		return identities, nil
+	}
+	if err := r.db.Where("user_id IN ?", userIDs).Order("created_at ASC").Find(&identities).Error; err != nil {
+		return nil, err
+	}
+	return identities, nil
+}
+
+// FindVerifiedByValue retrieves the verified identity holding a normalized handle in an app
+func (r *identityRepository) FindVerifiedByValue(appName, identityType, value string) (*models.UserIdentity, error) {
+	var identity models.UserIdentity
+	if err := r.db.Where("app_name = ? AND type = ? AND value = ? AND verified = true", appName, identityType, value).First(&identity).Error; err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+// FindPendingVerification retrieves unverified identities whose owners requested verification, oldest request first
+func (r *identityRepository) FindPendingVerification(appName string, page, pageSize int) ([]models.UserIdentity, int64, error) {
+	var identities []models.UserIdentity
+	var total int64
+
+	query := r.db.Model(&models.UserIdentity{}).Where("verification_requested_at IS NOT NULL AND verified = false")
+	if appName != "" {
+		query = query.Where("app_name = ?", appName)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Order("verification_requested_at ASC").Offset(offset).Limit(pageSize).Find(&identities).Error; err != nil {
+		return nil, 0, err
+	}
+	return identities, total, nil
+}
+
+// Update updates an existing identity
+func (r *identityRepository) Update(identity *models.UserIdentity) error {
+	return r.db.Save(identity).Error
+}
+
+// Delete removes an identity
+func (r *identityRepository) Delete(identity *models.UserIdentity) error {
+	return r.db.Delete(identity).Error
+}
+
+// IndexValueBatch re-saves up to limit identities whose handle has no blind index yet
+// Returns the number of identities indexed; 0 means none are left
+func (r *identityRepository) IndexValueBatch(limit int) (int, error) {
+	var identities []models.UserIdentity
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("value_hash IS NULL").
+			Order("created_at ASC").
+			Limit(limit).
+			Find(&identities).Error; err != nil {
+			return err
+		}
+		for i := range identities {
+			if err := tx.Select("value_hash").Save(&identities[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(identities), nil
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"time"
+
+	"go-backend/internal/apps/user/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TOTPRepository defines data operations for user TOTP factors and recovery codes
+type TOTPRepository interface {
+	FindByUserID(userID uuid.UUID) (*models.UserTOTP, error)
+	Save(totp *models.UserTOTP) error
+	Delete(userID uuid.UUID) error
+	Confirm(id uuid.UUID, step int64) error
+	MarkStepUsed(id uuid.UUID, step int64) (bool, error)
+	ReserveAttempt(id uuid.UUID, maxFailures int, lockFor time.Duration) (bool, error)
+	ClearFailures(id uuid.UUID) error
+	ReplaceRecoveryCodes(userID uuid.UUID, codeHashes []string) error
+	ConsumeRecoveryCode(userID uuid.UUID, codeHash string) (bool, error)
+	CountUnusedRecoveryCodes(userID uuid.UUID) (int64, error)
+}
+
+// totpRepository implements TOTPRepository
+type totpRepository struct {
+	db *gorm.DB
+}
+
+// NewTOTPRepository creates a new instance of TOTPRepository
+func NewTOTPRepository(db *gorm.DB) TOTPRepository {
+	return &totpRepository{db: db}
+}
+
+// FindByUserID retrieves a user's TOTP factor
+func (r *totpRepository) FindByUserID(userID uuid.UUID) (*models.UserTOTP, error) {
+	var totp models.UserTOTP
+	if err := r.db.Where("user_id = ?", userID).First(&totp).Error; err != nil {
+		return nil, err
+	}
+	return &totp, nil
+}
+
+// Save creates or replaces a user's TOTP factor
+func (r *totpRepository) Save(totp *models.UserTOTP) error {
+	return r.db.Save(totp).Error
+}
+
+// Delete removes a user's TOTP factor together with its recovery codes
+func (r *totpRepository) Delete(userID uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.UserRecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&models.UserTOTP{}).Error
+	})
+}
+
+// Confirm marks a pending TOTP factor as active
+func (r *totpRepository) Confirm(id uuid.UUID, step int64) error {
+	return r.db.Model(&models.UserTOTP{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"confirmed_at":    time.Now(),
+		"last_used_step":  step,
+		"failed_attempts": 0,
+		"locked_until":    nil,
+	}).Error
+}
+
+// MarkStepUsed atomically records an accepted code and resets the failure counter
+// Returns false if a code for the same or a later time step was already accepted
+func (r *totpRepository) MarkStepUsed(id uuid.UUID, step int64) (bool, error) {
+	result := r.db.Model(&models.UserTOTP{}).
+		Where("id = ? AND last_used_step < ?", id, step).
+		Updates(map[string]interface{}{"last_used_step": step, "failed_attempts": 0, "locked_until": nil})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// ReserveAttempt atomically counts a verification attempt before the code is checked
+// The attempt that reaches maxFailures locks the factor for lockFor; an expired lock starts a new count
+// Returns false while the factor is locked, so concurrent guesses cannot exceed maxFailures
+func (r *totpRepository) ReserveAttempt(id uuid.UUID, maxFailures int, lockFor time.Duration) (bool, error) {
+	now := time.Now()
+	var attempts []int
+	err := r.db.Raw(
+		`UPDATE user_totp
+		SET failed_attempts = CASE WHEN locked_until IS NULL THEN failed_attempts + 1 ELSE 1 END,
+			locked_until = CASE WHEN (CASE WHEN locked_until IS NULL THEN failed_attempts + 1 ELSE 1 END) >= ? THEN ?::timestamptz ELSE NULL END,
+			updated_at = ?
+		WHERE id = ? AND ((locked_until IS NULL AND failed_attempts < ?) OR locked_until < ?)
+		RETURNING failed_attempts`,
+		maxFailures, now.Add(lockFor), now, id, maxFailures, now,
+	).Scan(&attempts).Error
+	if err != nil {
+		return false, err
+	}
+	return len(attempts) == 1, nil
+}
+
+// ClearFailures resets the attempt counter after a code was accepted
+func (r *totpRepository) ClearFailures(id uuid.UUID) error {
+	return r.db.Model(&models.UserTOTP{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"failed_attempts": 0,
+		"locked_until":    nil,
+	}).Error
+}
+
+// ReplaceRecoveryCodes discards a user's recovery codes and stores the new hashes
+func (r *totpRepository) ReplaceRecoveryCodes(userID uuid.UUID, codeHashes []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.UserRecoveryCode{}).Error; err != nil {
+			return err
+		}
+		codes := make([]models.UserRecoveryCode, len(codeHashes))
+		for i, hash := range codeHashes {
+			codes[i] = models.UserRecoveryCode{ID: uuid.New(), UserID: userID, CodeHash: hash}
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+// ConsumeRecoveryCode atomically marks an unused recovery code as used
+func (r *totpRepository) ConsumeRecoveryCode(userID uuid.UUID, codeHash string) (bool, error) {
+	result := r.db.Model(&models.UserRecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// CountUnusedRecoveryCodes counts a user's remaining recovery codes
+func (r *totpRepository) CountUnusedRecoveryCodes(userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.UserRecoveryCode{}).Where("user_id = ? AND used_at IS NULL", userID).Count(&count).Error
+	return count, err
+}
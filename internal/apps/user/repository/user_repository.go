@@ -1,7 +1,10 @@
 package repository
 
 import (
+	"time"
+
 	"go-backend/internal/apps/user/models"
+	"go-backend/pkg/identifier"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -11,10 +14,15 @@ import (
 type UserRepository interface {
 	Create(user *models.User) error
 	FindByID(id uuid.UUID) (*models.User, error)
+	FindByIDs(ids []uuid.UUID) ([]models.User, error)
 	FindByAppAndContact(appName, countryCode, phone string) (*models.User, error)
 	FindByAppAndEmail(appName, email string) (*models.User, error)
 	Update(user *models.User) error
-	FindAllPaginated(appName string, page, pageSize int) ([]models.User, int64, error)
+	Suspend(id uuid.UUID, reason string) (bool, error)
+	Reinstate(id uuid.UUID) (bool, error)
+	FindAllPaginated(filter models.UserListFilter, page, pageSize int) ([]models.User, int64, error)
+	FindAllWithCrushCounts(filter models.UserListFilter, page, pageSize int) ([]models.UserWithCrushCount, int64, error)
+	IndexPhoneBatch(limit int) (int, error)
 }
 
 // userRepository implements UserRepository
@@ -41,39 +49,90 @@ func (r *userRepository) FindByID(id uuid.UUID) (*models.User, error) {
 	return &user, nil
 }
 
+// FindByIDs retrieves the users with the given IDs in a single query; missing IDs are skipped
+func (r *userRepository) FindByIDs(ids []uuid.UUID) ([]models.User, error) {
+	var users []models.User
+	if len(ids) == 0 {
+		return users, nil
+	}
+	if err := r.db.Where("id IN ?", ids).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
 // FindByAppAndContact retrieves a user by app name, country code and phone
+// Numbers are compared in E.164 form so formatting and trunk zeros do not matter;
+// numbers that cannot be normalized fall back to an exact match
 func (r *userRepository) FindByAppAndContact(appName, countryCode, phone string) (*models.User, error) {
+	query := r.db.Where("app_name = ? AND country_code = ? AND phone = ?", appName, countryCode, phone)
+	if normalized, err := identifier.NormalizePhone(countryCode, phone); err == nil {
+		query = r.db.Where("app_name = ? AND phone_normalized = ?", appName, normalized)
+	}
+
 	var user models.User
-	if err := r.db.Where("app_name = ? AND country_code = ? AND phone = ?", appName, countryCode, phone).First(&user).Error; err != nil {
+	if err := query.Order("created_at ASC").First(&user).Error; err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
 // FindByAppAndEmail retrieves a user by app name and email
+// Addresses are compared in canonical form; addresses that cannot be normalized fall back to an exact match
 func (r *userRepository) FindByAppAndEmail(appName, email string) (*models.User, error) {
+	query := r.db.Where("app_name = ? AND email = ?", appName, email)
+	if normalized, err := identifier.NormalizeEmail(email); err == nil {
+		query = r.db.Where("app_name = ? AND email_normalized = ?", appName, normalized)
+	}
+
 	var user models.User
-	if err := r.db.Where("app_name = ? AND email = ?", appName, email).First(&user).Error; err != nil {
+	if err := query.Order("created_at ASC").First(&user).Error; err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
 // Update updates an existing user
+// Suspension is only changed through Suspend and Reinstate, so saving a stale copy cannot undo a suspension
 func (r *userRepository) Update(user *models.User) error {
-	return r.db.Save(user).Error
+	return r.db.Omit("suspended_at", "suspension_reason").Save(user).Error
 }
 
-// FindAllPaginated retrieves users with pagination and optional app_name filter
-func (r *userRepository) FindAllPaginated(appName string, page, pageSize int) ([]models.User, int64, error) {
+// Suspend suspends a user unless they already are
+// Returns false if the user was already suspended or does not exist
+func (r *userRepository) Suspend(id uuid.UUID, reason string) (bool, error) {
+	result := r.db.Model(&models.User{}).
+		Where("id = ? AND suspended_at IS NULL", id).
+		Updates(map[string]interface{}{"suspended_at": time.Now(), "suspension_reason": reason})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// Reinstate lifts a user's suspension
+// Returns false if the user was not suspended or does not exist
+func (r *userRepository) Reinstate(id uuid.UUID) (bool, error) {
+	result := r.db.Model(&models.User{}).
+		Where("id = ? AND suspended_at IS NOT NULL", id).
+		Updates(map[string]interface{}{"suspended_at": nil, "suspension_reason": nil})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// FindAllPaginated retrieves users with pagination, an optional app_name filter and a sort order
+// Crush count bounds and sorting by crushes count need FindAllWithCrushCounts
+func (r *userRepository) FindAllPaginated(filter models.UserListFilter, page, pageSize int) ([]models.User, int64, error) {
 	var users []models.User
 	var total int64
 
 	query := r.db.Model(&models.User{})
 
 	// Apply app_name filter if provided
-	if appName != "" {
-		query = query.Where("app_name = ?", appName)
+	if filter.AppName != "" {
+		query = query.Where("users.app_name = ?", filter.AppName)
 	}
 
 	// Get total count
@@ -85,9 +144,101 @@ func (r *userRepository) FindAllPaginated(appName string, page, pageSize int) ([
 	offset := (page - 1) * pageSize
 
 	// Get paginated results
-	if err := query.Order("created_at DESC").Offset(offset).Limit(pageSize).Find(&users).Error; err != nil {
+	if err := query.Order(userOrder(filter)).Offset(offset).Limit(pageSize).Find(&users).Error; err != nil {
 		return nil, 0, err
 	}
 
 	return users, total, nil
 }
+
+// crushCountsJoin joins each user's count of crushes, excluding deleted ones, as cc.crushes_count
+const crushCountsJoin = `LEFT JOIN (
+	SELECT user_id, COUNT(*) AS crushes_count FROM crushes WHERE deleted_at IS NULL GROUP BY user_id
+) cc ON cc.user_id = users.id`
+
+// FindAllWithCrushCounts retrieves users joined with their crushes count in a single query,
+// with pagination, app_name and crush count filters and a sort order
+func (r *userRepository) FindAllWithCrushCounts(filter models.UserListFilter, page, pageSize int) ([]models.UserWithCrushCount, int64, error) {
+	var users []models.UserWithCrushCount
+	var total int64
+
+	query := r.db.Model(&models.User{}).Joins(crushCountsJoin)
+
+	// Apply app_name and crush count filters if provided
+	if filter.AppName != "" {
+		query = query.Where("users.app_name = ?", filter.AppName)
+	}
+	if filter.MinCrushes != nil {
+		query = query.Where("COALESCE(cc.crushes_count, 0) >= ?", *filter.MinCrushes)
+	}
+	if filter.MaxCrushes != nil {
+		query = query.Where("COALESCE(cc.crushes_count, 0) <= ?", *filter.MaxCrushes)
+	}
+
+	// Get total count
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Calculate offset
+	offset := (page - 1) * pageSize
+
+	// Get paginated results
+	if err := query.Select("users.*, COALESCE(cc.crushes_count, 0) AS crushes_count").
+		Order(userOrder(filter)).
+		Offset(offset).
+		Limit(pageSize).
+		Find(&users).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
+
+// userOrder returns the ORDER BY clause for a listing; it defaults to newest first
+// Ties are broken by ID so pages do not overlap
+func userOrder(filter models.UserListFilter) string {
+	column := "users.created_at"
+	switch filter.SortBy {
+	case models.UserSortUpdatedAt:
+		column = "users.updated_at"
+	case models.UserSortName:
+		column = "users.name"
+	case models.UserSortAppName:
+		column = "users.app_name"
+	case models.UserSortCrushesCount:
+		column = "crushes_count"
+	}
+
+	direction := "ASC"
+	if filter.Descending {
+		direction = "DESC"
+	}
+	return column + " " + direction + " NULLS LAST, users.id ASC"
+}
+
+// IndexPhoneBatch re-saves up to limit users (including deleted ones) whose phone has no blind index yet
+// Saving fills the index so crush analytics can join users against crush targets; no other column is written
+// Returns the number of users indexed; 0 means none are left
+func (r *userRepository) IndexPhoneBatch(limit int) (int, error) {
+	var users []models.User
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().
+			Where("phone_normalized IS NOT NULL AND phone_hash IS NULL").
+			Order("created_at ASC").
+			Limit(limit).
+			Find(&users).Error; err != nil {
+			return err
+		}
+		for i := range users {
+			if err := tx.Unscoped().Select("phone_hash").Save(&users[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(users), nil
+}
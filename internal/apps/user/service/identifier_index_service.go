@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"go-backend/internal/apps/user/repository"
+)
+
+// identifierIndexBatchSize is the number of rows indexed per transaction
+const identifierIndexBatchSize = 500
+
+// IdentifierIndexService fills the blind indexes of users' phones and handles stored before indexing
+type IdentifierIndexService interface {
+	IndexIdentifiers(ctx context.Context) (int64, error)
+}
+
+// identifierIndexService implements IdentifierIndexService
+type identifierIndexService struct {
+	userRepo     repository.UserRepository
+	identityRepo repository.IdentityRepository
+}
+
+// NewIdentifierIndexService creates a new instance of IdentifierIndexService
+func NewIdentifierIndexService(userRepo repository.UserRepository, identityRepo repository.IdentityRepository) IdentifierIndexService {
+	return &identifierIndexService{userRepo: userRepo, identityRepo: identityRepo}
+}
+
+// IndexIdentifiers indexes unindexed users and identities in batches until none are left
+// Crush analytics join on the indexes, so this runs as a job right after the deploy
+// Returns the total number of rows indexed
+func (s *identifierIndexService) IndexIdentifiers(ctx context.Context) (int64, error) {
+	users, err := indexAll(ctx, s.userRepo.IndexPhoneBatch)
+	if err != nil {
+		return users, fmt.Errorf("failed to index users: %w", err)
+	}
+	identities, err := indexAll(ctx, s.identityRepo.IndexValueBatch)
+	if err != nil {
+		return users + identities, fmt.Errorf("failed to index identities: %w", err)
+	}
+
+	if users+identities > 0 {
+		fmt.Printf("[IdentifierIndex] Indexed %d users and %d identities\n", users, identities)
+	}
+	return users + identities, nil
+}
+
+// indexAll calls indexBatch until nothing is left to index or ctx is done
+func indexAll(ctx context.Context, indexBatch func(limit int) (int, error)) (int64, error) {
+	var total int64
+	for ctx.Err() == nil {
+		n, err := indexBatch(identifierIndexBatchSize)
+		if err != nil {
+			return total, err
+		}
+		if n == 0 {
+			return total, nil
+		}
+		total += int64(n)
+	}
+	return total, ctx.Err()
+}
@@ -0,0 +1,302 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"go-backend/internal/apps/user/models"
+	"go-backend/internal/apps/user/repository"
+	"go-backend/pkg/identifier"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// IdentityService defines business logic for users' social identities
+type IdentityService interface {
+	ListIdentities(userID uuid.UUID) ([]models.UserIdentity, error)
+	AddIdentity(userID uuid.UUID, req models.CreateIdentityRequest) (*models.UserIdentity, error)
+	UpdateIdentity(userID, identityID uuid.UUID, req models.UpdateIdentityRequest) (*models.UserIdentity, error)
+	DeleteIdentity(userID, identityID uuid.UUID) error
+	RequestVerification(userID, identityID uuid.UUID) (*models.UserIdentity, error)
+	ListPendingVerifications(appName string, page, pageSize int) (*models.PaginatedIdentitiesResponse, error)
+	SetVerified(userID, identityID uuid.UUID, verified bool) (*models.UserIdentity, error)
+}
+
+// verificationCodeByteCount is the number of random bytes in a verification code (8 hex characters)
+const verificationCodeByteCount = 4
+
+// identityService implements IdentityService
+type identityService struct {
+	repo     repository.IdentityRepository
+	userRepo repository.UserRepository
+}
+
+// NewIdentityService creates a new instance of IdentityService
+func NewIdentityService(repo repository.IdentityRepository, userRepo repository.UserRepository) IdentityService {
+	return &identityService{repo: repo, userRepo: userRepo}
+}
+
+// ListIdentities lists a user's identities
+func (s *identityService) ListIdentities(userID uuid.UUID) ([]models.UserIdentity, error) {
+	if _, err := s.findUser(userID); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByUserID(userID)
+}
+
+// AddIdentity claims a social handle for a user; a user has at most one identity per type
+func (s *identityService) AddIdentity(userID uuid.UUID, req models.CreateIdentityRequest) (*models.UserIdentity, error) {
+	user, err := s.findUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsSuspended() {
+		return nil, errors.New("user is suspended")
+	}
+
+	value, err := identifier.NormalizeHandle(req.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	identities, err := s.repo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, existing := range identities {
+		if existing.Type == req.Type {
+			return nil, errors.New("identity of this type already exists")
+		}
+	}
+
+	if err := s.ensureUnclaimed(user.AppName, req.Type, value); err != nil {
+		return nil, err
+	}
+
+	identity := &models.UserIdentity{
+		UserID:  userID,
+		AppName: user.AppName,
+		Type:    req.Type,
+		Value:   value,
+	}
+	if err := s.repo.Create(identity); err != nil {
+		if isUniqueViolation(err) {
+			return nil, errors.New("identity already claimed")
+		}
+		return nil, err
+	}
+	return identity, nil
+}
+
+// UpdateIdentity changes the handle of an identity and clears its verification
+func (s *identityService) UpdateIdentity(userID, identityID uuid.UUID, req models.UpdateIdentityRequest) (*models.UserIdentity, error) {
+	identity, err := s.findIdentity(userID, identityID)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.findUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsSuspended() {
+		return nil, errors.New("user is suspended")
+	}
+
+	value, err := identifier.NormalizeHandle(req.Value)
+	if err != nil {
+		return nil, err
+	}
+	if value == identity.Value {
+		return identity, nil
+	}
+
+	if err := s.ensureUnclaimed(identity.AppName, identity.Type, value); err != nil {
+		return nil, err
+	}
+
+	identity.Value = value
+	identity.Verified = false
+	identity.VerifiedAt = nil
+	identity.VerificationCode = nil
+	identity.VerificationRequestedAt = nil
+	if err := s.repo.Update(identity); err != nil {
+		if isUniqueViolation(err) {
+			return nil, errors.New("identity already claimed")
+		}
+		return nil, err
+	}
+	return identity, nil
+}
+
+// DeleteIdentity removes an identity
+func (s *identityService) DeleteIdentity(userID, identityID uuid.UUID) error {
+	identity, err := s.findIdentity(userID, identityID)
+	if err != nil {
+		return err
+	}
+	return s.repo.Delete(identity)
+}
+
+// RequestVerification issues a code for the owner to add to the account's bio and queues the identity for support
+// Requesting again replaces the code
+func (s *identityService) RequestVerification(userID, identityID uuid.UUID) (*models.UserIdentity, error) {
+	identity, err := s.findIdentity(userID, identityID)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.findUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsSuspended() {
+		return nil, errors.New("user is suspended")
+	}
+	if identity.Verified {
+		return nil, errors.New("identity already verified")
+	}
+	if err := s.ensureUnclaimed(identity.AppName, identity.Type, identity.Value); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, verificationCodeByteCount)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+	code := "verify-" + hex.EncodeToString(buf)
+	now := time.Now()
+	identity.VerificationCode = &code
+	identity.VerificationRequestedAt = &now
+	if err := s.repo.Update(identity); err != nil {
+		return nil, err
+	}
+	return identity, nil
+}
+
+// ListPendingVerifications lists the identities waiting for support to check their verification code
+func (s *identityService) ListPendingVerifications(appName string, page, pageSize int) (*models.PaginatedIdentitiesResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	identities, total, err := s.repo.FindPendingVerification(appName, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := int(total) / pageSize
+	if int(total)%pageSize > 0 {
+		totalPages++
+	}
+	var nextPage, prevPage *int
+	if page > 1 {
+		prev := page - 1
+		prevPage = &prev
+	}
+	if page < totalPages {
+		next := page + 1
+		nextPage = &next
+	}
+
+	return &models.PaginatedIdentitiesResponse{
+		Data:       identities,
+		Page:       page,
+		PageSize:   pageSize,
+		Total:      total,
+		TotalPages: totalPages,
+		NextPage:   nextPage,
+		PrevPage:   prevPage,
+	}, nil
+}
+
+// SetVerified marks an identity as verified (or not), e.g. after support found its code in the account's bio
+// Either way the pending verification request is closed
+func (s *identityService) SetVerified(userID, identityID uuid.UUID, verified bool) (*models.UserIdentity, error) {
+	identity, err := s.findIdentity(userID, identityID)
+	if err != nil {
+		return nil, err
+	}
+
+	if verified == identity.Verified && identity.VerificationRequestedAt == nil {
+		return identity, nil
+	}
+	if verified && !identity.Verified {
+		if err := s.ensureUnclaimed(identity.AppName, identity.Type, identity.Value); err != nil {
+			return nil, err
+		}
+	}
+	if verified != identity.Verified {
+		identity.Verified = verified
+		identity.VerifiedAt = nil
+		if verified {
+			now := time.Now()
+			identity.VerifiedAt = &now
+		}
+	}
+	identity.VerificationCode = nil
+	identity.VerificationRequestedAt = nil
+	if err := s.repo.Update(identity); err != nil {
+		if isUniqueViolation(err) {
+			return nil, errors.New("identity already claimed")
+		}
+		return nil, err
+	}
+	return identity, nil
+}
+
+// findUser loads a user, mapping a missing record to "user not found"
+func (s *identityService) findUser(userID uuid.UUID) (*models.User, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// findIdentity loads an identity and ensures it belongs to the user
+func (s *identityService) findIdentity(userID, identityID uuid.UUID) (*models.UserIdentity, error) {
+	identity, err := s.repo.FindByID(identityID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("identity not found")
+		}
+		return nil, err
+	}
+	if identity.UserID != userID {
+		return nil, errors.New("identity not found")
+	}
+	return identity, nil
+}
+
+// ensureUnclaimed checks that no user of the app has the handle verified
+// Unverified claims do not block others, so a handle cannot be squatted before its owner signs up
+func (s *identityService) ensureUnclaimed(appName, identityType, value string) error {
+	_, err := s.repo.FindVerifiedByValue(appName, identityType, value)
+	if err == nil {
+		return errors.New("identity already claimed")
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return nil
+}
+
+// isUniqueViolation reports whether err is a Postgres unique constraint violation (SQLSTATE 23505)
+func isUniqueViolation(err error) bool {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return true
+	}
+	return strings.Contains(err.Error(), "23505")
+}
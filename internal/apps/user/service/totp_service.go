@@ -0,0 +1,322 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"strings"
+	"time"
+
+	"go-backend/internal/apps/user/models"
+	"go-backend/internal/apps/user/repository"
+	"go-backend/pkg/secure"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	totpSkewSteps         = 1 // Accept the previous and next code to tolerate clock drift
+	totpMaxFailures       = 5
+	totpLockDuration      = 15 * time.Minute
+	recoveryCodeCount     = 10
+	recoveryCodeByteCount = 5 // 8 base32 characters per code
+)
+
+// errInvalidTOTPCode is returned for a wrong, replayed or already used code
+var errInvalidTOTPCode = errors.New("invalid totp code")
+
+// TOTPService defines business logic for authenticator-app second factors
+type TOTPService interface {
+	Enroll(userID uuid.UUID) (*models.TOTPEnrollmentResponse, error)
+	Confirm(userID uuid.UUID, code string) (*models.TOTPRecoveryCodesResponse, error)
+	Disable(userID uuid.UUID, code string) error
+	RegenerateRecoveryCodes(userID uuid.UUID, code string) (*models.TOTPRecoveryCodesResponse, error)
+	Status(userID uuid.UUID) (*models.TOTPStatusResponse, error)
+	IsEnrolled(userID uuid.UUID) (bool, error)
+	VerifySecondFactor(userID uuid.UUID, code string) error
+}
+
+// totpService implements TOTPService
+type totpService struct {
+	repo     repository.TOTPRepository
+	userRepo repository.UserRepository
+}
+
+// NewTOTPService creates a new instance of TOTPService
+func NewTOTPService(repo repository.TOTPRepository, userRepo repository.UserRepository) TOTPService {
+	return &totpService{repo: repo, userRepo: userRepo}
+}
+
+// Enroll starts (or restarts) TOTP enrolment and returns the secret and provisioning URI
+// The factor is not enforced until it is confirmed with a first code
+func (s *totpService) Enroll(userID uuid.UUID) (*models.TOTPEnrollmentResponse, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+
+	totp, err := s.repo.FindByUserID(userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if totp != nil && totp.ConfirmedAt != nil {
+		return nil, errors.New("totp already enabled")
+	}
+	if totp == nil {
+		totp = &models.UserTOTP{ID: uuid.New(), UserID: userID}
+	}
+
+	secret, err := secure.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := secure.EncryptString(secret)
+	if err != nil {
+		return nil, err
+	}
+	totp.Secret = encrypted
+	totp.LastUsedStep = 0
+	totp.FailedAttempts = 0
+	totp.LockedUntil = nil
+
+	if err := s.repo.Save(totp); err != nil {
+		return nil, err
+	}
+
+	return &models.TOTPEnrollmentResponse{
+		Secret:          secret,
+		ProvisioningURI: secure.TOTPProvisioningURI(user.AppName, totpAccountName(user), secret),
+	}, nil
+}
+
+// Confirm activates a pending enrolment with a first code and issues recovery codes
+func (s *totpService) Confirm(userID uuid.UUID, code string) (*models.TOTPRecoveryCodesResponse, error) {
+	totp, err := s.findTOTP(userID)
+	if err != nil {
+		return nil, err
+	}
+	if totp.ConfirmedAt != nil {
+		return nil, errors.New("totp already enabled")
+	}
+	if err := s.reserveAttempt(totp); err != nil {
+		return nil, err
+	}
+
+	step, ok, err := s.validateCode(totp, code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errInvalidTOTPCode
+	}
+
+	if err := s.repo.Confirm(totp.ID, step); err != nil {
+		return nil, err
+	}
+	return s.issueRecoveryCodes(userID)
+}
+
+// Disable removes the user's second factor; it requires a valid authenticator or recovery code
+func (s *totpService) Disable(userID uuid.UUID, code string) error {
+	totp, err := s.findTOTP(userID)
+	if err != nil {
+		return err
+	}
+	if totp.ConfirmedAt != nil {
+		if err := s.verify(totp, code, true); err != nil {
+			return err
+		}
+	}
+	return s.repo.Delete(userID)
+}
+
+// RegenerateRecoveryCodes replaces the user's recovery codes; it requires a valid authenticator code
+func (s *totpService) RegenerateRecoveryCodes(userID uuid.UUID, code string) (*models.TOTPRecoveryCodesResponse, error) {
+	totp, err := s.findTOTP(userID)
+	if err != nil {
+		return nil, err
+	}
+	if totp.ConfirmedAt == nil {
+		return nil, errors.New("totp not enabled")
+	}
+	if err := s.verify(totp, code, false); err != nil {
+		return nil, err
+	}
+	return s.issueRecoveryCodes(userID)
+}
+
+// Status reports whether the user has a pending or active second factor
+func (s *totpService) Status(userID uuid.UUID) (*models.TOTPStatusResponse, error) {
+	totp, err := s.repo.FindByUserID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &models.TOTPStatusResponse{}, nil
+		}
+		return nil, err
+	}
+
+	remaining, err := s.repo.CountUnusedRecoveryCodes(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TOTPStatusResponse{
+		Enabled:                totp.ConfirmedAt != nil,
+		Pending:                totp.ConfirmedAt == nil,
+		ConfirmedAt:            totp.ConfirmedAt,
+		RecoveryCodesRemaining: remaining,
+	}, nil
+}
+
+// IsEnrolled reports whether the user has a confirmed second factor
+func (s *totpService) IsEnrolled(userID uuid.UUID) (bool, error) {
+	totp, err := s.repo.FindByUserID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return totp.ConfirmedAt != nil, nil
+}
+
+// VerifySecondFactor checks an authenticator or recovery code during login
+func (s *totpService) VerifySecondFactor(userID uuid.UUID, code string) error {
+	totp, err := s.findTOTP(userID)
+	if err != nil {
+		return err
+	}
+	if totp.ConfirmedAt == nil {
+		return errors.New("totp not enabled")
+	}
+	return s.verify(totp, code, true)
+}
+
+// findTOTP loads the user's factor, mapping a missing row to "totp not enabled"
+func (s *totpService) findTOTP(userID uuid.UUID) (*models.UserTOTP, error) {
+	totp, err := s.repo.FindByUserID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("totp not enabled")
+		}
+		return nil, err
+	}
+	return totp, nil
+}
+
+// verify accepts a fresh authenticator code or, if allowed, an unused recovery code
+func (s *totpService) verify(totp *models.UserTOTP, code string, allowRecovery bool) error {
+	if err := s.reserveAttempt(totp); err != nil {
+		return err
+	}
+
+	step, ok, err := s.validateCode(totp, code)
+	if err != nil {
+		return err
+	}
+	if ok {
+		fresh, err := s.repo.MarkStepUsed(totp.ID, step)
+		if err != nil {
+			return err
+		}
+		if fresh {
+			return nil
+		}
+		// The code was already used; treat a replay like any other wrong code
+	} else if allowRecovery {
+		hash, err := hashRecoveryCode(totp.UserID, code)
+		if err != nil {
+			return err
+		}
+		consumed, err := s.repo.ConsumeRecoveryCode(totp.UserID, hash)
+		if err != nil {
+			return err
+		}
+		if consumed {
+			return s.repo.ClearFailures(totp.ID)
+		}
+	}
+
+	return errInvalidTOTPCode
+}
+
+// validateCode checks an authenticator code against the decrypted secret
+func (s *totpService) validateCode(totp *models.UserTOTP, code string) (int64, bool, error) {
+	secret, err := secure.DecryptString(totp.Secret)
+	if err != nil {
+		return 0, false, err
+	}
+	code = strings.ReplaceAll(strings.TrimSpace(code), " ", "")
+	return secure.ValidateTOTP(secret, code, time.Now(), totpSkewSteps)
+}
+
+// reserveAttempt counts an attempt before its code is checked and rejects it while the factor is locked
+// Accepted codes reset the count, so only failures accumulate towards the lock
+func (s *totpService) reserveAttempt(totp *models.UserTOTP) error {
+	reserved, err := s.repo.ReserveAttempt(totp.ID, totpMaxFailures, totpLockDuration)
+	if err != nil {
+		return err
+	}
+	if !reserved {
+		return errors.New("too many failed attempts, try again later")
+	}
+	return nil
+}
+
+// issueRecoveryCodes generates, stores and returns a new set of recovery codes
+func (s *totpService) issueRecoveryCodes(userID uuid.UUID) (*models.TOTPRecoveryCodesResponse, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		hash, err := hashRecoveryCode(userID, code)
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = code
+		hashes[i] = hash
+	}
+
+	if err := s.repo.ReplaceRecoveryCodes(userID, hashes); err != nil {
+		return nil, err
+	}
+	return &models.TOTPRecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// generateRecoveryCode returns a random code formatted as xxxx-xxxx
+func generateRecoveryCode() (string, error) {
+	buf := make([]byte, recoveryCodeByteCount)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	raw := strings.ToLower(base32.StdEncoding.EncodeToString(buf))
+	return raw[:4] + "-" + raw[4:], nil
+}
+
+// hashRecoveryCode normalises a recovery code and returns its keyed hash
+func hashRecoveryCode(userID uuid.UUID, code string) (string, error) {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	return secure.KeyedHash("recovery", userID.String(), normalized)
+}
+
+// totpAccountName picks the label shown in the authenticator app
+func totpAccountName(user *models.User) string {
+	if user.Email != nil && *user.Email != "" {
+		return *user.Email
+	}
+	if user.Phone != nil && *user.Phone != "" {
+		countryCode := ""
+		if user.CountryCode != nil {
+			countryCode = *user.CountryCode
+		}
+		return countryCode + *user.Phone
+	}
+	return user.ID.String()
+}
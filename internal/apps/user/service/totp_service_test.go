@@ -0,0 +1,211 @@
+package service
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"go-backend/internal/apps/user/models"
+	"go-backend/pkg/secure"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func TestMain(m *testing.M) {
+	os.Setenv("RAZORPAY_ENCRYPTION_KEY", "0123456789abcdef0123456789abcdef")
+	os.Setenv("DATA_HASH_KEY", "test-hash-key-that-is-at-least-32-bytes-long")
+	os.Exit(m.Run())
+}
+
+// fakeTOTPRepository keeps a single factor in memory, mirroring the conditional updates of totpRepository
+type fakeTOTPRepository struct {
+	totp *models.UserTOTP
+}
+
+func (r *fakeTOTPRepository) FindByUserID(userID uuid.UUID) (*models.UserTOTP, error) {
+	if r.totp == nil || r.totp.UserID != userID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *r.totp
+	return &copied, nil
+}
+
+func (r *fakeTOTPRepository) Save(totp *models.UserTOTP) error {
+	copied := *totp
+	r.totp = &copied
+	return nil
+}
+
+func (r *fakeTOTPRepository) Delete(userID uuid.UUID) error {
+	r.totp = nil
+	return nil
+}
+
+func (r *fakeTOTPRepository) Confirm(id uuid.UUID, step int64) error {
+	now := time.Now()
+	r.totp.ConfirmedAt = &now
+	r.totp.LastUsedStep = step
+	r.totp.FailedAttempts = 0
+	r.totp.LockedUntil = nil
+	return nil
+}
+
+func (r *fakeTOTPRepository) MarkStepUsed(id uuid.UUID, step int64) (bool, error) {
+	if r.totp.LastUsedStep >= step {
+		return false, nil
+	}
+	r.totp.LastUsedStep = step
+	r.totp.FailedAttempts = 0
+	r.totp.LockedUntil = nil
+	return true, nil
+}
+
+func (r *fakeTOTPRepository) ReserveAttempt(id uuid.UUID, maxFailures int, lockFor time.Duration) (bool, error) {
+	now := time.Now()
+	switch {
+	case r.totp.LockedUntil == nil && r.totp.FailedAttempts < maxFailures:
+		r.totp.FailedAttempts++
+	case r.totp.LockedUntil != nil && r.totp.LockedUntil.Before(now):
+		r.totp.FailedAttempts = 1
+		r.totp.LockedUntil = nil
+	default:
+		return false, nil
+	}
+	if r.totp.FailedAttempts >= maxFailures {
+		lockedUntil := now.Add(lockFor)
+		r.totp.LockedUntil = &lockedUntil
+	}
+	return true, nil
+}
+
+func (r *fakeTOTPRepository) ClearFailures(id uuid.UUID) error {
+	r.totp.FailedAttempts = 0
+	r.totp.LockedUntil = nil
+	return nil
+}
+
+func (r *fakeTOTPRepository) ReplaceRecoveryCodes(userID uuid.UUID, codeHashes []string) error {
+	return nil
+}
+
+func (r *fakeTOTPRepository) ConsumeRecoveryCode(userID uuid.UUID, codeHash string) (bool, error) {
+	return false, nil
+}
+
+func (r *fakeTOTPRepository) CountUnusedRecoveryCodes(userID uuid.UUID) (int64, error) {
+	return 0, nil
+}
+
+// newConfirmedTOTP returns a service with a confirmed factor and the factor's plaintext secret
+func newConfirmedTOTP(t *testing.T) (*totpService, *fakeTOTPRepository, string) {
+	t.Helper()
+	secret, err := secure.GenerateTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted, err := secure.EncryptString(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	confirmedAt := time.Now()
+	repo := &fakeTOTPRepository{totp: &models.UserTOTP{
+		ID:          uuid.New(),
+		UserID:      uuid.New(),
+		Secret:      encrypted,
+		ConfirmedAt: &confirmedAt,
+	}}
+	return &totpService{repo: repo}, repo, secret
+}
+
+// codeAt returns the factor's code for the time step offset steps from now
+func codeAt(t *testing.T, secret string, offset int64) string {
+	t.Helper()
+	code, err := secure.TOTPCode(secret, secure.TOTPStep(time.Now())+offset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return code
+}
+
+func TestVerifySecondFactorRejectsReplay(t *testing.T) {
+	tests := []struct {
+		name    string
+		first   int64 // Step offset of the accepted code
+		second  int64 // Step offset of the code tried next
+		wantErr string
+	}{
+		{"same code twice", 0, 0, "invalid totp code"},
+		{"older code after a newer one", 0, -1, "invalid totp code"},
+		{"newer code after an older one", -1, 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, repo, secret := newConfirmedTOTP(t)
+			userID := repo.totp.UserID
+
+			if err := svc.VerifySecondFactor(userID, codeAt(t, secret, tt.first)); err != nil {
+				t.Fatalf("first code rejected: %v", err)
+			}
+			err := svc.VerifySecondFactor(userID, codeAt(t, secret, tt.second))
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("second code rejected: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Fatalf("second code error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifySecondFactorWindow(t *testing.T) {
+	tests := []struct {
+		offset int64
+		wantOK bool
+	}{
+		{-2, false},
+		{-1, true},
+		{0, true},
+		{1, true},
+		{2, false},
+	}
+
+	for _, tt := range tests {
+		svc, repo, secret := newConfirmedTOTP(t)
+		err := svc.VerifySecondFactor(repo.totp.UserID, codeAt(t, secret, tt.offset))
+		if (err == nil) != tt.wantOK {
+			t.Errorf("code %d steps from now: error = %v, want accepted %v", tt.offset, err, tt.wantOK)
+		}
+	}
+}
+
+func TestVerifySecondFactorLockout(t *testing.T) {
+	svc, repo, secret := newConfirmedTOTP(t)
+	userID := repo.totp.UserID
+
+	for i := 0; i < totpMaxFailures; i++ {
+		if err := svc.VerifySecondFactor(userID, "000000"); err == nil || err.Error() != "invalid totp code" {
+			t.Fatalf("wrong code %d: error = %v, want invalid totp code", i+1, err)
+		}
+	}
+
+	// Once locked even a valid code is refused, and the failure count is kept
+	err := svc.VerifySecondFactor(userID, codeAt(t, secret, 0))
+	if err == nil || err.Error() != "too many failed attempts, try again later" {
+		t.Fatalf("valid code while locked: error = %v, want lockout", err)
+	}
+	if repo.totp.FailedAttempts != totpMaxFailures {
+		t.Errorf("failed attempts = %d, want %d", repo.totp.FailedAttempts, totpMaxFailures)
+	}
+
+	// After the lock expires a valid code is accepted and clears the count
+	expired := time.Now().Add(-time.Second)
+	repo.totp.LockedUntil = &expired
+	if err := svc.VerifySecondFactor(userID, codeAt(t, secret, 0)); err != nil {
+		t.Fatalf("valid code after lock expired: %v", err)
+	}
+	if repo.totp.FailedAttempts != 0 || repo.totp.LockedUntil != nil {
+		t.Errorf("failures not cleared: attempts %d, locked until %v", repo.totp.FailedAttempts, repo.totp.LockedUntil)
+	}
+}
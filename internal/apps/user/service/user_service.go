@@ -7,6 +7,7 @@ import (
 	crushRepository "go-backend/internal/apps/crush/repository"
 	"go-backend/internal/apps/user/models"
 	"go-backend/internal/apps/user/repository"
+	"go-backend/pkg/identifier"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -19,20 +20,29 @@ type UserService interface {
 	GetUserByID(id uuid.UUID) (*models.UserResponse, error)
 	GetUserByAppAndContact(appName, countryCode, phone string) (*models.UserResponse, error)
 	GetUserByAppAndEmail(appName, email string) (*models.UserResponse, error)
-	ListAllUsersPaginated(appName string, page, pageSize int) (*models.PaginatedUsersWithCountResponse, error)
+	ListAllUsersPaginated(filter models.UserListFilter, page, pageSize int) (*models.PaginatedUsersWithCountResponse, error)
+}
+
+// ContactVerifier confirms with a one-time code that the user holds a phone number or email address
+type ContactVerifier interface {
+	VerifyPhone(appName, countryCode, phone, code string) error
+	VerifyEmail(appName, email, code string) error
 }
 
 // userService implements UserService
 type userService struct {
 	repo      repository.UserRepository
 	crushRepo crushRepository.CrushRepository
+	verifier  ContactVerifier
 }
 
 // NewUserService creates a new instance of UserService
-func NewUserService(repo repository.UserRepository, crushRepo crushRepository.CrushRepository) UserService {
+// verifier confirms new phone numbers and emails before a user can switch to them
+func NewUserService(repo repository.UserRepository, crushRepo crushRepository.CrushRepository, verifier ContactVerifier) UserService {
 	return &userService{
 		repo:      repo,
 		crushRepo: crushRepo,
+		verifier:  verifier,
 	}
 }
 
@@ -48,11 +58,29 @@ func validateContactRule(countryCode, phone, email *string) error {
 	return nil
 }
 
+// validateIdentifierFormats ensures the phone number and email can be normalized
+func validateIdentifierFormats(countryCode, phone, email *string) error {
+	if countryCode != nil && phone != nil && strings.TrimSpace(*phone) != "" {
+		if _, err := identifier.ParsePhone(*countryCode, *phone); err != nil {
+			return err
+		}
+	}
+	if email != nil && strings.TrimSpace(*email) != "" {
+		if _, err := identifier.NormalizeEmail(*email); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // CreateUser creates a new user
 func (s *userService) CreateUser(req models.CreateUserRequest) (*models.UserResponse, error) {
 	if err := validateContactRule(req.CountryCode, req.Phone, req.Email); err != nil {
 		return nil, err
 	}
+	if err := validateIdentifierFormats(req.CountryCode, req.Phone, req.Email); err != nil {
+		return nil, err
+	}
 
 	// Build model
 	user := &models.User{
@@ -111,6 +139,7 @@ func (s *userService) GetUserByAppAndEmail(appName, email string) (*models.UserR
 }
 
 // UpdateUser updates an existing user
+// Changing the phone or email requires an OTP sent to the new value, so users cannot claim identifiers they do not hold
 func (s *userService) UpdateUser(id uuid.UUID, req models.UpdateUserRequest) (*models.UserResponse, error) {
 	user, err := s.repo.FindByID(id)
 	if err != nil {
@@ -119,6 +148,10 @@ func (s *userService) UpdateUser(id uuid.UUID, req models.UpdateUserRequest) (*m
 		}
 		return nil, err
 	}
+	if user.IsSuspended() {
+		return nil, errors.New("user is suspended")
+	}
+	previousPhone, previousEmail := user.PhoneNormalized, user.EmailNormalized
 
 	// Apply updates if provided
 	if req.Name != nil {
@@ -133,13 +166,6 @@ func (s *userService) UpdateUser(id uuid.UUID, req models.UpdateUserRequest) (*m
 	if req.Email != nil {
 		user.Email = req.Email
 	}
-	if req.AppName != nil {
-		trimmed := strings.TrimSpace(*req.AppName)
-		if trimmed == "" {
-			return nil, errors.New("app_name cannot be empty")
-		}
-		user.AppName = trimmed
-	}
 	// Merge metadata if provided (partial update)
 	if req.Metadata != nil && len(req.Metadata) > 0 {
 		if user.Metadata == nil {
@@ -154,6 +180,17 @@ func (s *userService) UpdateUser(id uuid.UUID, req models.UpdateUserRequest) (*m
 	if err := validateContactRule(user.CountryCode, user.Phone, user.Email); err != nil {
 		return nil, err
 	}
+	// Only changed identifiers are checked so legacy values do not block unrelated updates
+	var countryCode, phone *string
+	if req.CountryCode != nil || req.Phone != nil {
+		countryCode, phone = user.CountryCode, user.Phone
+	}
+	if err := validateIdentifierFormats(countryCode, phone, req.Email); err != nil {
+		return nil, err
+	}
+	if err := s.verifyContactChanges(user, previousPhone, previousEmail, req); err != nil {
+		return nil, err
+	}
 
 	if err := s.repo.Update(user); err != nil {
 		return nil, err
@@ -162,8 +199,43 @@ func (s *userService) UpdateUser(id uuid.UUID, req models.UpdateUserRequest) (*m
 	return &resp, nil
 }
 
-// ListAllUsersPaginated retrieves all users with pagination and optional app_name filter
-func (s *userService) ListAllUsersPaginated(appName string, page, pageSize int) (*models.PaginatedUsersWithCountResponse, error) {
+// verifyContactChanges checks the OTPs confirming a user's new phone number or email
+// Removing an identifier or re-entering the same one in another format needs no OTP
+func (s *userService) verifyContactChanges(user *models.User, previousPhone, previousEmail *string, req models.UpdateUserRequest) error {
+	user.NormalizeIdentifiers()
+
+	if user.PhoneNormalized != nil && !sameIdentifier(user.PhoneNormalized, previousPhone) {
+		if req.PhoneOTP == nil || *req.PhoneOTP == "" {
+			return errors.New("phone_otp is required to change phone")
+		}
+		if err := s.verifier.VerifyPhone(user.AppName, *user.CountryCode, *user.Phone, *req.PhoneOTP); err != nil {
+			return err
+		}
+	}
+	if user.EmailNormalized != nil && !sameIdentifier(user.EmailNormalized, previousEmail) {
+		if req.EmailOTP == nil || *req.EmailOTP == "" {
+			return errors.New("email_otp is required to change email")
+		}
+		if err := s.verifier.VerifyEmail(user.AppName, *user.Email, *req.EmailOTP); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sameIdentifier reports whether two optional normalized identifiers are equal
+func sameIdentifier(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// ListAllUsersPaginated retrieves all users with their crushes count, with pagination,
+// optional app_name and crush count filters and a sort order (default newest first)
+// Listings that sort or filter on crushes count are loaded joined with the counts,
+// others count the page's crushes in one batch
+func (s *userService) ListAllUsersPaginated(filter models.UserListFilter, page, pageSize int) (*models.PaginatedUsersWithCountResponse, error) {
 	// Validate page and pageSize
 	if page < 1 {
 		page = 1
@@ -175,33 +247,50 @@ func (s *userService) ListAllUsersPaginated(appName string, page, pageSize int)
 		pageSize = 100 // max page size
 	}
 
-	users, total, err := s.repo.FindAllPaginated(appName, page, pageSize)
-	if err != nil {
-		return nil, err
+	// Validate sort and crush count bounds
+	if filter.SortBy == "" {
+		filter.SortBy = models.UserSortCreatedAt
+	}
+	if !models.IsValidUserSort(filter.SortBy) {
+		return nil, errors.New("invalid sort")
 	}
+	if filter.MinCrushes != nil && filter.MaxCrushes != nil && *filter.MinCrushes > *filter.MaxCrushes {
+		return nil, errors.New("min_crushes cannot exceed max_crushes")
+	}
+
+	var responses []models.UserWithCountResponse
+	var total int64
+	if filter.NeedsCrushCounts() {
+		users, count, err := s.repo.FindAllWithCrushCounts(filter, page, pageSize)
+		if err != nil {
+			return nil, err
+		}
 
-	// Build response with crushes count
-	responses := make([]models.UserWithCountResponse, len(users))
-	for i, user := range users {
-		// Get crushes count for this user
-		crushesCount, err := s.crushRepo.CountByUserID(user.ID)
+		responses = make([]models.UserWithCountResponse, len(users))
+		for i, user := range users {
+			responses[i] = user.ToCountResponse(user.CrushesCount)
+		}
+		total = count
+	} else {
+		users, count, err := s.repo.FindAllPaginated(filter, page, pageSize)
 		if err != nil {
-			// If error counting crushes, set count to 0
-			crushesCount = 0
-		}
-
-		responses[i] = models.UserWithCountResponse{
-			ID:           user.ID,
-			Name:         user.Name,
-			CountryCode:  user.CountryCode,
-			Phone:        user.Phone,
-			Email:        user.Email,
-			AppName:      user.AppName,
-			Metadata:     user.Metadata,
-			CrushesCount: crushesCount,
-			CreatedAt:    user.CreatedAt,
-			UpdatedAt:    user.UpdatedAt,
+			return nil, err
+		}
+
+		userIDs := make([]uuid.UUID, len(users))
+		for i, user := range users {
+			userIDs[i] = user.ID
+		}
+		crushesCounts, err := s.crushRepo.CountByUserIDs(userIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		responses = make([]models.UserWithCountResponse, len(users))
+		for i, user := range users {
+			responses[i] = user.ToCountResponse(crushesCounts[user.ID])
 		}
+		total = count
 	}
 
 	// Calculate total pages
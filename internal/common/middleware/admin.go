@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Admin roles, from most to least privileged
+const (
+	RoleAdmin    = "admin"
+	RoleSupport  = "support"
+	RoleReadonly = "readonly"
+)
+
+// AdminAPIKeyHeader carries the admin API key on management requests
+const AdminAPIKeyHeader = "X-API-Key"
+
+const adminPrincipalContextKey = "auth.admin_principal"
+
+// AdminPrincipal identifies the admin API key behind a request
+type AdminPrincipal struct {
+	KeyID uuid.UUID
+	Name  string
+	Role  string
+}
+
+// AdminKeyResolver resolves a raw admin API key to the principal it belongs to
+type AdminKeyResolver interface {
+	ResolveAdminKey(key string) (*AdminPrincipal, error)
+}
+
+// AdminGuard builds middleware that only admits admin API keys holding one of the given roles
+// Calling it without roles admits any valid admin API key
+type AdminGuard func(roles ...string) gin.HandlerFunc
+
+// NewAdminGuard creates an AdminGuard backed by the given resolver
+func NewAdminGuard(resolver AdminKeyResolver) AdminGuard {
+	return func(roles ...string) gin.HandlerFunc {
+		return func(c *gin.Context) {
+			key := strings.TrimSpace(c.GetHeader(AdminAPIKeyHeader))
+			if key == "" {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "admin api key required"})
+				return
+			}
+
+			principal, err := resolver.ResolveAdminKey(key)
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+				return
+			}
+
+			if len(roles) > 0 && !hasRole(principal.Role, roles) {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "insufficient role"})
+				return
+			}
+
+			c.Set(adminPrincipalContextKey, principal)
+			c.Next()
+		}
+	}
+}
+
+// GetAdminPrincipal returns the admin principal stored by an AdminGuard
+func GetAdminPrincipal(c *gin.Context) (*AdminPrincipal, bool) {
+	value, exists := c.Get(adminPrincipalContextKey)
+	if !exists {
+		return nil, false
+	}
+	principal, ok := value.(*AdminPrincipal)
+	return principal, ok
+}
+
+func hasRole(role string, allowed []string) bool {
+	for _, r := range allowed {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
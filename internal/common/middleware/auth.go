@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const principalContextKey = "auth.principal"
+
+// Principal identifies the authenticated user behind a request
+type Principal struct {
+	UserID    uuid.UUID
+	AppName   string
+	ExpiresAt time.Time // When the access token stops being valid; long-lived responses must end by then
+}
+
+// PrincipalResolver resolves a bearer access token to the authenticated principal
+type PrincipalResolver interface {
+	ResolvePrincipal(token string) (*Principal, error)
+}
+
+// RequireAuth rejects requests without a valid bearer access token and stores the
+// authenticated principal on the gin context
+func RequireAuth(resolver PrincipalResolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		token, found := strings.CutPrefix(header, "Bearer ")
+		if !found || strings.TrimSpace(token) == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+			return
+		}
+
+		principal, err := resolver.ResolvePrincipal(strings.TrimSpace(token))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.Set(principalContextKey, principal)
+		c.Next()
+	}
+}
+
+// GetPrincipal returns the authenticated principal stored by RequireAuth
+func GetPrincipal(c *gin.Context) (*Principal, bool) {
+	value, exists := c.Get(principalContextKey)
+	if !exists {
+		return nil, false
+	}
+	principal, ok := value.(*Principal)
+	return principal, ok
+}
+
+// QueryTokenAuth copies an access_token query parameter into the Authorization header when none is set
+// It must run before RequireAuth, and only on routes whose clients cannot send headers (e.g. EventSource)
+// Request logs go through Logger, which keeps the token out of them
+func QueryTokenAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			if token := c.Query("access_token"); token != "" {
+				c.Request.Header.Set("Authorization", "Bearer "+token)
+			}
+		}
+		c.Next()
+	}
+}
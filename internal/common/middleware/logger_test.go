@@ -0,0 +1,27 @@
+package middleware
+
+import "testing"
+
+func TestRedactPath(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{"no query", "/api/v1/users/me", "/api/v1/users/me"},
+		{"nothing to redact", "/api/v1/crushes?page=2", "/api/v1/crushes?page=2"},
+		{"access token", "/api/v1/notifications/stream?access_token=secret", "/api/v1/notifications/stream?access_token=REDACTED"},
+		{"magic link token", "/api/v1/otp/email/magic-link/verify?token=secret", "/api/v1/otp/email/magic-link/verify?token=REDACTED"},
+		{"other params kept", "/stream?last_event_id=5&access_token=secret", "/stream?access_token=REDACTED&last_event_id=5"},
+		{"repeated token", "/verify?token=a&token=b", "/verify?token=REDACTED"},
+		{"unparseable query", "/verify?token=%zz", "/verify"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := redactPath(tt.path); got != tt.want {
+				t.Errorf("redactPath(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// trustedPlatforms maps TRUSTED_PLATFORM values to the client IP header set by that platform
+var trustedPlatforms = map[string]string{
+	"cloudflare": gin.PlatformCloudflare,
+	"appengine":  gin.PlatformGoogleAppEngine,
+	"flyio":      gin.PlatformFlyIO,
+}
+
+// SetupTrustedProxies configures which forwarding headers c.ClientIP() honours
+// TRUSTED_PROXIES is a comma-separated list of proxy IPs or CIDRs and TRUSTED_PLATFORM names a CDN or host;
+// with neither set no header is trusted and the client IP is the remote address
+func SetupTrustedProxies(router *gin.Engine) error {
+	if platform := strings.TrimSpace(os.Getenv("TRUSTED_PLATFORM")); platform != "" {
+		header, ok := trustedPlatforms[strings.ToLower(platform)]
+		if !ok {
+			return fmt.Errorf("unknown TRUSTED_PLATFORM %q", platform)
+		}
+		router.TrustedPlatform = header
+	}
+
+	var proxies []string
+	if proxiesEnv := os.Getenv("TRUSTED_PROXIES"); proxiesEnv != "" {
+		proxies = parseOrigins(proxiesEnv)
+	}
+	if err := router.SetTrustedProxies(proxies); err != nil {
+		return fmt.Errorf("invalid TRUSTED_PROXIES: %w", err)
+	}
+	return nil
+}
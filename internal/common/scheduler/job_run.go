@@ -0,0 +1,48 @@
+package scheduler
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Job run statuses
+const (
+	RunStatusRunning   = "running"
+	RunStatusSucceeded = "succeeded"
+	RunStatusFailed    = "failed"
+)
+
+// JobRun records a single execution of a scheduled job
+// (job_name, scheduled_at) is unique so each slot runs at most once across replicas
+type JobRun struct {
+	ID           uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	JobName      string     `gorm:"not null;size:100;uniqueIndex:idx_job_runs_slot" json:"job_name"`
+	ScheduledAt  time.Time  `gorm:"not null;uniqueIndex:idx_job_runs_slot" json:"scheduled_at"`
+	StartedAt    time.Time  `gorm:"not null" json:"started_at"`
+	FinishedAt   *time.Time `json:"finished_at,omitempty"`
+	Status       string     `gorm:"not null;size:20" json:"status"`
+	RowsAffected int64      `gorm:"not null;default:0" json:"rows_affected"`
+	Error        string     `gorm:"type:text" json:"error,omitempty"`
+	Instance     string     `gorm:"not null;size:255" json:"instance"`
+}
+
+// TableName sets the table name to 'job_runs'
+func (JobRun) TableName() string { return "job_runs" }
+
+// BeforeCreate hook to generate UUID before creating record
+func (r *JobRun) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+// JobInfo describes a registered job and its most recent run
+type JobInfo struct {
+	Name     string    `json:"name"`
+	Schedule string    `json:"schedule"`
+	NextRun  time.Time `json:"next_run"`
+	LastRun  *JobRun   `json:"last_run,omitempty"`
+}
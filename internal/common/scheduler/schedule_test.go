@@ -0,0 +1,98 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+// at parses a UTC time in "2006-01-02 15:04:05" form
+func at(t *testing.T, value string) time.Time {
+	t.Helper()
+	parsed, err := time.Parse("2006-01-02 15:04:05", value)
+	if err != nil {
+		t.Fatalf("invalid test time %q: %v", value, err)
+	}
+	return parsed
+}
+
+func TestScheduleNext(t *testing.T) {
+	tests := []struct {
+		name  string
+		expr  string
+		after string
+		want  string
+	}{
+		{"every 15 minutes", "*/15 * * * *", "2026-10-16 10:07:00", "2026-10-16 10:15:00"},
+		{"strictly after a matching minute", "*/15 * * * *", "2026-10-16 10:15:00", "2026-10-16 10:30:00"},
+		{"seconds are ignored", "*/15 * * * *", "2026-10-16 10:14:59", "2026-10-16 10:15:00"},
+		{"hourly rolls into the next day", "@hourly", "2026-10-16 23:30:00", "2026-10-17 00:00:00"},
+		{"daily", "@daily", "2026-10-16 00:00:00", "2026-10-17 00:00:00"},
+		{"monthly rolls into the next year", "@monthly", "2026-12-15 08:00:00", "2027-01-01 00:00:00"},
+		{"weekly runs on sunday", "@weekly", "2026-10-16 12:00:00", "2026-10-18 00:00:00"},
+		{"7 means sunday", "0 9 * * 7", "2026-10-16 12:00:00", "2026-10-18 09:00:00"},
+		{"list of hours", "30 9,17 * * *", "2026-10-16 10:00:00", "2026-10-16 17:30:00"},
+		{"range with step", "0-30/10 * * * *", "2026-10-16 10:31:00", "2026-10-16 11:00:00"},
+		{"value with step", "5/20 * * * *", "2026-10-16 10:26:00", "2026-10-16 10:45:00"},
+		{"weekdays only", "0 8 * * 1-5", "2026-10-16 09:00:00", "2026-10-19 08:00:00"},
+		{"day of month or day of week", "0 0 13 * 5", "2026-10-16 12:00:00", "2026-10-23 00:00:00"},
+		{"day of month alone", "0 0 13 * *", "2026-10-16 12:00:00", "2026-11-13 00:00:00"},
+		{"month end skips short months", "0 0 31 * *", "2026-10-31 00:00:00", "2026-12-31 00:00:00"},
+		{"leap day", "0 0 29 2 *", "2026-03-01 00:00:00", "2028-02-29 00:00:00"},
+		{"interval aligns to multiples", "@every 10m", "2026-10-16 10:07:30", "2026-10-16 10:10:00"},
+		{"interval strictly after a boundary", "@every 10m", "2026-10-16 10:10:00", "2026-10-16 10:20:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseSchedule(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseSchedule(%q) returned error: %v", tt.expr, err)
+			}
+			got := schedule.Next(at(t, tt.after))
+			if want := at(t, tt.want); !got.Equal(want) {
+				t.Errorf("ParseSchedule(%q).Next(%s) = %s, want %s", tt.expr, tt.after, got, want)
+			}
+		})
+	}
+}
+
+func TestScheduleNextNeverMatches(t *testing.T) {
+	schedule, err := ParseSchedule("0 0 31 2 *")
+	if err != nil {
+		t.Fatalf("ParseSchedule returned error: %v", err)
+	}
+	if got := schedule.Next(at(t, "2026-10-16 00:00:00")); !got.IsZero() {
+		t.Errorf("Next = %s, want the zero time for a date that never occurs", got)
+	}
+}
+
+func TestParseScheduleInvalid(t *testing.T) {
+	exprs := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * 32 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"*/-5 * * * *",
+		"10-5 * * * *",
+		"a * * * *",
+		"1-b * * * *",
+		"@yearly",
+		"@every",
+		"@every soon",
+		"@every 500ms",
+	}
+
+	for _, expr := range exprs {
+		t.Run(expr, func(t *testing.T) {
+			if _, err := ParseSchedule(expr); err == nil {
+				t.Errorf("ParseSchedule(%q) accepted an invalid expression", expr)
+			}
+		})
+	}
+}
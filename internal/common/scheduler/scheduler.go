@@ -0,0 +1,247 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// defaultJobTimeout bounds a single job run when the job does not set its own timeout
+const defaultJobTimeout = 10 * time.Minute
+
+// Job is a unit of background work run on a schedule
+// Run returns the number of rows it affected, which is recorded in the run history
+type Job struct {
+	Name     string
+	Schedule string
+	Timeout  time.Duration
+	Run      func(ctx context.Context) (int64, error)
+}
+
+// registeredJob is a job with its parsed schedule
+type registeredJob struct {
+	Job
+	schedule Schedule
+}
+
+// Scheduler runs registered jobs in-process on their schedules
+// Every replica runs the scheduler; a Postgres advisory lock per job and the unique
+// (job_name, scheduled_at) run record ensure each slot is executed by only one replica
+type Scheduler struct {
+	db       *gorm.DB
+	instance string
+
+	mu     sync.Mutex
+	jobs   []*registeredJob
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New creates a Scheduler that records runs and takes locks in the given database
+func New(db *gorm.DB) *Scheduler {
+	hostname, _ := os.Hostname()
+	return &Scheduler{
+		db:       db,
+		instance: fmt.Sprintf("%s-%d", hostname, os.Getpid()),
+	}
+}
+
+// Register adds a job; it must be called before Start
+func (s *Scheduler) Register(job Job) error {
+	if job.Name == "" || job.Run == nil {
+		return errors.New("job name and run function are required")
+	}
+	schedule, err := ParseSchedule(job.Schedule)
+	if err != nil {
+		return fmt.Errorf("job %s: %w", job.Name, err)
+	}
+	if job.Timeout <= 0 {
+		job.Timeout = defaultJobTimeout
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, existing := range s.jobs {
+		if existing.Name == job.Name {
+			return fmt.Errorf("job %s already registered", job.Name)
+		}
+	}
+	s.jobs = append(s.jobs, &registeredJob{Job: job, schedule: schedule})
+	return nil
+}
+
+// Start launches one goroutine per registered job
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ctx, s.cancel = context.WithCancel(ctx)
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(ctx, job)
+	}
+	fmt.Printf("[Scheduler] Started %d jobs on %s\n", len(s.jobs), s.instance)
+}
+
+// Stop cancels running jobs and waits for their goroutines to exit
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	cancel := s.cancel
+	s.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	s.wg.Wait()
+}
+
+// Jobs lists the registered jobs with their next scheduled time and most recent run
+func (s *Scheduler) Jobs() ([]JobInfo, error) {
+	s.mu.Lock()
+	jobs := append([]*registeredJob(nil), s.jobs...)
+	s.mu.Unlock()
+
+	now := time.Now()
+	infos := make([]JobInfo, 0, len(jobs))
+	for _, job := range jobs {
+		info := JobInfo{Name: job.Name, Schedule: job.Schedule, NextRun: job.schedule.Next(now)}
+		var last JobRun
+		err := s.db.Where("job_name = ?", job.Name).Order("started_at DESC").First(&last).Error
+		if err == nil {
+			info.LastRun = &last
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// Runs returns the most recent runs, optionally filtered by job name
+func (s *Scheduler) Runs(jobName string, limit int) ([]JobRun, error) {
+	var runs []JobRun
+	query := s.db.Order("started_at DESC").Limit(limit)
+	if jobName != "" {
+		query = query.Where("job_name = ?", jobName)
+	}
+	if err := query.Find(&runs).Error; err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
+
+// loop sleeps until each scheduled slot and runs the job
+func (s *Scheduler) loop(ctx context.Context, job *registeredJob) {
+	defer s.wg.Done()
+
+	for {
+		next := job.schedule.Next(time.Now())
+		if next.IsZero() {
+			fmt.Printf("[Scheduler] Job %s has no upcoming run, stopping\n", job.Name)
+			return
+		}
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if err := s.runOnce(ctx, job, next); err != nil {
+			fmt.Printf("[Scheduler] Job %s (slot %s) failed: %v\n", job.Name, next.Format(time.RFC3339), err)
+		}
+	}
+}
+
+// runOnce runs the job for a slot if this replica wins the job's advisory lock
+// The lock is transaction-scoped, so it is released even if the process dies mid-run
+func (s *Scheduler) runOnce(ctx context.Context, job *registeredJob, scheduledAt time.Time) error {
+	tx := s.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+	defer tx.Rollback()
+
+	var locked bool
+	if err := tx.Raw("SELECT pg_try_advisory_xact_lock(?)", lockKey(job.Name)).Scan(&locked).Error; err != nil {
+		return err
+	}
+	if !locked {
+		// Another replica is running this job
+		return nil
+	}
+
+	run := &JobRun{
+		JobName:     job.Name,
+		ScheduledAt: scheduledAt,
+		StartedAt:   time.Now(),
+		Status:      RunStatusRunning,
+		Instance:    s.instance,
+	}
+	// Recorded outside the lock transaction so the run is visible while it is in progress
+	if err := s.db.Create(run).Error; err != nil {
+		if isUniqueViolation(err) {
+			// Another replica already ran this slot
+			return nil
+		}
+		return err
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, job.Timeout)
+	defer cancel()
+	affected, runErr := safeRun(runCtx, job.Run)
+
+	finishedAt := time.Now()
+	updates := map[string]interface{}{
+		"finished_at":   finishedAt,
+		"status":        RunStatusSucceeded,
+		"rows_affected": affected,
+	}
+	if runErr != nil {
+		updates["status"] = RunStatusFailed
+		updates["error"] = runErr.Error()
+	}
+	if err := s.db.Model(&JobRun{}).Where("id = ?", run.ID).Updates(updates).Error; err != nil {
+		return err
+	}
+
+	fmt.Printf("[Scheduler] Job %s finished in %s: %d rows affected\n", job.Name, finishedAt.Sub(run.StartedAt).Round(time.Millisecond), affected)
+	if runErr != nil {
+		return runErr
+	}
+	return tx.Commit().Error
+}
+
+// safeRun calls the job, converting a panic into an error so one bad job cannot crash the server
+func safeRun(ctx context.Context, run func(ctx context.Context) (int64, error)) (affected int64, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+	return run(ctx)
+}
+
+// lockKey derives a stable advisory lock key from the job name
+func lockKey(jobName string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte("scheduler:" + jobName))
+	return int64(h.Sum64())
+}
+
+// isUniqueViolation reports whether err is a Postgres unique constraint violation (SQLSTATE 23505)
+func isUniqueViolation(err error) bool {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return true
+	}
+	return strings.Contains(err.Error(), "23505")
+}
@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Track failed verification attempts per OTP
+ALTER TABLE phone_otp ADD COLUMN IF NOT EXISTS attempts INT NOT NULL DEFAULT 0;
+ALTER TABLE email_otp ADD COLUMN IF NOT EXISTS attempts INT NOT NULL DEFAULT 0;
+
+-- Create otp_send_logs table used for resend throttling
+CREATE TABLE IF NOT EXISTS otp_send_logs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    app_name VARCHAR(255) NOT NULL,
+    channel VARCHAR(20) NOT NULL,
+    recipient VARCHAR(255) NOT NULL,
+    ip_address VARCHAR(64),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes for the hourly cap lookups
+CREATE INDEX idx_otp_send_logs_recipient ON otp_send_logs(app_name, channel, recipient, created_at);
+CREATE INDEX idx_otp_send_logs_ip ON otp_send_logs(app_name, ip_address, created_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS otp_send_logs;
+ALTER TABLE email_otp DROP COLUMN IF EXISTS attempts;
+ALTER TABLE phone_otp DROP COLUMN IF EXISTS attempts;
+
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- OTP values are now stored as hex-encoded keyed hashes.
+-- Existing plaintext OTPs cannot be verified against hashes, so drop them; clients simply request a new one.
+DELETE FROM phone_otp;
+DELETE FROM email_otp;
+
+ALTER TABLE phone_otp ALTER COLUMN value TYPE VARCHAR(64);
+ALTER TABLE email_otp ALTER COLUMN value TYPE VARCHAR(64);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DELETE FROM phone_otp;
+DELETE FROM email_otp;
+
+ALTER TABLE phone_otp ALTER COLUMN value TYPE VARCHAR(6);
+ALTER TABLE email_otp ALTER COLUMN value TYPE VARCHAR(6);
+
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Record which SMS provider delivered each phone OTP
+ALTER TABLE phone_otp ADD COLUMN IF NOT EXISTS provider VARCHAR(50);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE phone_otp DROP COLUMN IF EXISTS provider;
+
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Record the channel (sms, whatsapp, voice) each phone OTP was delivered on
+ALTER TABLE phone_otp ADD COLUMN IF NOT EXISTS channel VARCHAR(20);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE phone_otp DROP COLUMN IF EXISTS channel;
+
+-- +goose StatementEnd
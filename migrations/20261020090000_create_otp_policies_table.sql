@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Create otp_policies table; zero values and empty lists fall back to the server defaults
+CREATE TABLE IF NOT EXISTS otp_policies (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    app_name VARCHAR(100) NOT NULL,
+    ttl_seconds INT NOT NULL DEFAULT 0,
+    length INT NOT NULL DEFAULT 0,
+    resend_cooldown_seconds INT NOT NULL DEFAULT 0,
+    max_attempts INT NOT NULL DEFAULT 0,
+    hourly_recipient_cap INT NOT NULL DEFAULT 0,
+    hourly_ip_cap INT NOT NULL DEFAULT 0,
+    allowed_channels JSONB NOT NULL DEFAULT '[]',
+    allowed_country_codes JSONB NOT NULL DEFAULT '[]',
+    sender_name VARCHAR(100),
+    template_id VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+-- One live policy per app
+CREATE UNIQUE INDEX idx_otp_policies_app_name ON otp_policies(app_name) WHERE deleted_at IS NULL;
+CREATE INDEX idx_otp_policies_deleted_at ON otp_policies(deleted_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS otp_policies;
+
+-- +goose StatementEnd
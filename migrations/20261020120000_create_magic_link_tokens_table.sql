@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Create magic_link_tokens table; only a hash of each signed token is stored
+CREATE TABLE IF NOT EXISTS magic_link_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    app_name VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    redirect_url VARCHAR(2048) NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    consumed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create index for the resend cooldown lookup
+CREATE INDEX idx_magic_link_tokens_recipient ON magic_link_tokens(app_name, email, created_at);
+
+-- Whitelisted magic link redirect targets per app
+ALTER TABLE otp_policies ADD COLUMN IF NOT EXISTS allowed_redirect_urls JSONB NOT NULL DEFAULT '[]';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE otp_policies DROP COLUMN IF EXISTS allowed_redirect_urls;
+DROP TABLE IF EXISTS magic_link_tokens;
+
+-- +goose StatementEnd
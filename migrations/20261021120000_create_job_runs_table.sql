@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Create job_runs table; one row per executed schedule slot of a background job
+CREATE TABLE IF NOT EXISTS job_runs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    job_name VARCHAR(100) NOT NULL,
+    scheduled_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    finished_at TIMESTAMP WITH TIME ZONE,
+    status VARCHAR(20) NOT NULL,
+    rows_affected BIGINT NOT NULL DEFAULT 0,
+    error TEXT,
+    instance VARCHAR(255) NOT NULL
+);
+
+-- Each schedule slot runs at most once across replicas
+CREATE UNIQUE INDEX idx_job_runs_slot ON job_runs(job_name, scheduled_at);
+
+-- Create index for run history listings
+CREATE INDEX idx_job_runs_started_at ON job_runs(started_at DESC);
+
+-- Checkout link expiry for subscriptions still awaiting authorization
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS expire_by TIMESTAMP WITH TIME ZONE;
+
+-- Create indexes for the expiry sweeps
+CREATE INDEX IF NOT EXISTS idx_phone_otp_expires_at ON phone_otp(expires_at);
+CREATE INDEX IF NOT EXISTS idx_email_otp_expires_at ON email_otp(expires_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_email_otp_expires_at;
+DROP INDEX IF EXISTS idx_phone_otp_expires_at;
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS expire_by;
+DROP TABLE IF EXISTS job_runs;
+
+-- +goose StatementEnd
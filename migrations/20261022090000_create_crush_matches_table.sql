@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Create crush_matches table; each mutual pair is stored once with user_a_id < user_b_id
+CREATE TABLE IF NOT EXISTS crush_matches (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    app_name VARCHAR(100) NOT NULL,
+    user_a_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    user_b_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    crush_a_id UUID NOT NULL REFERENCES crushes(id) ON DELETE CASCADE,
+    crush_b_id UUID NOT NULL REFERENCES crushes(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_crush_matches_order CHECK (user_a_id < user_b_id)
+);
+
+-- One row per pair
+CREATE UNIQUE INDEX idx_crush_matches_pair ON crush_matches(user_a_id, user_b_id);
+
+-- Create index for listing a user's matches from either side
+CREATE INDEX idx_crush_matches_user_b_id ON crush_matches(user_b_id);
+
+-- Create indexes for resolving crush targets to users
+CREATE INDEX IF NOT EXISTS idx_users_instagram_id ON users((metadata->>'instagram_id')) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_users_snapchat_id ON users((metadata->>'snapchat_id')) WHERE deleted_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_users_snapchat_id;
+DROP INDEX IF EXISTS idx_users_instagram_id;
+DROP TABLE IF EXISTS crush_matches;
+
+-- +goose StatementEnd
@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Create crush_invites table; the invited phone number is only stored as a keyed hash
+CREATE TABLE IF NOT EXISTS crush_invites (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    app_name VARCHAR(100) NOT NULL,
+    target_hash VARCHAR(64) NOT NULL,
+    sender_user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    crush_id UUID NOT NULL REFERENCES crushes(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL,
+    provider VARCHAR(50),
+    error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create index for the per-target dedup window
+CREATE INDEX idx_crush_invites_target ON crush_invites(app_name, target_hash, created_at);
+
+-- Create index for the per-sender daily cap
+CREATE INDEX idx_crush_invites_sender ON crush_invites(sender_user_id, created_at);
+
+-- Create invite_opt_outs table; opt-outs apply across apps
+CREATE TABLE IF NOT EXISTS invite_opt_outs (
+    target_hash VARCHAR(64) PRIMARY KEY,
+    source VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS invite_opt_outs;
+DROP TABLE IF EXISTS crush_invites;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Crushes are active, archived by the user or expired by the app's policy; only active crushes match
+ALTER TABLE crushes ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'active';
+ALTER TABLE crushes ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP WITH TIME ZONE;
+
+-- Create index for the expiry sweep
+CREATE INDEX IF NOT EXISTS idx_crushes_expires_at ON crushes(expires_at) WHERE status = 'active' AND deleted_at IS NULL;
+
+-- Create index for counting a user's active crushes
+CREATE INDEX IF NOT EXISTS idx_crushes_user_id_status ON crushes(user_id, status);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_crushes_user_id_status;
+DROP INDEX IF EXISTS idx_crushes_expires_at;
+ALTER TABLE crushes DROP COLUMN IF EXISTS expires_at;
+ALTER TABLE crushes DROP COLUMN IF EXISTS status;
+-- +goose StatementEnd
@@ -0,0 +1,131 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Canonical identifiers used for lookups and crush matching (see pkg/identifier):
+-- phones in E.164 form, lowercased social handles without '@' or profile URLs, canonical emails.
+-- The application keeps them in sync on every save; the backfill below mirrors its rules,
+-- except that calling codes are not checked against the ITU list (rows are re-normalized when next saved).
+ALTER TABLE users ADD COLUMN IF NOT EXISTS phone_normalized VARCHAR(20);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_normalized VARCHAR(255);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS instagram_normalized VARCHAR(255);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS snapchat_normalized VARCHAR(255);
+
+ALTER TABLE crushes ADD COLUMN IF NOT EXISTS phone_normalized VARCHAR(20);
+ALTER TABLE crushes ADD COLUMN IF NOT EXISTS instagram_normalized VARCHAR(255);
+ALTER TABLE crushes ADD COLUMN IF NOT EXISTS snapchat_normalized VARCHAR(255);
+
+-- Session-scoped helpers for the backfill
+CREATE FUNCTION pg_temp.normalize_phone(country_code TEXT, phone TEXT) RETURNS TEXT AS $$
+DECLARE
+    cc TEXT := regexp_replace(coalesce(country_code, ''), '[^0-9]', '', 'g');
+    digits TEXT := regexp_replace(coalesce(phone, ''), '[[:space:]()./-]', '', 'g');
+BEGIN
+    IF digits ~ '^(\+|00)' THEN
+        -- International form carries its own calling code
+        digits := regexp_replace(digits, '^(\+|00)', '');
+        cc := '';
+    ELSE
+        cc := regexp_replace(cc, '^00', '');
+        digits := ltrim(digits, '0');
+        IF cc !~ '^[1-9][0-9]{0,2}$' THEN
+            RETURN NULL;
+        END IF;
+    END IF;
+    IF digits !~ '^[0-9]+$' OR length(cc || digits) > 15 OR length(digits) < 4 THEN
+        RETURN NULL;
+    END IF;
+    RETURN '+' || cc || digits;
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+CREATE FUNCTION pg_temp.normalize_handle(handle TEXT) RETURNS TEXT AS $$
+DECLARE
+    h TEXT := lower(btrim(coalesce(handle, '')));
+BEGIN
+    h := regexp_replace(h, '[?#].*$', '');
+    h := regexp_replace(h, '^.*?://', '');
+    h := regexp_replace(h, '^www\.', '');
+    IF position('/' IN h) > 0 THEN
+        -- Profile URL: the handle is the only path segment, optionally after add/ or u/
+        IF h !~ '^[^/]*\.[^/]*/+((add|u)/+)?[^/]+/*$' THEN
+            RETURN NULL;
+        END IF;
+        h := regexp_replace(h, '^[^/]*/+((add|u)/+)?([^/]+)/*$', '\3');
+    END IF;
+    h := ltrim(h, '@');
+    IF h !~ '^[a-z0-9._-]{1,30}$' THEN
+        RETURN NULL;
+    END IF;
+    RETURN h;
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+CREATE FUNCTION pg_temp.normalize_email(email TEXT) RETURNS TEXT AS $$
+DECLARE
+    e TEXT := lower(btrim(coalesce(email, '')));
+    local_part TEXT;
+    domain_part TEXT;
+BEGIN
+    IF e !~ '^[^@[:space:]]+@[^@[:space:]]+\.[^@[:space:]]+$' THEN
+        RETURN NULL;
+    END IF;
+    local_part := split_part(e, '@', 1);
+    domain_part := split_part(e, '@', 2);
+    IF domain_part IN ('gmail.com', 'googlemail.com') THEN
+        domain_part := 'gmail.com';
+        local_part := replace(split_part(local_part, '+', 1), '.', '');
+        IF local_part = '' THEN
+            RETURN NULL;
+        END IF;
+    END IF;
+    RETURN local_part || '@' || domain_part;
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+UPDATE users SET
+    phone_normalized = CASE WHEN country_code IS NOT NULL AND phone IS NOT NULL THEN pg_temp.normalize_phone(country_code, phone) END,
+    email_normalized = CASE WHEN email IS NOT NULL THEN pg_temp.normalize_email(email) END,
+    instagram_normalized = pg_temp.normalize_handle(metadata->>'instagram_id'),
+    snapchat_normalized = pg_temp.normalize_handle(metadata->>'snapchat_id');
+
+UPDATE crushes SET
+    phone_normalized = CASE WHEN country_code IS NOT NULL AND phone IS NOT NULL THEN pg_temp.normalize_phone(country_code, phone) END,
+    instagram_normalized = pg_temp.normalize_handle(instagram_id),
+    snapchat_normalized = pg_temp.normalize_handle(snapchat_id);
+
+DROP FUNCTION pg_temp.normalize_phone(TEXT, TEXT);
+DROP FUNCTION pg_temp.normalize_handle(TEXT);
+DROP FUNCTION pg_temp.normalize_email(TEXT);
+
+-- Create indexes for login lookups and for resolving crush targets to users
+CREATE INDEX IF NOT EXISTS idx_users_app_phone_normalized ON users(app_name, phone_normalized) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_users_app_email_normalized ON users(app_name, email_normalized) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_users_app_instagram_normalized ON users(app_name, instagram_normalized) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_users_app_snapchat_normalized ON users(app_name, snapchat_normalized) WHERE deleted_at IS NULL;
+
+-- Create indexes for finding crushes on a user
+CREATE INDEX IF NOT EXISTS idx_crushes_phone_normalized ON crushes(phone_normalized) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_crushes_instagram_normalized ON crushes(instagram_normalized) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_crushes_snapchat_normalized ON crushes(snapchat_normalized) WHERE deleted_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_crushes_snapchat_normalized;
+DROP INDEX IF EXISTS idx_crushes_instagram_normalized;
+DROP INDEX IF EXISTS idx_crushes_phone_normalized;
+DROP INDEX IF EXISTS idx_users_app_snapchat_normalized;
+DROP INDEX IF EXISTS idx_users_app_instagram_normalized;
+DROP INDEX IF EXISTS idx_users_app_email_normalized;
+DROP INDEX IF EXISTS idx_users_app_phone_normalized;
+
+ALTER TABLE crushes DROP COLUMN IF EXISTS snapchat_normalized;
+ALTER TABLE crushes DROP COLUMN IF EXISTS instagram_normalized;
+ALTER TABLE crushes DROP COLUMN IF EXISTS phone_normalized;
+
+ALTER TABLE users DROP COLUMN IF EXISTS snapchat_normalized;
+ALTER TABLE users DROP COLUMN IF EXISTS instagram_normalized;
+ALTER TABLE users DROP COLUMN IF EXISTS email_normalized;
+ALTER TABLE users DROP COLUMN IF EXISTS phone_normalized;
+-- +goose StatementEnd
@@ -0,0 +1,64 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Create user_identities table; social handles users are matched on, stored normalized
+CREATE TABLE IF NOT EXISTS user_identities (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    app_name VARCHAR(100) NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    value VARCHAR(255) NOT NULL,
+    verified BOOLEAN NOT NULL DEFAULT FALSE,
+    verified_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_user_identities_type CHECK (type IN ('instagram', 'snapchat'))
+);
+
+-- A handle can be claimed by one user per app
+CREATE UNIQUE INDEX idx_user_identities_app_type_value ON user_identities(app_name, type, value);
+
+-- One identity per type per user
+CREATE UNIQUE INDEX idx_user_identities_user_type ON user_identities(user_id, type);
+
+-- Move the handles kept in users.metadata across; when several users share a handle the earliest account keeps it
+INSERT INTO user_identities (user_id, app_name, type, value)
+SELECT id, app_name, 'instagram', instagram_normalized
+FROM users
+WHERE instagram_normalized IS NOT NULL AND deleted_at IS NULL
+ORDER BY created_at
+ON CONFLICT DO NOTHING;
+
+INSERT INTO user_identities (user_id, app_name, type, value)
+SELECT id, app_name, 'snapchat', snapchat_normalized
+FROM users
+WHERE snapchat_normalized IS NOT NULL AND deleted_at IS NULL
+ORDER BY created_at
+ON CONFLICT DO NOTHING;
+
+-- Handles are now read from user_identities only
+DROP INDEX IF EXISTS idx_users_app_instagram_normalized;
+DROP INDEX IF EXISTS idx_users_app_snapchat_normalized;
+ALTER TABLE users DROP COLUMN IF EXISTS instagram_normalized;
+ALTER TABLE users DROP COLUMN IF EXISTS snapchat_normalized;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN IF NOT EXISTS instagram_normalized VARCHAR(255);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS snapchat_normalized VARCHAR(255);
+
+UPDATE users SET instagram_normalized = ui.value
+FROM user_identities ui
+WHERE ui.user_id = users.id AND ui.type = 'instagram';
+
+UPDATE users SET snapchat_normalized = ui.value
+FROM user_identities ui
+WHERE ui.user_id = users.id AND ui.type = 'snapchat';
+
+CREATE INDEX IF NOT EXISTS idx_users_app_instagram_normalized ON users(app_name, instagram_normalized) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_users_app_snapchat_normalized ON users(app_name, snapchat_normalized) WHERE deleted_at IS NULL;
+
+DROP TABLE IF EXISTS user_identities;
+-- +goose StatementEnd
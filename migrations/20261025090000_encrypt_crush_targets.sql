@@ -0,0 +1,73 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Crush target identifiers are stored encrypted (pkg/secure, RAZORPAY_ENCRYPTION_KEY) and matched
+-- through blind indexes: keyed hashes (DATA_HASH_KEY) of the normalized identifiers.
+-- Encryption happens in the application, so existing rows are migrated by the server's encrypt_legacy_crushes
+-- job (or `go run ./cmd/encryptcrushes`), which clears the plaintext phone, instagram_id and snapchat_id columns.
+-- Rows take part in matching again once they have been migrated, within minutes of the deploy.
+ALTER TABLE crushes ADD COLUMN IF NOT EXISTS phone_encrypted TEXT;
+ALTER TABLE crushes ADD COLUMN IF NOT EXISTS instagram_encrypted TEXT;
+ALTER TABLE crushes ADD COLUMN IF NOT EXISTS snapchat_encrypted TEXT;
+
+ALTER TABLE crushes ADD COLUMN IF NOT EXISTS phone_hash VARCHAR(64);
+ALTER TABLE crushes ADD COLUMN IF NOT EXISTS instagram_hash VARCHAR(64);
+ALTER TABLE crushes ADD COLUMN IF NOT EXISTS snapchat_hash VARCHAR(64);
+
+-- At least one contact method must be provided, in clear (not yet migrated) or encrypted
+ALTER TABLE crushes DROP CONSTRAINT IF EXISTS chk_crushes_contact_method;
+ALTER TABLE crushes ADD CONSTRAINT chk_crushes_contact_method
+    CHECK (
+        (country_code IS NOT NULL AND phone IS NOT NULL) OR
+        (instagram_id IS NOT NULL AND instagram_id != '') OR
+        (snapchat_id IS NOT NULL AND snapchat_id != '') OR
+        phone_encrypted IS NOT NULL OR
+        instagram_encrypted IS NOT NULL OR
+        snapchat_encrypted IS NOT NULL
+    );
+
+-- Normalized identifiers are now derived in memory and matched through the hashes
+DROP INDEX IF EXISTS idx_crushes_snapchat_normalized;
+DROP INDEX IF EXISTS idx_crushes_instagram_normalized;
+DROP INDEX IF EXISTS idx_crushes_phone_normalized;
+ALTER TABLE crushes DROP COLUMN IF EXISTS snapchat_normalized;
+ALTER TABLE crushes DROP COLUMN IF EXISTS instagram_normalized;
+ALTER TABLE crushes DROP COLUMN IF EXISTS phone_normalized;
+
+-- Create indexes for finding crushes on a user
+CREATE INDEX IF NOT EXISTS idx_crushes_phone_hash ON crushes(phone_hash) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_crushes_instagram_hash ON crushes(instagram_hash) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_crushes_snapchat_hash ON crushes(snapchat_hash) WHERE deleted_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- Encrypted identifiers cannot be decrypted in SQL; run the down migration only before encrypting any crushes
+DROP INDEX IF EXISTS idx_crushes_snapchat_hash;
+DROP INDEX IF EXISTS idx_crushes_instagram_hash;
+DROP INDEX IF EXISTS idx_crushes_phone_hash;
+
+ALTER TABLE crushes ADD COLUMN IF NOT EXISTS phone_normalized VARCHAR(20);
+ALTER TABLE crushes ADD COLUMN IF NOT EXISTS instagram_normalized VARCHAR(255);
+ALTER TABLE crushes ADD COLUMN IF NOT EXISTS snapchat_normalized VARCHAR(255);
+CREATE INDEX IF NOT EXISTS idx_crushes_phone_normalized ON crushes(phone_normalized) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_crushes_instagram_normalized ON crushes(instagram_normalized) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_crushes_snapchat_normalized ON crushes(snapchat_normalized) WHERE deleted_at IS NULL;
+
+ALTER TABLE crushes DROP CONSTRAINT IF EXISTS chk_crushes_contact_method;
+ALTER TABLE crushes ADD CONSTRAINT chk_crushes_contact_method
+    CHECK (
+        (country_code IS NOT NULL AND phone IS NOT NULL) OR
+        (instagram_id IS NOT NULL AND instagram_id != '') OR
+        (snapchat_id IS NOT NULL AND snapchat_id != '')
+    );
+
+ALTER TABLE crushes DROP COLUMN IF EXISTS snapchat_hash;
+ALTER TABLE crushes DROP COLUMN IF EXISTS instagram_hash;
+ALTER TABLE crushes DROP COLUMN IF EXISTS phone_hash;
+
+ALTER TABLE crushes DROP COLUMN IF EXISTS snapchat_encrypted;
+ALTER TABLE crushes DROP COLUMN IF EXISTS instagram_encrypted;
+ALTER TABLE crushes DROP COLUMN IF EXISTS phone_encrypted;
+-- +goose StatementEnd
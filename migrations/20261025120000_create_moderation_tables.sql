@@ -0,0 +1,75 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Suspended users cannot sign in, edit their profile or use Crush Connect
+ALTER TABLE users ADD COLUMN IF NOT EXISTS suspended_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS suspension_reason VARCHAR(600);
+
+-- Create blocks table; a block stops the blocked user from adding the blocker as a crush
+CREATE TABLE IF NOT EXISTS blocks (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    app_name VARCHAR(100) NOT NULL,
+    blocker_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    blocked_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    crush_id UUID,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_blocks_not_self CHECK (blocker_id <> blocked_id)
+);
+
+-- A user blocks another user at most once
+CREATE UNIQUE INDEX idx_blocks_blocker_blocked ON blocks(blocker_id, blocked_id);
+
+-- Create index for checking blocks against the blocked user
+CREATE INDEX idx_blocks_blocked_id ON blocks(blocked_id);
+
+-- Create reports table; abuse reports against users and their crushes
+CREATE TABLE IF NOT EXISTS reports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    app_name VARCHAR(100) NOT NULL,
+    reporter_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reported_user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    crush_id UUID,
+    reason VARCHAR(50) NOT NULL,
+    details VARCHAR(1000),
+    evidence JSONB NOT NULL DEFAULT '{}',
+    status VARCHAR(20) NOT NULL DEFAULT 'open',
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_reports_reason CHECK (reason IN ('harassment', 'inappropriate_name', 'inappropriate_metadata', 'spam', 'impersonation', 'other')),
+    CONSTRAINT chk_reports_status CHECK (status IN ('open', 'actioned', 'dismissed'))
+);
+
+-- Create index for the moderation queue
+CREATE INDEX idx_reports_status_created_at ON reports(status, created_at);
+
+-- Create index for a reported user's history
+CREATE INDEX idx_reports_reported_user_id ON reports(reported_user_id);
+
+-- Create moderation_actions table; audit log of actions taken by admin keys
+CREATE TABLE IF NOT EXISTS moderation_actions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    report_id UUID REFERENCES reports(id) ON DELETE SET NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    action VARCHAR(20) NOT NULL,
+    note VARCHAR(500),
+    affected BIGINT NOT NULL DEFAULT 0,
+    admin_key_id UUID NOT NULL,
+    admin_name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes for a report's and a user's moderation history
+CREATE INDEX idx_moderation_actions_report_id ON moderation_actions(report_id);
+CREATE INDEX idx_moderation_actions_user_id ON moderation_actions(user_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS moderation_actions;
+DROP TABLE IF EXISTS reports;
+DROP TABLE IF EXISTS blocks;
+ALTER TABLE users DROP COLUMN IF EXISTS suspension_reason;
+ALTER TABLE users DROP COLUMN IF EXISTS suspended_at;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Blind indexes of users' own identifiers, sharing the crush target index so signups can be joined to crushes
+-- Existing rows are indexed by the server's index_identifiers job (or by running cmd/indexidentifiers)
+ALTER TABLE users ADD COLUMN IF NOT EXISTS phone_hash VARCHAR(64);
+ALTER TABLE user_identities ADD COLUMN IF NOT EXISTS value_hash VARCHAR(64);
+
+-- Create indexes for joining crush targets to the users they name
+CREATE INDEX IF NOT EXISTS idx_users_app_name_phone_hash ON users(app_name, phone_hash);
+CREATE INDEX IF NOT EXISTS idx_user_identities_app_name_type_value_hash ON user_identities(app_name, type, value_hash);
+
+-- Create index for crush analytics by date
+CREATE INDEX IF NOT EXISTS idx_crushes_created_at ON crushes(created_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_crushes_created_at;
+DROP INDEX IF EXISTS idx_user_identities_app_name_type_value_hash;
+DROP INDEX IF EXISTS idx_users_app_name_phone_hash;
+ALTER TABLE user_identities DROP COLUMN IF EXISTS value_hash;
+ALTER TABLE users DROP COLUMN IF EXISTS phone_hash;
+-- +goose StatementEnd
@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Create razorpay_webhook_events table; every webhook delivery is stored once per Razorpay event ID
+CREATE TABLE IF NOT EXISTS razorpay_webhook_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    event_id VARCHAR(100) NOT NULL,
+    event_type VARCHAR(100),
+    razorpay_subscription_id VARCHAR(100),
+    payload TEXT NOT NULL,
+    signature_valid BOOLEAN NOT NULL DEFAULT FALSE,
+    status VARCHAR(20) NOT NULL DEFAULT 'received',
+    error TEXT,
+    event_created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    processed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_razorpay_webhook_events_status CHECK (status IN ('received', 'processed', 'stale', 'ignored', 'failed'))
+);
+
+-- Redeliveries of an event are recognized by its event ID
+CREATE UNIQUE INDEX IF NOT EXISTS idx_razorpay_webhook_events_event_id ON razorpay_webhook_events(event_id);
+
+-- Create index for a subscription's events in Razorpay order
+CREATE INDEX IF NOT EXISTS idx_razorpay_webhook_events_subscription ON razorpay_webhook_events(razorpay_subscription_id, event_created_at);
+
+-- Create index for finding failed events
+CREATE INDEX IF NOT EXISTS idx_razorpay_webhook_events_status_created_at ON razorpay_webhook_events(status, created_at);
+
+-- Newest webhook event applied to a subscription; older events arriving later cannot regress it
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS last_webhook_event_at TIMESTAMP WITH TIME ZONE;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS last_webhook_event_at;
+DROP TABLE IF EXISTS razorpay_webhook_events;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Keep each webhook delivery's signature so replayed events are verified again
+-- Events stored before this column existed cannot be replayed
+ALTER TABLE razorpay_webhook_events ADD COLUMN IF NOT EXISTS signature VARCHAR(128);
+
+-- Create index for replaying a time range of events in Razorpay order
+CREATE INDEX IF NOT EXISTS idx_razorpay_webhook_events_status_event_created_at ON razorpay_webhook_events(status, event_created_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_razorpay_webhook_events_status_event_created_at;
+ALTER TABLE razorpay_webhook_events DROP COLUMN IF EXISTS signature;
+-- +goose StatementEnd
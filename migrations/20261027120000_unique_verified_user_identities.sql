@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Only a verified claim reserves a handle; unverified claims no longer lock its owner out
+DROP INDEX IF EXISTS idx_user_identities_app_type_value;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_identities_app_type_value_verified ON user_identities(app_name, type, value) WHERE verified;
+
+-- Create index for looking up the claims on a handle
+CREATE INDEX IF NOT EXISTS idx_user_identities_app_type_value ON user_identities(app_name, type, value);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_user_identities_app_type_value;
+DROP INDEX IF EXISTS idx_user_identities_app_type_value_verified;
+
+-- When several users claim a handle the verified claim, then the earliest, keeps it
+DELETE FROM user_identities ui
+USING user_identities other
+WHERE ui.app_name = other.app_name AND ui.type = other.type AND ui.value = other.value AND ui.id <> other.id
+  AND (other.verified, ui.created_at, ui.id) > (ui.verified, other.created_at, other.id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_identities_app_type_value ON user_identities(app_name, type, value);
+-- +goose StatementEnd
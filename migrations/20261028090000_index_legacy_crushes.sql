@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- The encrypt_legacy_crushes job looks for crushes still holding target identifiers in clear every few minutes;
+-- the partial index keeps that lookup cheap and is empty once every crush is encrypted
+CREATE INDEX IF NOT EXISTS idx_crushes_legacy_plaintext ON crushes(created_at)
+    WHERE phone IS NOT NULL OR instagram_id IS NOT NULL OR snapchat_id IS NOT NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_crushes_legacy_plaintext;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Users verify a handle by requesting a code, adding it to the account's bio and having support confirm it there.
+-- Handles migrated from users.metadata were never checked, so they stay unverified and are not matched until
+-- their owners go through the same flow; clients prompt for it on identities with verified = false.
+ALTER TABLE user_identities ADD COLUMN IF NOT EXISTS verification_code VARCHAR(20);
+ALTER TABLE user_identities ADD COLUMN IF NOT EXISTS verification_requested_at TIMESTAMP WITH TIME ZONE;
+
+-- Create index for support's queue of pending verification requests
+CREATE INDEX IF NOT EXISTS idx_user_identities_verification_requested_at ON user_identities(verification_requested_at)
+    WHERE verification_requested_at IS NOT NULL AND NOT verified;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_user_identities_verification_requested_at;
+ALTER TABLE user_identities DROP COLUMN IF EXISTS verification_requested_at;
+ALTER TABLE user_identities DROP COLUMN IF EXISTS verification_code;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- The index_identifiers job looks for users and identities without a blind index every few minutes;
+-- the partial indexes keep those lookups cheap and are empty once every row is indexed
+CREATE INDEX IF NOT EXISTS idx_users_unindexed_phone ON users(created_at)
+    WHERE phone_normalized IS NOT NULL AND phone_hash IS NULL;
+CREATE INDEX IF NOT EXISTS idx_user_identities_unindexed_value ON user_identities(created_at)
+    WHERE value_hash IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_user_identities_unindexed_value;
+DROP INDEX IF EXISTS idx_users_unindexed_phone;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+package identifier
+
+import "go-backend/pkg/secure"
+
+// Identifier kinds, keying blind indexes
+const (
+	KindPhone     = "phone"
+	KindInstagram = "instagram"
+	KindSnapchat  = "snapchat"
+)
+
+// BlindIndex returns the keyed hash of a normalized identifier, so it can be matched without keeping the plaintext
+// Crush targets and users' own identifiers share the index, so they can be joined in SQL
+func BlindIndex(kind, normalized string) (string, error) {
+	return secure.KeyedHash("crush_target", kind, normalized)
+}
+
+// OptionalBlindIndex is BlindIndex for an optional identifier; nil identifiers get no index
+func OptionalBlindIndex(kind string, normalized *string) (*string, error) {
+	if normalized == nil {
+		return nil, nil
+	}
+	hash, err := BlindIndex(kind, *normalized)
+	if err != nil {
+		return nil, err
+	}
+	return &hash, nil
+}
@@ -0,0 +1,37 @@
+package identifier
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrInvalidEmail is returned for addresses that cannot be normalized.
+var ErrInvalidEmail = errors.New("invalid email address")
+
+// NormalizeEmail canonicalises an email address for matching.
+// Addresses are trimmed and lowercased; for Gmail, dots and "+tag" suffixes in the local part are dropped
+// and googlemail.com is folded into gmail.com, since they all reach the same mailbox.
+func NormalizeEmail(email string) (string, error) {
+	e := strings.ToLower(strings.TrimSpace(email))
+
+	at := strings.LastIndex(e, "@")
+	if at <= 0 || at == len(e)-1 || strings.ContainsAny(e, " \t") {
+		return "", ErrInvalidEmail
+	}
+	local, domain := e[:at], e[at+1:]
+	if strings.Contains(local, "@") || !strings.Contains(domain, ".") {
+		return "", ErrInvalidEmail
+	}
+
+	if domain == "gmail.com" || domain == "googlemail.com" {
+		domain = "gmail.com"
+		if i := strings.Index(local, "+"); i >= 0 {
+			local = local[:i]
+		}
+		local = strings.ReplaceAll(local, ".", "")
+		if local == "" {
+			return "", ErrInvalidEmail
+		}
+	}
+	return local + "@" + domain, nil
+}
@@ -0,0 +1,89 @@
+package identifier
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizeEmailEquivalence(t *testing.T) {
+	tests := []struct {
+		name   string
+		want   string
+		inputs []string
+	}{
+		{
+			name:   "case and whitespace",
+			want:   "jane.doe@example.com",
+			inputs: []string{"jane.doe@example.com", "Jane.Doe@Example.COM", "  jane.doe@example.com\t"},
+		},
+		{
+			name: "gmail dots, tags and googlemail",
+			want: "janedoe@gmail.com",
+			inputs: []string{
+				"janedoe@gmail.com",
+				"jane.doe@gmail.com",
+				"J.a.n.e.D.o.e@GMail.com",
+				"jane.doe+crushes@gmail.com",
+				"janedoe+a+b@googlemail.com",
+			},
+		},
+		{
+			name:   "tags kept outside gmail",
+			want:   "jane+news@example.com",
+			inputs: []string{"jane+news@example.com", "JANE+NEWS@example.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, input := range tt.inputs {
+				got, err := NormalizeEmail(input)
+				if err != nil {
+					t.Fatalf("NormalizeEmail(%q) returned error: %v", input, err)
+				}
+				if got != tt.want {
+					t.Errorf("NormalizeEmail(%q) = %q, want %q", input, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestNormalizeEmailDistinct(t *testing.T) {
+	// Dots only fold for Gmail; elsewhere they may name different mailboxes
+	a, err := NormalizeEmail("jane.doe@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NormalizeEmail("janedoe@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a == b {
+		t.Errorf("NormalizeEmail folded %q and %q into %q", "jane.doe@example.com", "janedoe@example.com", a)
+	}
+}
+
+func TestNormalizeEmailInvalid(t *testing.T) {
+	inputs := []string{
+		"",
+		"   ",
+		"jane",
+		"@example.com",
+		"jane@",
+		"jane@localhost",
+		"jane doe@example.com",
+		"jane@@example.com",
+		"+tag@gmail.com",
+		"...@gmail.com",
+	}
+
+	for _, input := range inputs {
+		t.Run(input, func(t *testing.T) {
+			got, err := NormalizeEmail(input)
+			if !errors.Is(err, ErrInvalidEmail) {
+				t.Errorf("NormalizeEmail(%q) = %q, %v, want ErrInvalidEmail", input, got, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package identifier
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrInvalidHandle is returned for handles that cannot be normalized.
+var ErrInvalidHandle = errors.New("invalid social handle")
+
+// maxHandleLength bounds handles across platforms (Instagram allows 30, Snapchat 15).
+const maxHandleLength = 30
+
+// handlePathPrefixes are profile URL path segments that precede the handle, e.g. snapchat.com/add/<handle>.
+var handlePathPrefixes = map[string]bool{
+	"add": true,
+	"u":   true,
+}
+
+// NormalizeHandle canonicalises an Instagram or Snapchat handle.
+// Surrounding whitespace, a leading '@' and profile URLs (https://instagram.com/<handle>/?hl=en) are stripped
+// and the result is lowercased, so "@John.Doe", "john.doe" and " john.doe " are equal.
+func NormalizeHandle(handle string) (string, error) {
+	h := strings.ToLower(strings.TrimSpace(handle))
+
+	if i := strings.IndexAny(h, "?#"); i >= 0 {
+		h = h[:i]
+	}
+	if i := strings.Index(h, "://"); i >= 0 {
+		h = h[i+3:]
+	}
+	h = strings.TrimPrefix(h, "www.")
+
+	// Profile URL: take the handle from the path
+	if strings.Contains(h, "/") {
+		segments := strings.Split(h, "/")
+		if !strings.Contains(segments[0], ".") {
+			return "", ErrInvalidHandle
+		}
+		var path []string
+		for _, segment := range segments[1:] {
+			if segment != "" {
+				path = append(path, segment)
+			}
+		}
+		if len(path) > 1 && handlePathPrefixes[path[0]] {
+			path = path[1:]
+		}
+		if len(path) != 1 {
+			return "", ErrInvalidHandle
+		}
+		h = path[0]
+	}
+
+	h = strings.TrimLeft(h, "@")
+	if h == "" || len(h) > maxHandleLength {
+		return "", ErrInvalidHandle
+	}
+	for _, r := range h {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '.' || r == '_' || r == '-') {
+			return "", ErrInvalidHandle
+		}
+	}
+	return h, nil
+}
@@ -0,0 +1,78 @@
+package identifier
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeHandleEquivalence(t *testing.T) {
+	tests := []struct {
+		name   string
+		want   string
+		inputs []string
+	}{
+		{
+			name: "instagram",
+			want: "john.doe",
+			inputs: []string{
+				"john.doe",
+				"@John.Doe",
+				" john.doe ",
+				"@@john.doe",
+				"instagram.com/john.doe",
+				"https://www.instagram.com/John.Doe/",
+				"https://instagram.com/john.doe/?hl=en",
+				"http://instagram.com/john.doe#posts",
+			},
+		},
+		{
+			name: "snapchat",
+			want: "jane_doe-1",
+			inputs: []string{
+				"jane_doe-1",
+				"@Jane_Doe-1",
+				"https://www.snapchat.com/add/jane_doe-1",
+				"snapchat.com/add/JANE_DOE-1/",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, input := range tt.inputs {
+				got, err := NormalizeHandle(input)
+				if err != nil {
+					t.Fatalf("NormalizeHandle(%q) returned error: %v", input, err)
+				}
+				if got != tt.want {
+					t.Errorf("NormalizeHandle(%q) = %q, want %q", input, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestNormalizeHandleInvalid(t *testing.T) {
+	inputs := []string{
+		"",
+		"@",
+		"   ",
+		"john doe",
+		"john/doe",
+		"jöhn",
+		"john!",
+		"https://instagram.com/",
+		"https://instagram.com/john/doe",
+		strings.Repeat("a", maxHandleLength+1),
+	}
+
+	for _, input := range inputs {
+		t.Run(input, func(t *testing.T) {
+			got, err := NormalizeHandle(input)
+			if !errors.Is(err, ErrInvalidHandle) {
+				t.Errorf("NormalizeHandle(%q) = %q, %v, want ErrInvalidHandle", input, got, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package identifier
+
+import "strings"
+
+// maskRune replaces hidden characters of masked identifiers.
+const maskRune = '*'
+
+// MaskPhone hides all but the last two digits of a phone number, keeping its length and separators.
+// "98765 43210" becomes "***** ***10".
+func MaskPhone(phone string) string {
+	runes := []rune(phone)
+	visible := 2
+	for i := len(runes) - 1; i >= 0; i-- {
+		if runes[i] < '0' || runes[i] > '9' {
+			continue
+		}
+		if visible > 0 {
+			visible--
+			continue
+		}
+		runes[i] = maskRune
+	}
+	return string(runes)
+}
+
+// MaskHandle hides all but the first character of a social handle, ignoring a leading '@'.
+// "@john.doe" becomes "@j*******".
+func MaskHandle(handle string) string {
+	handle = strings.TrimSpace(handle)
+	prefix := ""
+	if strings.HasPrefix(handle, "@") {
+		prefix, handle = "@", handle[1:]
+	}
+	runes := []rune(handle)
+	if len(runes) <= 1 {
+		return prefix + strings.Repeat(string(maskRune), len(runes))
+	}
+	return prefix + string(runes[0]) + strings.Repeat(string(maskRune), len(runes)-1)
+}
@@ -0,0 +1,166 @@
+package identifier
+
+import (
+	"errors"
+	"strings"
+)
+
+// Errors returned for phone numbers that cannot be normalized.
+var (
+	ErrInvalidCountryCode = errors.New("invalid country code")
+	ErrInvalidPhone       = errors.New("invalid phone number")
+)
+
+// callingCodes lists the ITU-T E.164 country calling codes assigned to geographic areas.
+// Codes are prefix-free, so a full international number has at most one matching code.
+var callingCodes = map[string]bool{
+	"1": true, "7": true,
+
+	"20": true, "27": true, "30": true, "31": true, "32": true, "33": true, "34": true, "36": true, "39": true,
+	"40": true, "41": true, "43": true, "44": true, "45": true, "46": true, "47": true, "48": true, "49": true,
+	"51": true, "52": true, "53": true, "54": true, "55": true, "56": true, "57": true, "58": true,
+	"60": true, "61": true, "62": true, "63": true, "64": true, "65": true, "66": true,
+	"81": true, "82": true, "84": true, "86": true,
+	"90": true, "91": true, "92": true, "93": true, "94": true, "95": true, "98": true,
+
+	"211": true, "212": true, "213": true, "216": true, "218": true,
+	"220": true, "221": true, "222": true, "223": true, "224": true, "225": true, "226": true, "227": true, "228": true, "229": true,
+	"230": true, "231": true, "232": true, "233": true, "234": true, "235": true, "236": true, "237": true, "238": true, "239": true,
+	"240": true, "241": true, "242": true, "243": true, "244": true, "245": true, "246": true, "247": true, "248": true, "249": true,
+	"250": true, "251": true, "252": true, "253": true, "254": true, "255": true, "256": true, "257": true, "258": true,
+	"260": true, "261": true, "262": true, "263": true, "264": true, "265": true, "266": true, "267": true, "268": true, "269": true,
+	"290": true, "291": true, "297": true, "298": true, "299": true,
+	"350": true, "351": true, "352": true, "353": true, "354": true, "355": true, "356": true, "357": true, "358": true, "359": true,
+	"370": true, "371": true, "372": true, "373": true, "374": true, "375": true, "376": true, "377": true, "378": true, "379": true,
+	"380": true, "381": true, "382": true, "383": true, "385": true, "386": true, "387": true, "389": true,
+	"420": true, "421": true, "423": true,
+	"500": true, "501": true, "502": true, "503": true, "504": true, "505": true, "506": true, "507": true, "508": true, "509": true,
+	"590": true, "591": true, "592": true, "593": true, "594": true, "595": true, "596": true, "597": true, "598": true, "599": true,
+	"670": true, "672": true, "673": true, "674": true, "675": true, "676": true, "677": true, "678": true, "679": true,
+	"680": true, "681": true, "682": true, "683": true, "685": true, "686": true, "687": true, "688": true, "689": true,
+	"690": true, "691": true, "692": true,
+	"850": true, "852": true, "853": true, "855": true, "856": true, "880": true, "886": true,
+	"960": true, "961": true, "962": true, "963": true, "964": true, "965": true, "966": true, "967": true, "968": true,
+	"970": true, "971": true, "972": true, "973": true, "974": true, "975": true, "976": true, "977": true,
+	"992": true, "993": true, "994": true, "995": true, "996": true, "998": true,
+}
+
+// Phone is a phone number split into its calling code and national significant number.
+type Phone struct {
+	CountryCode string // With leading '+', e.g. +91
+	Number      string // Digits only, without trunk prefix
+}
+
+// E164 returns the number in E.164 form, e.g. +919876543210.
+func (p Phone) E164() string {
+	return p.CountryCode + p.Number
+}
+
+// NormalizeCountryCode validates a calling code such as "91", "+91" or "0091" and returns it as "+91".
+func NormalizeCountryCode(countryCode string) (string, error) {
+	digits := stripPhoneSeparators(countryCode)
+	digits = strings.TrimPrefix(digits, "+")
+	if strings.HasPrefix(digits, "00") {
+		digits = digits[2:]
+	}
+	if !isDigits(digits) || !callingCodes[digits] {
+		return "", ErrInvalidCountryCode
+	}
+	return "+" + digits, nil
+}
+
+// ParsePhone parses a phone number into E.164 parts.
+// The number may be national (leading trunk zeros are dropped) or international ("+..." or "00..."),
+// in which case it must agree with countryCode when one is given.
+func ParsePhone(countryCode, phone string) (Phone, error) {
+	number := stripPhoneSeparators(phone)
+
+	// International form carries its own calling code
+	if strings.HasPrefix(number, "+") || strings.HasPrefix(number, "00") {
+		parsed, err := parseInternational(number)
+		if err != nil {
+			return Phone{}, err
+		}
+		if strings.TrimSpace(countryCode) != "" {
+			expected, err := NormalizeCountryCode(countryCode)
+			if err != nil {
+				return Phone{}, err
+			}
+			if expected != parsed.CountryCode {
+				return Phone{}, ErrInvalidPhone
+			}
+		}
+		return parsed, nil
+	}
+
+	cc, err := NormalizeCountryCode(countryCode)
+	if err != nil {
+		return Phone{}, err
+	}
+	number = strings.TrimLeft(number, "0")
+	if !validNationalNumber(cc, number) {
+		return Phone{}, ErrInvalidPhone
+	}
+	return Phone{CountryCode: cc, Number: number}, nil
+}
+
+// NormalizePhone returns the E.164 form of a phone number; see ParsePhone.
+func NormalizePhone(countryCode, phone string) (string, error) {
+	parsed, err := ParsePhone(countryCode, phone)
+	if err != nil {
+		return "", err
+	}
+	return parsed.E164(), nil
+}
+
+// parseInternational splits a "+<cc><number>" or "00<cc><number>" string.
+func parseInternational(number string) (Phone, error) {
+	if strings.HasPrefix(number, "+") {
+		number = number[1:]
+	} else {
+		number = number[2:]
+	}
+	if !isDigits(number) {
+		return Phone{}, ErrInvalidPhone
+	}
+
+	for size := 1; size <= 3 && size < len(number); size++ {
+		if callingCodes[number[:size]] {
+			cc, national := "+"+number[:size], number[size:]
+			if !validNationalNumber(cc, national) {
+				return Phone{}, ErrInvalidPhone
+			}
+			return Phone{CountryCode: cc, Number: national}, nil
+		}
+	}
+	return Phone{}, ErrInvalidCountryCode
+}
+
+// validNationalNumber checks the digit count; E.164 numbers have at most 15 digits including the calling code.
+func validNationalNumber(countryCode, number string) bool {
+	return isDigits(number) && len(number) >= 4 && len(countryCode)-1+len(number) <= 15
+}
+
+// stripPhoneSeparators removes whitespace (including non-breaking spaces) and common separators: - . ( ) /
+func stripPhoneSeparators(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\t', '\n', '\r', '-', '.', '(', ')', '/', '\u00a0':
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(s))
+}
+
+// isDigits reports whether s is a non-empty string of ASCII digits.
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
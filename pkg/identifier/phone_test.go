@@ -0,0 +1,147 @@
+package identifier
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizePhoneEquivalence(t *testing.T) {
+	tests := []struct {
+		name   string
+		want   string
+		inputs [][2]string // country code, phone
+	}{
+		{
+			name: "indian mobile",
+			want: "+919876543210",
+			inputs: [][2]string{
+				{"+91", "9876543210"},
+				{"91", "98765 43210"},
+				{"0091", "098765-43210"},
+				{"+91", "+91 98765 43210"},
+				{"", "+919876543210"},
+				{"", "00919876543210"},
+				{" +91 ", "(98765) 43210"},
+				{"+91", "98765 43210"},
+			},
+		},
+		{
+			name: "us number",
+			want: "+14155550123",
+			inputs: [][2]string{
+				{"+1", "415-555-0123"},
+				{"1", "(415) 555.0123"},
+				{"", "+1 415 555 0123"},
+			},
+		},
+		{
+			name: "uk number with trunk zero",
+			want: "+447911123456",
+			inputs: [][2]string{
+				{"+44", "07911 123456"},
+				{"44", "7911123456"},
+				{"", "+44 7911 123456"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, in := range tt.inputs {
+				got, err := NormalizePhone(in[0], in[1])
+				if err != nil {
+					t.Fatalf("NormalizePhone(%q, %q) returned error: %v", in[0], in[1], err)
+				}
+				if got != tt.want {
+					t.Errorf("NormalizePhone(%q, %q) = %q, want %q", in[0], in[1], got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestNormalizePhoneInvalid(t *testing.T) {
+	tests := []struct {
+		name        string
+		countryCode string
+		phone       string
+		wantErr     error
+	}{
+		{"missing country code", "", "9876543210", ErrInvalidCountryCode},
+		{"unassigned country code", "+999", "9876543210", ErrInvalidCountryCode},
+		{"letters in country code", "+9a", "9876543210", ErrInvalidCountryCode},
+		{"letters in number", "+91", "98765abcde", ErrInvalidPhone},
+		{"too short", "+91", "123", ErrInvalidPhone},
+		{"too long", "+91", "98765432101234", ErrInvalidPhone},
+		{"only trunk zero", "+91", "0", ErrInvalidPhone},
+		{"00 prefix is international", "+91", "0000", ErrInvalidCountryCode},
+		{"conflicting country codes", "+44", "+919876543210", ErrInvalidPhone},
+		{"international without digits", "", "+", ErrInvalidPhone},
+		{"unassigned international code", "", "+999 12345678", ErrInvalidCountryCode},
+		{"empty", "+91", "", ErrInvalidPhone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizePhone(tt.countryCode, tt.phone)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("NormalizePhone(%q, %q) = %q, %v, want error %v", tt.countryCode, tt.phone, got, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParsePhone(t *testing.T) {
+	tests := []struct {
+		name        string
+		countryCode string
+		phone       string
+		want        Phone
+	}{
+		{"national", "91", "098765 43210", Phone{CountryCode: "+91", Number: "9876543210"}},
+		{"international", "", "+7 912 345 67 89", Phone{CountryCode: "+7", Number: "9123456789"}},
+		{"three digit code", "", "+353 85 123 4567", Phone{CountryCode: "+353", Number: "851234567"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePhone(tt.countryCode, tt.phone)
+			if err != nil {
+				t.Fatalf("ParsePhone(%q, %q) returned error: %v", tt.countryCode, tt.phone, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParsePhone(%q, %q) = %+v, want %+v", tt.countryCode, tt.phone, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeCountryCode(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"91", "+91", false},
+		{"+91", "+91", false},
+		{"0091", "+91", false},
+		{" +1 ", "+1", false},
+		{"+353", "+353", false},
+		{"", "", true},
+		{"+", "", true},
+		{"+0", "", true},
+		{"+9999", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := NormalizeCountryCode(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NormalizeCountryCode(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeCountryCode(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package secure
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+)
+
+var (
+	hashKey     []byte
+	hashKeyOnce sync.Once
+	hashKeyErr  error
+)
+
+const hashKeyEnv = "DATA_HASH_KEY"
+
+// getHashKey lazily loads and validates the keyed hash secret from environment variables.
+// The key must be at least 32 bytes long.
+func getHashKey() ([]byte, error) {
+	hashKeyOnce.Do(func() {
+		keyStr := os.Getenv(hashKeyEnv)
+		if keyStr == "" {
+			hashKeyErr = errors.New("DATA_HASH_KEY is not set")
+			return
+		}
+		if len(keyStr) < 32 {
+			hashKeyErr = errors.New("DATA_HASH_KEY must be at least 32 bytes long")
+			return
+		}
+		hashKey = []byte(keyStr)
+	})
+
+	return hashKey, hashKeyErr
+}
+
+// KeyedHash returns the hex-encoded HMAC-SHA256 of the given parts joined with ':'.
+// The output is deterministic, so it can be stored and looked up without keeping the plaintext.
+func KeyedHash(parts ...string) (string, error) {
+	key, err := getHashKey()
+	if err != nil {
+		return "", err
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(strings.Join(parts, ":")))
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// EqualHash compares two hashes in constant time.
+func EqualHash(a, b string) bool {
+	return hmac.Equal([]byte(a), []byte(b))
+}
+
+// RandomDigits returns a uniformly random numeric string of the given length using crypto/rand.
+func RandomDigits(length int) (string, error) {
+	if length <= 0 {
+		return "", errors.New("length must be positive")
+	}
+
+	digits := make([]byte, length)
+	ten := big.NewInt(10)
+	for i := range digits {
+		n, err := rand.Int(rand.Reader, ten)
+		if err != nil {
+			return "", err
+		}
+		digits[i] = byte('0' + n.Int64())
+	}
+	return string(digits), nil
+}
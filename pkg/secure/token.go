@@ -0,0 +1,111 @@
+package secure
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+)
+
+var (
+	tokenSecret     []byte
+	tokenSecretOnce sync.Once
+	tokenSecretErr  error
+)
+
+const tokenSecretEnv = "AUTH_TOKEN_SECRET"
+
+// tokenHeader is the fixed JOSE header for HS256 signed tokens
+var tokenHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// getTokenSecret lazily loads and validates the token signing secret from environment variables.
+// The secret must be at least 32 bytes long.
+func getTokenSecret() ([]byte, error) {
+	tokenSecretOnce.Do(func() {
+		secretStr := os.Getenv(tokenSecretEnv)
+		if secretStr == "" {
+			tokenSecretErr = errors.New("AUTH_TOKEN_SECRET is not set")
+			return
+		}
+		if len(secretStr) < 32 {
+			tokenSecretErr = errors.New("AUTH_TOKEN_SECRET must be at least 32 bytes long")
+			return
+		}
+		tokenSecret = []byte(secretStr)
+	})
+
+	return tokenSecret, tokenSecretErr
+}
+
+// SignToken serialises claims as JSON and returns an HS256 signed compact token (JWT format).
+func SignToken(claims interface{}) (string, error) {
+	secret, err := getTokenSecret()
+	if err != nil {
+		return "", err
+	}
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := tokenHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signingInput))
+	signature := base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+
+	return signingInput + "." + signature, nil
+}
+
+// VerifyToken checks the signature of a token created by SignToken and decodes its claims.
+// Expiry and other claim-level checks are the caller's responsibility.
+func VerifyToken(token string, claims interface{}) error {
+	secret, err := getTokenSecret()
+	if err != nil {
+		return err
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != tokenHeader {
+		return errors.New("malformed token")
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return errors.New("malformed token")
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return errors.New("invalid token signature")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return errors.New("malformed token")
+	}
+
+	return json.Unmarshal(payload, claims)
+}
+
+// GenerateRandomToken returns a URL-safe random string built from numBytes bytes of entropy.
+func GenerateRandomToken(numBytes int) (string, error) {
+	buf := make([]byte, numBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// HashToken returns the hex-encoded SHA-256 digest of a high-entropy token for storage and lookup.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,87 @@
+package secure
+
+import (
+	"encoding/base64"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	// Keys are loaded once per process, so they are set before any test runs
+	os.Setenv(tokenSecretEnv, "test-token-secret-that-is-at-least-32-bytes")
+	os.Setenv(hashKeyEnv, "test-hash-key-that-is-at-least-32-bytes-long")
+	os.Setenv(encryptionKeyEnv, "0123456789abcdef0123456789abcdef")
+	os.Exit(m.Run())
+}
+
+type testClaims struct {
+	Sub string `json:"sub"`
+	Exp int64  `json:"exp"`
+}
+
+func TestSignAndVerifyToken(t *testing.T) {
+	want := testClaims{Sub: "user-1", Exp: 1700000000}
+	token, err := SignToken(want)
+	if err != nil {
+		t.Fatalf("SignToken returned error: %v", err)
+	}
+	if parts := strings.Split(token, "."); len(parts) != 3 || parts[0] != tokenHeader {
+		t.Fatalf("SignToken returned %q, want a compact HS256 token", token)
+	}
+
+	var got testClaims
+	if err := VerifyToken(token, &got); err != nil {
+		t.Fatalf("VerifyToken returned error: %v", err)
+	}
+	if got != want {
+		t.Errorf("VerifyToken decoded %+v, want %+v", got, want)
+	}
+}
+
+func TestVerifyTokenRejectsTampering(t *testing.T) {
+	token, err := SignToken(testClaims{Sub: "user-1", Exp: 1700000000})
+	if err != nil {
+		t.Fatalf("SignToken returned error: %v", err)
+	}
+	parts := strings.Split(token, ".")
+	forgedPayload := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"admin","exp":1700000000}`))
+	noneHeader := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr string
+	}{
+		{"empty", "", "malformed token"},
+		{"two parts", parts[0] + "." + parts[1], "malformed token"},
+		{"four parts", token + ".extra", "malformed token"},
+		{"alg none header", noneHeader + "." + parts[1] + ".", "malformed token"},
+		{"signature not base64", parts[0] + "." + parts[1] + ".!!!", "malformed token"},
+		{"swapped payload", parts[0] + "." + forgedPayload + "." + parts[2], "invalid token signature"},
+		{"stripped signature", parts[0] + "." + parts[1] + ".", "invalid token signature"},
+		{"truncated signature", token[:len(token)-4], "invalid token signature"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var claims testClaims
+			err := VerifyToken(tt.token, &claims)
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("VerifyToken(%q) error = %v, want %q", tt.token, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHashToken(t *testing.T) {
+	if HashToken("a") != HashToken("a") {
+		t.Error("HashToken is not deterministic")
+	}
+	if HashToken("a") == HashToken("b") {
+		t.Error("HashToken returned the same digest for different tokens")
+	}
+	if got := HashToken(""); got != "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855" {
+		t.Errorf("HashToken(\"\") = %q, want the SHA-256 of the empty string", got)
+	}
+}